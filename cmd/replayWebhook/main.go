@@ -0,0 +1,67 @@
+// Command replayWebhook signs a recorded provider payload with the configured
+// webhook secret and posts it to a running server, e.g.:
+//
+//	go run ./cmd/replayWebhook internal/domain/billing/testdata/stripe/customer.subscription.created.json
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/billing"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	url := flag.String("url", fmt.Sprintf("http://localhost:%d/v1/billing/webhooks/stripe", cfg.Server.Port), "webhook endpoint")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: replayWebhook [-url endpoint] fixture.json...")
+		os.Exit(2)
+	}
+	if cfg.Billing.StripeWebhookSecret == "" {
+		fmt.Fprintln(os.Stderr, "BILLING_STRIPE_WEBHOOK_SECRET is not set")
+		os.Exit(1)
+	}
+
+	provider := billing.NewStripeProvider(
+		cfg.Billing.StripeWebhookSecret,
+		time.Duration(cfg.Billing.WebhookToleranceSeconds)*time.Second,
+		cfg.Billing.StripePricePlans,
+	)
+
+	for _, path := range flag.Args() {
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, *url, bytes.NewReader(payload))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(provider.SignatureHeader(), provider.Sign(payload, utils.Now()))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		fmt.Printf("%s -> %d %s\n", path, resp.StatusCode, bytes.TrimSpace(body))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	go_boilerplate "github.com/lkgiovani/go-boilerplate"
@@ -24,6 +26,18 @@ type Config struct {
 	Redis             RedisConfig
 	EmailVerification EmailVerificationConfig
 	PasswordReset     PasswordResetConfig
+	Invitation        InvitationConfig
+	EmailChange       EmailChangeConfig
+	AccountDeletion   AccountDeletionConfig
+	DataExport        DataExportConfig
+	LoginHistory      LoginHistoryConfig
+	UserImport        UserImportConfig
+	UserExport        UserExportConfig
+	PlanLifecycle     PlanLifecycleConfig
+	Billing           BillingConfig
+	Metering          MeteringConfig
+	Session           SessionConfig
+	Introspection     IntrospectionConfig
 	RateLimit         RateLimitConfig
 	Security          SecurityConfig
 }
@@ -91,6 +105,17 @@ type JWTConfig struct {
 	AccessTokenCookieMaxAge  int
 	RefreshTokenCookieMaxAge int
 	RefreshTokenExpiration   int
+	Profiles                 map[string]TokenProfileConfig
+	CLIAPIKeys               []string
+}
+
+type TokenProfileConfig struct {
+	ClientType               string
+	Audience                 string
+	AccessTokenTTLSeconds    int
+	RefreshTokenTTLSeconds   int
+	AccessTokenCookieMaxAge  int
+	RefreshTokenCookieMaxAge int
 }
 
 type AdminConfig struct {
@@ -126,16 +151,96 @@ type PasswordResetConfig struct {
 	ResendCooldownMinutes int
 }
 
+type InvitationConfig struct {
+	TokenExpirationHours int
+}
+
+type EmailChangeConfig struct {
+	TokenExpirationHours int
+}
+
+type AccountDeletionConfig struct {
+	GracePeriodDays      int
+	PurgeIntervalMinutes int
+	PurgeBatchSize       int
+}
+
+type DataExportConfig struct {
+	CooldownHours          int
+	LinkExpirationHours    int
+	CleanupIntervalMinutes int
+}
+
+type LoginHistoryConfig struct {
+	RetentionDays          int
+	CleanupIntervalMinutes int
+}
+
+type UserImportConfig struct {
+	MaxRows   int
+	BatchSize int
+}
+
+type UserExportConfig struct {
+	AsyncThreshold         int
+	LinkExpirationHours    int
+	CleanupIntervalMinutes int
+}
+
+type PlanLifecycleConfig struct {
+	TrialDays             int
+	ReminderDays          int
+	IntervalMinutes       int
+	BatchSize             int
+	CatalogRefreshMinutes int
+}
+
+type BillingConfig struct {
+	StripeWebhookSecret     string
+	StripePricePlans        map[string]string
+	WebhookToleranceSeconds int
+}
+
+type MeteringConfig struct {
+	FlushIntervalSeconds int
+	LimitCacheSeconds    int
+}
+
+type SessionLimitsConfig struct {
+	IdleTimeoutHours     int
+	AbsoluteLifetimeDays int
+}
+
+type SessionConfig struct {
+	Default     SessionLimitsConfig
+	ClientTypes map[string]SessionLimitsConfig
+	Plans       map[string]SessionLimitsConfig
+}
+
+type IntrospectionConfig struct {
+	APIKeys []string
+	Clients map[string]string
+}
+
 type RateLimitConfig struct {
-	Enabled      bool
-	GlobalLimit  int
-	WhitelistIPs []string
+	Enabled       bool
+	Store         string
+	WindowSeconds int
+	GlobalLimit   int
+	AuthLimit     int
+	PlanLimits    map[string]int
+	GroupLimits   map[string]int
+	WhitelistIPs  []string
 }
 
 type SuspiciousConfig struct {
 	WindowMinutes         int
 	MaxRequestsPerWindow  int
 	MassCreationThreshold int
+	InvalidDataThreshold  int
+	ReportWorkers         int
+	ReportQueueSize       int
+	ReportWindowSeconds   int
 }
 
 type AutoBlockConfig struct {
@@ -144,6 +249,8 @@ type AutoBlockConfig struct {
 	TotalCount         int
 	TimeWindowHours    int
 	BlockDurationHours int
+	RefreshSeconds     int
+	CheckSeconds       int
 }
 
 type SecurityConfig struct {
@@ -305,6 +412,45 @@ func loadJWTConfig() JWTConfig {
 		refreshTokenExpiration = 10
 	}
 
+	var cliAPIKeys []string
+	rawCLIAPIKeys, _ := utils.GetString("JWT_CLI_API_KEYS")
+	for _, key := range strings.Split(rawCLIAPIKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			cliAPIKeys = append(cliAPIKeys, key)
+		}
+	}
+
+	profiles := map[string]TokenProfileConfig{
+		"web": loadTokenProfile("JWT_PROFILE_WEB", TokenProfileConfig{
+			ClientType:               "web",
+			Audience:                 audience,
+			AccessTokenTTLSeconds:    expirationMs / 1000,
+			RefreshTokenTTLSeconds:   refreshTokenExpiration * 24 * 60 * 60,
+			AccessTokenCookieMaxAge:  accessTokenCookieMaxAge,
+			RefreshTokenCookieMaxAge: refreshTokenCookieMaxAge,
+		}),
+		"web-remember": loadTokenProfile("JWT_PROFILE_WEB_REMEMBER", TokenProfileConfig{
+			ClientType:               "web",
+			Audience:                 audience,
+			AccessTokenTTLSeconds:    expirationMs / 1000,
+			RefreshTokenTTLSeconds:   30 * 24 * 60 * 60,
+			AccessTokenCookieMaxAge:  accessTokenCookieMaxAge,
+			RefreshTokenCookieMaxAge: 30 * 24 * 60 * 60,
+		}),
+		"mobile": loadTokenProfile("JWT_PROFILE_MOBILE", TokenProfileConfig{
+			ClientType:             "mobile",
+			Audience:               audience,
+			AccessTokenTTLSeconds:  60 * 60,
+			RefreshTokenTTLSeconds: 60 * 24 * 60 * 60,
+		}),
+		"cli": loadTokenProfile("JWT_PROFILE_CLI", TokenProfileConfig{
+			ClientType:             "cli",
+			Audience:               audience,
+			AccessTokenTTLSeconds:  60 * 60,
+			RefreshTokenTTLSeconds: 90 * 24 * 60 * 60,
+		}),
+	}
+
 	return JWTConfig{
 		SecretKey:                secretKey,
 		Issuer:                   issuer,
@@ -314,9 +460,33 @@ func loadJWTConfig() JWTConfig {
 		AccessTokenCookieMaxAge:  accessTokenCookieMaxAge,
 		RefreshTokenCookieMaxAge: refreshTokenCookieMaxAge,
 		RefreshTokenExpiration:   refreshTokenExpiration,
+		Profiles:                 profiles,
+		CLIAPIKeys:               cliAPIKeys,
 	}
 }
 
+func loadTokenProfile(prefix string, defaults TokenProfileConfig) TokenProfileConfig {
+	profile := defaults
+
+	if aud, _ := utils.GetString(prefix + "_AUDIENCE"); aud != "" {
+		profile.Audience = aud
+	}
+	if ttl, _ := utils.GetInt(prefix + "_ACCESS_TTL_SECONDS"); ttl > 0 {
+		profile.AccessTokenTTLSeconds = ttl
+	}
+	if ttl, _ := utils.GetInt(prefix + "_REFRESH_TTL_SECONDS"); ttl > 0 {
+		profile.RefreshTokenTTLSeconds = ttl
+	}
+	if maxAge, _ := utils.GetInt(prefix + "_ACCESS_COOKIE_MAX_AGE"); maxAge > 0 {
+		profile.AccessTokenCookieMaxAge = maxAge
+	}
+	if maxAge, _ := utils.GetInt(prefix + "_REFRESH_COOKIE_MAX_AGE"); maxAge > 0 {
+		profile.RefreshTokenCookieMaxAge = maxAge
+	}
+
+	return profile
+}
+
 func loadAdminConfig() AdminConfig {
 	email, err := utils.GetString("ADMIN_EMAIL")
 	if err != nil {
@@ -500,16 +670,311 @@ func loadPasswordResetConfig() PasswordResetConfig {
 	}
 }
 
+func loadInvitationConfig() InvitationConfig {
+	expiration, _ := utils.GetInt("INVITATION_EXPIRATION_HOURS")
+	if expiration == 0 {
+		expiration = 72
+	}
+
+	return InvitationConfig{
+		TokenExpirationHours: expiration,
+	}
+}
+
+func loadEmailChangeConfig() EmailChangeConfig {
+	expiration, _ := utils.GetInt("EMAIL_CHANGE_EXPIRATION_HOURS")
+	if expiration == 0 {
+		expiration = 24
+	}
+
+	return EmailChangeConfig{
+		TokenExpirationHours: expiration,
+	}
+}
+
+func loadAccountDeletionConfig() AccountDeletionConfig {
+	gracePeriod, _ := utils.GetInt("ACCOUNT_DELETION_GRACE_PERIOD_DAYS")
+	if gracePeriod == 0 {
+		gracePeriod = 30
+	}
+	purgeInterval, _ := utils.GetInt("ACCOUNT_PURGE_INTERVAL_MINUTES")
+	if purgeInterval == 0 {
+		purgeInterval = 60
+	}
+	batchSize, _ := utils.GetInt("ACCOUNT_PURGE_BATCH_SIZE")
+	if batchSize == 0 {
+		batchSize = 100
+	}
+
+	return AccountDeletionConfig{
+		GracePeriodDays:      gracePeriod,
+		PurgeIntervalMinutes: purgeInterval,
+		PurgeBatchSize:       batchSize,
+	}
+}
+
+func loadDataExportConfig() DataExportConfig {
+	cooldown, _ := utils.GetInt("DATA_EXPORT_COOLDOWN_HOURS")
+	if cooldown == 0 {
+		cooldown = 24
+	}
+	linkExpiration, _ := utils.GetInt("DATA_EXPORT_LINK_EXPIRATION_HOURS")
+	if linkExpiration == 0 {
+		linkExpiration = 48
+	}
+	cleanupInterval, _ := utils.GetInt("DATA_EXPORT_CLEANUP_INTERVAL_MINUTES")
+	if cleanupInterval == 0 {
+		cleanupInterval = 60
+	}
+
+	return DataExportConfig{
+		CooldownHours:          cooldown,
+		LinkExpirationHours:    linkExpiration,
+		CleanupIntervalMinutes: cleanupInterval,
+	}
+}
+
+func loadLoginHistoryConfig() LoginHistoryConfig {
+	retention, _ := utils.GetInt("LOGIN_HISTORY_RETENTION_DAYS")
+	if retention == 0 {
+		retention = 90
+	}
+	cleanupInterval, _ := utils.GetInt("LOGIN_HISTORY_CLEANUP_INTERVAL_MINUTES")
+	if cleanupInterval == 0 {
+		cleanupInterval = 60
+	}
+
+	return LoginHistoryConfig{
+		RetentionDays:          retention,
+		CleanupIntervalMinutes: cleanupInterval,
+	}
+}
+
+func loadUserImportConfig() UserImportConfig {
+	maxRows, _ := utils.GetInt("USER_IMPORT_MAX_ROWS")
+	if maxRows == 0 {
+		maxRows = 5000
+	}
+	batchSize, _ := utils.GetInt("USER_IMPORT_BATCH_SIZE")
+	if batchSize == 0 {
+		batchSize = 100
+	}
+
+	return UserImportConfig{
+		MaxRows:   maxRows,
+		BatchSize: batchSize,
+	}
+}
+
+func loadUserExportConfig() UserExportConfig {
+	asyncThreshold, _ := utils.GetInt("USER_EXPORT_ASYNC_THRESHOLD")
+	if asyncThreshold == 0 {
+		asyncThreshold = 5000
+	}
+	linkExpiration, _ := utils.GetInt("USER_EXPORT_LINK_EXPIRATION_HOURS")
+	if linkExpiration == 0 {
+		linkExpiration = 24
+	}
+	cleanupInterval, _ := utils.GetInt("USER_EXPORT_CLEANUP_INTERVAL_MINUTES")
+	if cleanupInterval == 0 {
+		cleanupInterval = 60
+	}
+
+	return UserExportConfig{
+		AsyncThreshold:         asyncThreshold,
+		LinkExpirationHours:    linkExpiration,
+		CleanupIntervalMinutes: cleanupInterval,
+	}
+}
+
+func loadPlanLifecycleConfig() PlanLifecycleConfig {
+	trialDays, _ := utils.GetInt("PLAN_TRIAL_DAYS")
+	if trialDays == 0 {
+		trialDays = 14
+	}
+	reminderDays, _ := utils.GetInt("PLAN_EXPIRATION_REMINDER_DAYS")
+	if reminderDays == 0 {
+		reminderDays = 3
+	}
+	interval, _ := utils.GetInt("PLAN_LIFECYCLE_INTERVAL_MINUTES")
+	if interval == 0 {
+		interval = 60
+	}
+	batchSize, _ := utils.GetInt("PLAN_LIFECYCLE_BATCH_SIZE")
+	if batchSize == 0 {
+		batchSize = 100
+	}
+	catalogRefresh, _ := utils.GetInt("PLAN_CATALOG_REFRESH_MINUTES")
+	if catalogRefresh == 0 {
+		catalogRefresh = 5
+	}
+
+	return PlanLifecycleConfig{
+		TrialDays:             trialDays,
+		ReminderDays:          reminderDays,
+		IntervalMinutes:       interval,
+		BatchSize:             batchSize,
+		CatalogRefreshMinutes: catalogRefresh,
+	}
+}
+
+func loadBillingConfig() BillingConfig {
+	stripeSecret, _ := utils.GetString("BILLING_STRIPE_WEBHOOK_SECRET")
+
+	pricePlans := make(map[string]string)
+	rawPricePlans, _ := utils.GetString("BILLING_STRIPE_PRICE_PLANS")
+	for _, pair := range strings.Split(rawPricePlans, ",") {
+		priceID, plan, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && priceID != "" && plan != "" {
+			pricePlans[priceID] = plan
+		}
+	}
+
+	tolerance, _ := utils.GetInt("BILLING_WEBHOOK_TOLERANCE_SECONDS")
+	if tolerance == 0 {
+		tolerance = 300
+	}
+
+	return BillingConfig{
+		StripeWebhookSecret:     stripeSecret,
+		StripePricePlans:        pricePlans,
+		WebhookToleranceSeconds: tolerance,
+	}
+}
+
+func loadMeteringConfig() MeteringConfig {
+	flushInterval, _ := utils.GetInt("METERING_FLUSH_INTERVAL_SECONDS")
+	if flushInterval == 0 {
+		flushInterval = 30
+	}
+	limitCache, _ := utils.GetInt("METERING_LIMIT_CACHE_SECONDS")
+	if limitCache == 0 {
+		limitCache = 60
+	}
+
+	return MeteringConfig{
+		FlushIntervalSeconds: flushInterval,
+		LimitCacheSeconds:    limitCache,
+	}
+}
+
+func loadSessionConfig() SessionConfig {
+	defaults := loadSessionLimits("SESSION")
+	if defaults.IdleTimeoutHours == 0 {
+		defaults.IdleTimeoutHours = 168
+	}
+	if defaults.AbsoluteLifetimeDays == 0 {
+		defaults.AbsoluteLifetimeDays = 30
+	}
+
+	return SessionConfig{
+		Default: defaults,
+		ClientTypes: map[string]SessionLimitsConfig{
+			"web":    loadSessionLimits("SESSION_WEB"),
+			"mobile": loadSessionLimits("SESSION_MOBILE"),
+			"cli":    loadSessionLimits("SESSION_CLI"),
+		},
+		Plans: map[string]SessionLimitsConfig{
+			"FREE":       loadSessionLimits("SESSION_PLAN_FREE"),
+			"PRO":        loadSessionLimits("SESSION_PLAN_PRO"),
+			"ENTERPRISE": loadSessionLimits("SESSION_PLAN_ENTERPRISE"),
+		},
+	}
+}
+
+func loadSessionLimits(prefix string) SessionLimitsConfig {
+	idleTimeout, _ := utils.GetInt(prefix + "_IDLE_TIMEOUT_HOURS")
+	absoluteLifetime, _ := utils.GetInt(prefix + "_ABSOLUTE_LIFETIME_DAYS")
+
+	return SessionLimitsConfig{
+		IdleTimeoutHours:     idleTimeout,
+		AbsoluteLifetimeDays: absoluteLifetime,
+	}
+}
+
+func loadIntrospectionConfig() IntrospectionConfig {
+	var apiKeys []string
+	rawAPIKeys, _ := utils.GetString("INTROSPECTION_API_KEYS")
+	for _, key := range strings.Split(rawAPIKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+
+	clients := make(map[string]string)
+	rawClients, _ := utils.GetString("INTROSPECTION_CLIENTS")
+	for _, pair := range strings.Split(rawClients, ",") {
+		clientID, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && clientID != "" && secret != "" {
+			clients[clientID] = secret
+		}
+	}
+
+	return IntrospectionConfig{
+		APIKeys: apiKeys,
+		Clients: clients,
+	}
+}
+
 func loadRateLimitConfig() RateLimitConfig {
 	enabled, _ := utils.GetBool("RATE_LIMIT_ENABLED")
+	store, _ := utils.GetString("RATE_LIMIT_STORE")
+	if store == "" {
+		store = "memory"
+	}
+	window, _ := utils.GetInt("RATE_LIMIT_WINDOW_SECONDS")
+	if window == 0 {
+		window = 60
+	}
 	globalLimit, _ := utils.GetInt("RATE_LIMIT_GLOBAL")
 	if globalLimit == 0 {
 		globalLimit = 200
 	}
+	authLimit, _ := utils.GetInt("RATE_LIMIT_AUTH")
+	if authLimit == 0 {
+		authLimit = 10
+	}
+
+	planLimits := map[string]int{
+		"FREE":       60,
+		"PRO":        300,
+		"ENTERPRISE": 1000,
+	}
+	for plan := range planLimits {
+		if limit, _ := utils.GetInt("RATE_LIMIT_PLAN_" + plan); limit > 0 {
+			planLimits[plan] = limit
+		}
+	}
+
+	groupLimits := make(map[string]int)
+	rawGroups, _ := utils.GetString("RATE_LIMIT_GROUPS")
+	for _, pair := range strings.Split(rawGroups, ",") {
+		group, rawLimit, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || group == "" {
+			continue
+		}
+		if limit, err := strconv.Atoi(rawLimit); err == nil && limit > 0 {
+			groupLimits[group] = limit
+		}
+	}
+
+	var whitelist []string
+	rawWhitelist, _ := utils.GetString("RATE_LIMIT_WHITELIST_IPS")
+	for _, ip := range strings.Split(rawWhitelist, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			whitelist = append(whitelist, ip)
+		}
+	}
 
 	return RateLimitConfig{
-		Enabled:     enabled,
-		GlobalLimit: globalLimit,
+		Enabled:       enabled,
+		Store:         store,
+		WindowSeconds: window,
+		GlobalLimit:   globalLimit,
+		AuthLimit:     authLimit,
+		PlanLimits:    planLimits,
+		GroupLimits:   groupLimits,
+		WhitelistIPs:  whitelist,
 	}
 }
 
@@ -526,6 +991,22 @@ func loadSecurityConfig() SecurityConfig {
 	if suspiciousMassCreation == 0 {
 		suspiciousMassCreation = 8
 	}
+	suspiciousInvalidData, _ := utils.GetInt("SECURITY_SUSPICIOUS_INVALID_DATA_THRESHOLD")
+	if suspiciousInvalidData == 0 {
+		suspiciousInvalidData = 20
+	}
+	reportWorkers, _ := utils.GetInt("SECURITY_SUSPICIOUS_REPORT_WORKERS")
+	if reportWorkers == 0 {
+		reportWorkers = 4
+	}
+	reportQueueSize, _ := utils.GetInt("SECURITY_SUSPICIOUS_REPORT_QUEUE_SIZE")
+	if reportQueueSize == 0 {
+		reportQueueSize = 1000
+	}
+	reportWindow, _ := utils.GetInt("SECURITY_SUSPICIOUS_REPORT_WINDOW_SECONDS")
+	if reportWindow == 0 {
+		reportWindow = 10
+	}
 
 	criticalCount, _ := utils.GetInt("SECURITY_AUTO_BLOCK_CRITICAL_COUNT")
 	if criticalCount == 0 {
@@ -547,12 +1028,24 @@ func loadSecurityConfig() SecurityConfig {
 	if blockDuration == 0 {
 		blockDuration = 168
 	}
+	refresh, _ := utils.GetInt("SECURITY_AUTO_BLOCK_REFRESH_SECONDS")
+	if refresh == 0 {
+		refresh = 60
+	}
+	check, _ := utils.GetInt("SECURITY_AUTO_BLOCK_CHECK_SECONDS")
+	if check == 0 {
+		check = 5
+	}
 
 	return SecurityConfig{
 		Suspicious: SuspiciousConfig{
 			WindowMinutes:         suspiciousWindow,
 			MaxRequestsPerWindow:  suspiciousMaxRequests,
 			MassCreationThreshold: suspiciousMassCreation,
+			InvalidDataThreshold:  suspiciousInvalidData,
+			ReportWorkers:         reportWorkers,
+			ReportQueueSize:       reportQueueSize,
+			ReportWindowSeconds:   reportWindow,
 		},
 		AutoBlock: AutoBlockConfig{
 			CriticalCount:      criticalCount,
@@ -560,6 +1053,8 @@ func loadSecurityConfig() SecurityConfig {
 			TotalCount:         totalCount,
 			TimeWindowHours:    timeWindow,
 			BlockDurationHours: blockDuration,
+			RefreshSeconds:     refresh,
+			CheckSeconds:       check,
 		},
 	}
 }
@@ -577,6 +1072,18 @@ func LoadConfig() *Config {
 		Redis:             loadRedisConfig(),
 		EmailVerification: loadEmailVerificationConfig(),
 		PasswordReset:     loadPasswordResetConfig(),
+		Invitation:        loadInvitationConfig(),
+		EmailChange:       loadEmailChangeConfig(),
+		AccountDeletion:   loadAccountDeletionConfig(),
+		DataExport:        loadDataExportConfig(),
+		LoginHistory:      loadLoginHistoryConfig(),
+		UserImport:        loadUserImportConfig(),
+		UserExport:        loadUserExportConfig(),
+		PlanLifecycle:     loadPlanLifecycleConfig(),
+		Billing:           loadBillingConfig(),
+		Metering:          loadMeteringConfig(),
+		Session:           loadSessionConfig(),
+		Introspection:     loadIntrospectionConfig(),
 		RateLimit:         loadRateLimitConfig(),
 		Security:          loadSecurityConfig(),
 	}
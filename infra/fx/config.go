@@ -12,6 +12,7 @@ var configModule = fx.Module(
 		config.LoadConfig,
 		provideLogger,
 		provideJWTConfig,
+		provideSessionConfig,
 	),
 )
 
@@ -19,6 +20,10 @@ func provideJWTConfig(cfg *config.Config) config.JWTConfig {
 	return cfg.JWT
 }
 
+func provideSessionConfig(cfg *config.Config) config.SessionConfig {
+	return cfg.Session
+}
+
 func provideLogger(cfg *config.Config) (logger.Logger, error) {
 	return logger.NewLogger(cfg.Server.Mode, cfg.Server.LogLevel)
 }
@@ -1,12 +1,35 @@
 package fx
 
 import (
+	"context"
+	"time"
+
 	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/accountdeletion"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/billing"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/dataexport"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/invitation"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/loginhistory"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/metering"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/organization"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/passwordRecovery"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/planlifecycle"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/preferences"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/rbac"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/userexport"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/userimport"
 	"github.com/lkgiovani/go-boilerplate/internal/security/googleauth"
 	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 var DomainModule = fx.Module(
@@ -15,8 +38,34 @@ var DomainModule = fx.Module(
 		user.NewUserRepository,
 		user.NewService,
 		user.NewInsertAdminUser,
+		preferences.NewService,
+		rbac.NewGormRepository,
+		rbac.NewService,
+		organization.NewGormRepository,
+		provideOrganizationService,
+		loginhistory.NewGormRepository,
+		provideLoginHistoryService,
 		auth.NewAuthRepository,
+		auth.NewSessionPolicy,
 		auth.NewService,
+		dataexport.NewGormRepository,
+		provideDataExportService,
+		provideAccountDeletionService,
+		userimport.NewGormRepository,
+		provideUserImportService,
+		userexport.NewGormRepository,
+		provideUserExportService,
+		plancatalog.NewGormRepository,
+		providePlanCatalog,
+		func(catalog *plancatalog.Service) user.PlanCatalog { return catalog },
+		planlifecycle.NewGormRepository,
+		providePlanLifecycleService,
+		billing.NewGormRepository,
+		provideBillingService,
+		metering.NewGormRepository,
+		provideMeteringService,
+		security.NewGormRepository,
+		provideSecurityService,
 		jwt.NewJwtService,
 		fx.Annotate(
 			func(cfg *config.Config) *googleauth.GoogleGateway {
@@ -26,3 +75,213 @@ var DomainModule = fx.Module(
 		),
 	),
 )
+
+func provideOrganizationService(
+	repo organization.Repository,
+	userRepo user.UserService,
+	catalog *plancatalog.Service,
+	sender email.EmailSender,
+	cfg *config.Config,
+	logger logger.Logger,
+) *organization.Service {
+	return organization.NewService(
+		repo,
+		userRepo,
+		catalog,
+		sender,
+		cfg.Email.FrontendURL,
+		cfg.Invitation.TokenExpirationHours,
+		logger,
+	)
+}
+
+func provideLoginHistoryService(
+	repo loginhistory.Repository,
+	userRepo user.UserService,
+	cfg *config.Config,
+	logger logger.Logger,
+) *loginhistory.Service {
+	return loginhistory.NewService(repo, userRepo, cfg.LoginHistory.RetentionDays, logger)
+}
+
+func provideAccountDeletionService(
+	userRepo user.UserService,
+	authRepo auth.Repository,
+	exportService *dataexport.Service,
+	storageService *storage.Service,
+	cfg *config.Config,
+	logger logger.Logger,
+) *accountdeletion.Service {
+	return accountdeletion.NewService(
+		userRepo,
+		authRepo,
+		exportService,
+		storageService,
+		cfg.AccountDeletion.GracePeriodDays,
+		cfg.AccountDeletion.PurgeBatchSize,
+		logger,
+	)
+}
+
+func provideDataExportService(
+	repo dataexport.Repository,
+	userRepo user.UserService,
+	authRepo auth.Repository,
+	verificationRepo emailverification.Repository,
+	resetRepo passwordRecovery.Repository,
+	storageService *storage.Service,
+	sender email.EmailSender,
+	cfg *config.Config,
+	logger logger.Logger,
+) *dataexport.Service {
+	return dataexport.NewService(
+		repo,
+		userRepo,
+		authRepo,
+		verificationRepo,
+		resetRepo,
+		storageService,
+		sender,
+		cfg.DataExport.CooldownHours,
+		cfg.DataExport.LinkExpirationHours,
+		logger,
+	)
+}
+
+func provideUserImportService(
+	repo userimport.Repository,
+	userRepo user.UserService,
+	catalog *plancatalog.Service,
+	invitationService *invitation.Service,
+	cfg *config.Config,
+	logger logger.Logger,
+) *userimport.Service {
+	return userimport.NewService(
+		repo,
+		userRepo,
+		catalog,
+		invitationService,
+		cfg.UserImport.MaxRows,
+		cfg.UserImport.BatchSize,
+		logger,
+	)
+}
+
+func provideUserExportService(
+	repo userexport.Repository,
+	userRepo user.UserService,
+	storageService *storage.Service,
+	sender email.EmailSender,
+	cfg *config.Config,
+	logger logger.Logger,
+) *userexport.Service {
+	return userexport.NewService(
+		repo,
+		userRepo,
+		storageService,
+		sender,
+		cfg.UserExport.AsyncThreshold,
+		cfg.UserExport.LinkExpirationHours,
+		logger,
+	)
+}
+
+// providePlanCatalog loads the catalog before anything that creates users or
+// checks plans is built, so a missing catalog stops the startup.
+func providePlanCatalog(
+	repo plancatalog.Repository,
+	userRepo user.UserService,
+	cfg *config.Config,
+	logger logger.Logger,
+) (*plancatalog.Service, error) {
+	catalog := plancatalog.NewService(repo, userRepo, cfg.PlanLifecycle.BatchSize, logger)
+	if err := catalog.Load(context.Background()); err != nil {
+		logger.Error("Failed to load plan catalog", zap.Error(err))
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func providePlanLifecycleService(
+	repo planlifecycle.Repository,
+	userRepo user.UserService,
+	catalog *plancatalog.Service,
+	sender email.EmailSender,
+	cfg *config.Config,
+	logger logger.Logger,
+) *planlifecycle.Service {
+	return planlifecycle.NewService(
+		repo,
+		userRepo,
+		catalog,
+		sender,
+		cfg.PlanLifecycle.TrialDays,
+		cfg.PlanLifecycle.ReminderDays,
+		cfg.PlanLifecycle.BatchSize,
+		logger,
+	)
+}
+
+func provideBillingService(
+	repo billing.Repository,
+	lifecycle *planlifecycle.Service,
+	catalog *plancatalog.Service,
+	cfg *config.Config,
+	logger logger.Logger,
+) *billing.Service {
+	var providers []billing.Provider
+	if cfg.Billing.StripeWebhookSecret != "" {
+		providers = append(providers, billing.NewStripeProvider(
+			cfg.Billing.StripeWebhookSecret,
+			time.Duration(cfg.Billing.WebhookToleranceSeconds)*time.Second,
+			cfg.Billing.StripePricePlans,
+		))
+	}
+
+	return billing.NewService(repo, lifecycle, catalog, providers, logger)
+}
+
+func provideSecurityService(
+	repo security.Repository,
+	userRepo user.UserService,
+	authRepo auth.Repository,
+	cfg *config.Config,
+	logger logger.Logger,
+) (*security.Service, error) {
+	autoBlock := cfg.Security.AutoBlock
+	service := security.NewService(
+		repo,
+		userRepo,
+		authRepo,
+		security.Rules{
+			CriticalCount: autoBlock.CriticalCount,
+			HighCount:     autoBlock.HighCount,
+			TotalCount:    autoBlock.TotalCount,
+			Window:        time.Duration(autoBlock.TimeWindowHours) * time.Hour,
+			BlockDuration: time.Duration(autoBlock.BlockDurationHours) * time.Hour,
+		},
+		time.Duration(cfg.Security.Suspicious.WindowMinutes)*time.Minute,
+		cfg.Security.Suspicious.MaxRequestsPerWindow,
+		time.Duration(autoBlock.CheckSeconds)*time.Second,
+		security.ReportQueue{
+			Workers: cfg.Security.Suspicious.ReportWorkers,
+			Size:    cfg.Security.Suspicious.ReportQueueSize,
+			Window:  time.Duration(cfg.Security.Suspicious.ReportWindowSeconds) * time.Second,
+		},
+		logger,
+	)
+	if err := service.Refresh(context.Background()); err != nil {
+		logger.Error("Failed to load security blocks", zap.Error(err))
+		return nil, err
+	}
+	return service, nil
+}
+
+func provideMeteringService(
+	repo metering.Repository,
+	userRepo user.UserService,
+	cfg *config.Config,
+	logger logger.Logger,
+) *metering.Service {
+	return metering.NewService(repo, userRepo, time.Duration(cfg.Metering.LimitCacheSeconds)*time.Second, logger)
+}
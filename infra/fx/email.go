@@ -3,8 +3,11 @@ package fx
 import (
 	"github.com/lkgiovani/go-boilerplate/infra/config"
 	"github.com/lkgiovani/go-boilerplate/internal/delivery"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/emailchange"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/invitation"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/passwordRecovery"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/pkg/logger"
@@ -19,9 +22,15 @@ var EmailModule = fx.Module(
 		provideEmailVerificationRepository,
 		provideEmailVerificationService,
 		delivery.NewEmailVerificationHandler,
+		provideEmailChangeRepository,
+		provideEmailChangeService,
+		delivery.NewEmailChangeHandler,
 		providePasswordRecoveryRepository,
 		providePasswordRecoveryService,
 		delivery.NewPasswordRecoveryHandler,
+		provideInvitationRepository,
+		provideInvitationService,
+		delivery.NewInvitationHandler,
 	),
 )
 
@@ -77,6 +86,29 @@ func provideEmailVerificationService(
 	)
 }
 
+func provideEmailChangeRepository(db *gorm.DB) emailchange.Repository {
+	return emailchange.NewGormRepository(db)
+}
+
+func provideEmailChangeService(
+	repo emailchange.Repository,
+	userRepo user.UserService,
+	authRepo auth.Repository,
+	sender email.EmailSender,
+	cfg *config.Config,
+	logger logger.Logger,
+) *emailchange.Service {
+	return emailchange.NewService(
+		repo,
+		userRepo,
+		authRepo,
+		sender,
+		cfg.Email.FrontendURL,
+		cfg.EmailChange.TokenExpirationHours,
+		logger,
+	)
+}
+
 func providePasswordRecoveryRepository(db *gorm.DB) passwordRecovery.Repository {
 	return passwordRecovery.NewGormRepository(db)
 }
@@ -98,3 +130,24 @@ func providePasswordRecoveryService(
 		logger,
 	)
 }
+
+func provideInvitationRepository(db *gorm.DB) invitation.Repository {
+	return invitation.NewGormRepository(db)
+}
+
+func provideInvitationService(
+	repo invitation.Repository,
+	userRepo user.UserService,
+	sender email.EmailSender,
+	cfg *config.Config,
+	logger logger.Logger,
+) *invitation.Service {
+	return invitation.NewService(
+		repo,
+		userRepo,
+		sender,
+		cfg.Email.FrontendURL,
+		cfg.Invitation.TokenExpirationHours,
+		logger,
+	)
+}
@@ -2,12 +2,16 @@ package fx
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/lkgiovani/go-boilerplate/infra/config"
 	"github.com/lkgiovani/go-boilerplate/infra/database"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/organization"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/security/ratelimit"
 	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -17,6 +21,7 @@ var infraModule = fx.Module(
 	"infra",
 	fx.Provide(
 		NewDatabase,
+		NewRateLimitStore,
 	),
 	fx.Invoke(
 		InitializeAdminUser,
@@ -31,6 +36,11 @@ func NewDatabase(lc fx.Lifecycle, cfg *config.Config, log logger.Logger) (*gorm.
 
 	db := database.GetDB()
 
+	if err := organization.RegisterTenantCallbacks(db); err != nil {
+		log.Error("Failed to register tenant callbacks", zap.Error(err))
+		return nil, err
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err == nil {
@@ -59,6 +69,46 @@ func NewDatabase(lc fx.Lifecycle, cfg *config.Config, log logger.Logger) (*gorm.
 	return db, nil
 }
 
+// NewRateLimitStore keeps the rate limit buckets in memory unless
+// RATE_LIMIT_STORE=redis, in which case they are shared by all instances.
+func NewRateLimitStore(lc fx.Lifecycle, cfg *config.Config, log logger.Logger) (ratelimit.Store, error) {
+	if cfg.RateLimit.Store == "memory" {
+		return ratelimit.NewMemoryStore(), nil
+	}
+	if cfg.RateLimit.Store != "redis" {
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+
+	timeout := time.Duration(cfg.Redis.Timeout) * time.Millisecond
+	client := redis.NewClient(&redis.Options{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password:     cfg.Redis.Password,
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+		PoolSize:     cfg.Redis.MaxActive,
+		MaxIdleConns: cfg.Redis.MaxIdle,
+		MinIdleConns: cfg.Redis.MinIdle,
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := client.Ping(ctx).Err(); err != nil {
+				log.Error("Failed to connect to Redis", zap.Error(err))
+				return err
+			}
+			log.Info("Redis connected successfully")
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("Closing Redis connection...")
+			return client.Close()
+		},
+	})
+
+	return ratelimit.NewRedisStore(client), nil
+}
+
 func InitializeAdminUser(lc fx.Lifecycle, insertAdminUser *user.InsertAdminUser, log logger.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -0,0 +1,172 @@
+package fx
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/accountdeletion"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/dataexport"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/loginhistory"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/metering"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/planlifecycle"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/userexport"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/userimport"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var JobsModule = fx.Module("jobs",
+	fx.Invoke(
+		StartAccountPurgeJob,
+		StartDataExportCleanupJob,
+		StartLoginHistoryCleanupJob,
+		StartUserExportCleanupJob,
+		StartUserExportRecoveryJob,
+		StartUserImportRecoveryJob,
+		StartPlanLifecycleJob,
+		StartPlanCatalogRefreshJob,
+		StartMeteringFlushJob,
+		StartSecurityBlockRefreshJob,
+		StartSecurityReportWorkers,
+	),
+)
+
+func StartAccountPurgeJob(lc fx.Lifecycle, cfg *config.Config, service *accountdeletion.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "account-purge", time.Duration(cfg.AccountDeletion.PurgeIntervalMinutes)*time.Minute, func(ctx context.Context) error {
+		_, err := service.PurgeExpired(ctx)
+		return err
+	})
+}
+
+func StartDataExportCleanupJob(lc fx.Lifecycle, cfg *config.Config, service *dataexport.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "data-export-cleanup", time.Duration(cfg.DataExport.CleanupIntervalMinutes)*time.Minute, func(ctx context.Context) error {
+		_, err := service.CleanupExpired(ctx)
+		return err
+	})
+}
+
+func StartLoginHistoryCleanupJob(lc fx.Lifecycle, cfg *config.Config, service *loginhistory.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "login-history-cleanup", time.Duration(cfg.LoginHistory.CleanupIntervalMinutes)*time.Minute, func(ctx context.Context) error {
+		_, err := service.CleanupExpired(ctx)
+		return err
+	})
+}
+
+func StartUserExportCleanupJob(lc fx.Lifecycle, cfg *config.Config, service *userexport.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "user-export-cleanup", time.Duration(cfg.UserExport.CleanupIntervalMinutes)*time.Minute, func(ctx context.Context) error {
+		_, err := service.CleanupExpired(ctx)
+		return err
+	})
+}
+
+// StartUserExportRecoveryJob fails the exports left unfinished by a restart,
+// once on startup and then periodically for those of other instances.
+func StartUserExportRecoveryJob(lc fx.Lifecycle, service *userexport.Service, log logger.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if _, err := service.FailInterrupted(ctx); err != nil {
+				log.Error("Failed to recover interrupted user exports", zap.Error(err))
+			}
+			return nil
+		},
+	})
+	startPeriodicJob(lc, log, "user-export-recovery", 5*time.Minute, func(ctx context.Context) error {
+		_, err := service.FailInterrupted(ctx)
+		return err
+	})
+}
+
+// StartUserImportRecoveryJob fails the imports left unfinished by a restart,
+// once on startup and then periodically for those of other instances.
+func StartUserImportRecoveryJob(lc fx.Lifecycle, service *userimport.Service, log logger.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if _, err := service.FailInterrupted(ctx); err != nil {
+				log.Error("Failed to recover interrupted user imports", zap.Error(err))
+			}
+			return nil
+		},
+	})
+	startPeriodicJob(lc, log, "user-import-recovery", 5*time.Minute, func(ctx context.Context) error {
+		_, err := service.FailInterrupted(ctx)
+		return err
+	})
+}
+
+func StartPlanLifecycleJob(lc fx.Lifecycle, cfg *config.Config, service *planlifecycle.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "plan-lifecycle", time.Duration(cfg.PlanLifecycle.IntervalMinutes)*time.Minute, service.Run)
+}
+
+// StartPlanCatalogRefreshJob picks up catalog changes made through other
+// instances.
+func StartPlanCatalogRefreshJob(lc fx.Lifecycle, cfg *config.Config, catalog *plancatalog.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "plan-catalog-refresh", time.Duration(cfg.PlanLifecycle.CatalogRefreshMinutes)*time.Minute, catalog.Load)
+}
+
+// StartMeteringFlushJob writes the buffered usage counters periodically and
+// once more on shutdown, so deploys do not lose usage.
+func StartMeteringFlushJob(lc fx.Lifecycle, cfg *config.Config, meteringService *metering.Service, log logger.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: meteringService.Flush,
+	})
+	startPeriodicJob(lc, log, "metering-flush", time.Duration(cfg.Metering.FlushIntervalSeconds)*time.Second, meteringService.Flush)
+}
+
+// StartSecurityBlockRefreshJob lifts expired blocks and picks up the blocks
+// created by other instances.
+func StartSecurityBlockRefreshJob(lc fx.Lifecycle, cfg *config.Config, securityService *security.Service, log logger.Logger) {
+	startPeriodicJob(lc, log, "security-block-refresh", time.Duration(cfg.Security.AutoBlock.RefreshSeconds)*time.Second, securityService.Refresh)
+}
+
+// StartSecurityReportWorkers records the reported suspicious activities and
+// drains the queue on shutdown.
+func StartSecurityReportWorkers(lc fx.Lifecycle, securityService *security.Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			securityService.StartWorkers()
+			return nil
+		},
+		OnStop: securityService.StopWorkers,
+	})
+}
+
+func startPeriodicJob(lc fx.Lifecycle, log logger.Logger, name string, interval time.Duration, run func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := run(ctx); err != nil {
+							log.Error("Background job failed", zap.String("job", name), zap.Error(err))
+						}
+					}
+				}
+			}()
+			log.Info("Background job scheduled", zap.String("job", name), zap.Duration("interval", interval))
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
@@ -11,5 +11,6 @@ var AppModule = fx.Options(
 	EmailModule,
 	StorageModule,
 	RoutesModule,
+	JobsModule,
 	ServerModule,
 )
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/lkgiovani/go-boilerplate/infra/config"
 	"github.com/lkgiovani/go-boilerplate/internal/delivery"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/rbac"
 	"github.com/lkgiovani/go-boilerplate/internal/security/middleware"
 	"github.com/lkgiovani/go-boilerplate/pkg/logger"
 	"go.uber.org/fx"
@@ -15,7 +16,23 @@ var RoutesModule = fx.Module("routes",
 		delivery.NewErrorHandler,
 		delivery.NewDocsHandler,
 		middleware.NewAuthMiddleware,
+		middleware.NewClientAuthMiddleware,
 		delivery.NewMobileAuthHandler,
+		delivery.NewRoleHandler,
+		delivery.NewOrganizationHandler,
+		delivery.NewAccountDeletionHandler,
+		delivery.NewDataExportHandler,
+		delivery.NewUserImportHandler,
+		delivery.NewUserExportHandler,
+		delivery.NewPlanHandler,
+		delivery.NewBillingHandler,
+		delivery.NewUsageHandler,
+		delivery.NewPreferencesHandler,
+		delivery.NewSecurityHandler,
+		delivery.NewLoginHistoryHandler,
+		middleware.NewQuotaMiddleware,
+		middleware.NewRateLimitMiddleware,
+		middleware.NewSecurityMiddleware,
 	),
 
 	fx.Invoke(
@@ -31,12 +48,19 @@ func setupRoutes(
 	handler *delivery.Handler,
 	docsHandler *delivery.DocsHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	clientAuthMiddleware *middleware.ClientAuthMiddleware,
+	quotaMiddleware *middleware.QuotaMiddleware,
+	rateLimitMiddleware *middleware.RateLimitMiddleware,
+	securityMiddleware *middleware.SecurityMiddleware,
 
 	logger logger.Logger,
 ) {
 	// Health check
 	router.Get("/health", handler.HealthCheckHandler)
 
+	// Rate limit per client IP for every route registered below
+	router.Use(rateLimitMiddleware.Global)
+
 	// Documentation routes
 	docs := router.Group("/docs")
 	docs.Get("/", docsHandler.DocsIndex)
@@ -50,69 +74,178 @@ func setupRoutes(
 
 	// Auth routes (public)
 	auth := v1.Group("/auth")
-	auth.Post("/login", handler.Login)
+	auth.Post("/login", rateLimitMiddleware.ByIP("login"), handler.Login)
 	auth.Post("/refresh", handler.Refresh)
 	auth.Post("/logout", handler.Logout)
 	auth.Post("/logout-all", authMiddleware.Authenticate, handler.LogoutAll) // Requires authentication
-	auth.Post("/signup", handler.Signup)
+	auth.Post("/signup", rateLimitMiddleware.ByIP("signup"), handler.Signup)
+	auth.Post("/introspect", clientAuthMiddleware.Authenticate, handler.Introspect)                          // Requires client credentials or API key
+	auth.Post("/password/change", authMiddleware.AuthenticatePasswordChange, handler.ChangeRequiredPassword) // Requires password change session
 
 	// Mobile Auth routes
 	mobileAuth := auth.Group("/mobile")
-	mobileAuth.Post("/oauth2/google", handler.MobileAuthHandler.AuthenticateWithGoogleMobile)
+	mobileAuth.Post("/oauth2/google", rateLimitMiddleware.ByIP("login"), handler.MobileAuthHandler.AuthenticateWithGoogleMobile)
 	mobileAuth.Post("/refresh", handler.MobileAuthHandler.RefreshMobileToken)
 
 	// Public routes
 	publicUsers := v1.Group("/users/public")
-	publicUsers.Post("/signup", handler.SignupUser)
-	publicUsers.Post("/resend-verification", handler.ResendVerification)
+	publicUsers.Post("/signup", rateLimitMiddleware.ByIP("signup"), handler.SignupUser)
+	publicUsers.Post("/resend-verification", rateLimitMiddleware.ByIP("verification"), handler.ResendVerification)
+
+	// Invitation routes (public)
+	invitations := v1.Group("/invitations")
+	invitations.Post("/verify", handler.InvitationHandler.VerifyInvitation)
+	invitations.Get("/verify", handler.InvitationHandler.VerifyInvitationByQuery)
+	invitations.Post("/accept", handler.InvitationHandler.AcceptInvitation)
 
 	// Upload routes
 	uploads := v1.Group("/uploads")
 	uploads.Use(authMiddleware.Authenticate)
+	uploads.Use(securityMiddleware.Monitor)
+	uploads.Use(rateLimitMiddleware.ByUser("uploads"))
+	uploads.Use(quotaMiddleware.MeterRequests)
 	uploads.Use(authMiddleware.RequireMinPlan("PRO")) // Only PRO patients or higher can upload files directly
-	uploads.Post("/images", handler.UploadHandler.GetUploadUrl)
+	uploads.Post("/images", quotaMiddleware.MeterResources, securityMiddleware.TrackCreations, handler.UploadHandler.GetUploadUrl)
 
 	// Email Verification routes
 	emailVer := v1.Group("/email-verification")
 	emailVer.Post("/send", authMiddleware.Authenticate, handler.EmailVerificationHandler.SendVerificationEmail)
 	emailVer.Post("/verify", handler.EmailVerificationHandler.VerifyEmail)
 	emailVer.Get("/verify", handler.EmailVerificationHandler.VerifyEmailByQuery)
-	emailVer.Post("/resend", handler.EmailVerificationHandler.ResendVerificationEmail)
+	emailVer.Post("/resend", rateLimitMiddleware.ByIP("verification"), handler.EmailVerificationHandler.ResendVerificationEmail)
+
+	// Email Change routes (links sent by email)
+	emailChange := v1.Group("/email-change")
+	emailChange.Post("/confirm", handler.EmailChangeHandler.ConfirmEmailChange)
+	emailChange.Get("/confirm", handler.EmailChangeHandler.ConfirmEmailChangeByQuery)
+	emailChange.Post("/cancel", handler.EmailChangeHandler.CancelEmailChange)
+	emailChange.Get("/cancel", handler.EmailChangeHandler.CancelEmailChangeByQuery)
 
 	// Password Recovery routes
 	passRecovery := v1.Group("/password-recovery")
+	passRecovery.Use(rateLimitMiddleware.ByIP("password-recovery"))
 	passRecovery.Post("/request", handler.PasswordRecoveryHandler.RequestPasswordRecovery)
 	passRecovery.Post("/verify", handler.PasswordRecoveryHandler.VerifyPasswordRecovery)
 	passRecovery.Get("/verify", handler.PasswordRecoveryHandler.VerifyPasswordRecoveryByQuery)
 	passRecovery.Post("/reset", handler.PasswordRecoveryHandler.ResetPassword)
 
+	// Billing webhooks (public, authenticated by the provider signature)
+	billing := v1.Group("/billing")
+	billing.Post("/webhooks/:provider", handler.BillingHandler.HandleWebhook)
+
 	// User routes (require authentication)
 	users := v1.Group("/users")
 	users.Use(authMiddleware.Authenticate) // Apply authentication middleware to all user routes
+	users.Use(securityMiddleware.Monitor)
+	users.Use(rateLimitMiddleware.ByUser("users"))
+	users.Get("/me/usage", handler.UsageHandler.GetMyUsage) // Registered before the quota so it still answers once the quota is exhausted
+	users.Use(quotaMiddleware.MeterRequests)
 
 	// Authenticated user routes
 	users.Get("/me", handler.GetCurrentUser)
-	users.Put("/", authMiddleware.RequireWriteAccess, handler.UpdateUser)
-	users.Patch("/password", authMiddleware.RequireWriteAccess, handler.UpdatePassword)
-	users.Patch("/add-image", authMiddleware.RequireWriteAccess, handler.AddImage)
-
-	// Admin routes (require admin role)
+	users.Delete("/me", handler.AccountDeletionHandler.DeleteCurrentUser)
+	users.Post("/me/export", handler.DataExportHandler.RequestExport) // Data portability (LGPD/GDPR) is a right of every account, so it is not gated by the "export" feature
+	users.Get("/me/export", handler.DataExportHandler.GetLatestExport)
+	users.Post("/me/trial", authMiddleware.RequireWriteAccess, handler.PlanHandler.StartTrial)
+	users.Get("/me/preferences", handler.PreferencesHandler.GetPreferences)
+	users.Get("/me/activity", handler.LoginHistoryHandler.GetMyActivity)
+	users.Patch("/me/preferences", authMiddleware.RequireWriteAccess, middleware.RequireOwnIfMatch, handler.PreferencesHandler.UpdatePreferences)
+	users.Get("/me/email", handler.EmailChangeHandler.GetPendingEmailChange)
+	users.Post("/me/email", authMiddleware.RequireWriteAccess, handler.EmailChangeHandler.RequestEmailChange)
+	users.Delete("/me/email", handler.EmailChangeHandler.CancelPendingEmailChange)
+	users.Put("/", authMiddleware.RequireWriteAccess, middleware.RequireOwnIfMatch, handler.UpdateUser)
+	users.Patch("/password", authMiddleware.RequireWriteAccess, middleware.RequireOwnIfMatch, handler.UpdatePassword)
+	users.Patch("/add-image", authMiddleware.RequireWriteAccess, handler.AddImage) // Only signs an upload URL; the image is saved through PUT /, which checks If-Match
+
+	// Admin routes (require fine-grained permissions)
 	adminUsers := users.Group("")
-	adminUsers.Use(authMiddleware.RequireAdmin) // Apply admin authorization middleware
-
-	adminUsers.Post("/", handler.SaveUser)                            // Create user (admin)
-	adminUsers.Get("/", handler.FindAllUsers)                         // List all users (admin)
-	adminUsers.Get("/:id", handler.FindUserByID)                      // Get user by ID (admin)
-	adminUsers.Get("/email/:email", handler.FindUserByEmail)          // Get user by email (admin)
-	adminUsers.Put("/:id", handler.UpdateUserAdmin)                   // Update user (admin)
-	adminUsers.Delete("/:id", handler.DeleteUserByID)                 // Delete user (admin)
-	adminUsers.Delete("/", handler.DeleteUsersByIDs)                  // Delete multiple users (admin)
-	adminUsers.Patch("/:userId/status", handler.ToggleUserStatus)     // Toggle user status (admin)
-	adminUsers.Patch("/:id/password", handler.UpdatePasswordAdmin)    // Update user password (admin)
-	adminUsers.Patch("/:id/access-mode", handler.UpdateAccessMode)    // Update access mode (admin)
-	adminUsers.Patch("/:id/features", handler.UpdateFeatures)         // Update features (admin)
-	adminUsers.Patch("/:id/limits", handler.UpdateLimits)             // Update limits (admin)
-	adminUsers.Patch("/:id/lifetime-pro", handler.GrantLifetimePro)   // Grant lifetime pro (admin)
-	adminUsers.Post("/:id/ensure-metadata", handler.EnsureMetadata)   // Ensure metadata (admin)
-	adminUsers.Delete("/:id/lifetime-pro", handler.RevokeLifetimePro) // Revoke lifetime pro (admin)
+
+	adminUsers.Post("/", authMiddleware.RequirePermission(rbac.PermUsersCreate), handler.SaveUser)                                                                            // Create user
+	adminUsers.Post("/force-password-change", authMiddleware.RequirePermission(rbac.PermUsersCredentials), handler.ForcePasswordChangeBulk)                                   // Force password change for many users
+	adminUsers.Get("/", authMiddleware.RequirePermission(rbac.PermUsersRead), handler.FindAllUsers)                                                                           // List all users
+	adminUsers.Post("/imports", authMiddleware.RequirePermission(rbac.PermUsersImport), handler.UserImportHandler.StartImport)                                                // Import users from CSV/JSON lines
+	adminUsers.Get("/imports/:importId", authMiddleware.RequirePermission(rbac.PermUsersImport), handler.UserImportHandler.GetImport)                                         // Get import progress
+	adminUsers.Get("/export", authMiddleware.RequirePermission(rbac.PermUsersExport), handler.UserExportHandler.ExportUsers)                                                  // Export users to CSV/XLSX
+	adminUsers.Get("/exports/:exportId", authMiddleware.RequirePermission(rbac.PermUsersExport), handler.UserExportHandler.GetExport)                                         // Get export status and download link
+	adminUsers.Get("/usage", authMiddleware.RequirePermission(rbac.PermUsersRead), handler.UsageHandler.GetUsageReport)                                                       // Usage report per billing period
+	adminUsers.Get("/deleted", authMiddleware.RequirePermission(rbac.PermUsersRead), handler.AccountDeletionHandler.FindDeletedUsers)                                         // List deleted users
+	adminUsers.Get("/:id", authMiddleware.RequirePermission(rbac.PermUsersRead), handler.FindUserByID)                                                                        // Get user by ID
+	adminUsers.Get("/email/:email", authMiddleware.RequirePermission(rbac.PermUsersRead), handler.FindUserByEmail)                                                            // Get user by email
+	adminUsers.Put("/:id", authMiddleware.RequirePermission(rbac.PermUsersUpdate), middleware.RequireIfMatch("id"), handler.UpdateUserAdmin)                                  // Update user
+	adminUsers.Delete("/:id", authMiddleware.RequirePermission(rbac.PermUsersDelete), middleware.RequireIfMatch("id"), handler.DeleteUserByID)                                // Delete user
+	adminUsers.Delete("/", authMiddleware.RequirePermission(rbac.PermUsersDelete), handler.DeleteUsersByIDs)                                                                  // Delete multiple users; no If-Match, since one ETag cannot describe several users
+	adminUsers.Post("/:id/restore", authMiddleware.RequirePermission(rbac.PermUsersDelete), middleware.RequireIfMatch("id"), handler.AccountDeletionHandler.RestoreUser)      // Restore deleted user
+	adminUsers.Patch("/:userId/status", authMiddleware.RequirePermission(rbac.PermUsersStatus), middleware.RequireIfMatch("userId"), handler.ToggleUserStatus)                // Toggle user status
+	adminUsers.Patch("/:id/password", authMiddleware.RequirePermission(rbac.PermUsersCredentials), middleware.RequireIfMatch("id"), handler.UpdatePasswordAdmin)              // Update user password
+	adminUsers.Patch("/:id/force-password-change", authMiddleware.RequirePermission(rbac.PermUsersCredentials), middleware.RequireIfMatch("id"), handler.ForcePasswordChange) // Force password change
+	adminUsers.Patch("/:id/access-mode", authMiddleware.RequirePermission(rbac.PermUsersStatus), middleware.RequireIfMatch("id"), handler.UpdateAccessMode)                   // Update access mode
+	adminUsers.Patch("/:id/features", authMiddleware.RequirePermission(rbac.PermUsersPlan), middleware.RequireIfMatch("id"), handler.UpdateFeatures)                          // Grant or deny feature keys
+	adminUsers.Patch("/:id/limits", authMiddleware.RequirePermission(rbac.PermUsersPlan), middleware.RequireIfMatch("id"), handler.UpdateLimits)                              // Update limits
+	adminUsers.Patch("/:id/lifetime-pro", authMiddleware.RequirePermission(rbac.PermUsersPlan), middleware.RequireIfMatch("id"), handler.GrantLifetimePro)                    // Grant lifetime pro
+	adminUsers.Post("/:id/ensure-metadata", authMiddleware.RequirePermission(rbac.PermUsersUpdate), middleware.RequireIfMatch("id"), handler.EnsureMetadata)                  // Ensure metadata
+	adminUsers.Get("/:id/plan-events", authMiddleware.RequirePermission(rbac.PermUsersPlan), handler.PlanHandler.FindPlanEvents)                                              // Plan transition history
+	adminUsers.Get("/:id/activity", authMiddleware.RequirePermission(rbac.PermUsersRead), handler.LoginHistoryHandler.GetUserActivity)                                        // Login history
+	adminUsers.Delete("/:id/plan-overrides", authMiddleware.RequirePermission(rbac.PermUsersPlan), middleware.RequireIfMatch("id"), handler.PlanHandler.ResetPlanOverrides)   // Back to catalog limits and features
+	adminUsers.Delete("/:id/lifetime-pro", authMiddleware.RequirePermission(rbac.PermUsersPlan), middleware.RequireIfMatch("id"), handler.RevokeLifetimePro)                  // Revoke lifetime pro
+	adminUsers.Post("/:id/invitation", authMiddleware.RequirePermission(rbac.PermUsersCreate), middleware.RequireIfMatch("id"), handler.InvitationHandler.ResendInvitation)   // Resend invitation
+	adminUsers.Delete("/:id/invitation", authMiddleware.RequirePermission(rbac.PermUsersCreate), middleware.RequireIfMatch("id"), handler.InvitationHandler.RevokeInvitation) // Revoke invitation
+	adminUsers.Get("/:id/roles", authMiddleware.RequirePermission(rbac.PermRolesManage), handler.RoleHandler.GetUserRoles)                                                    // Get user roles
+	adminUsers.Put("/:id/roles", authMiddleware.RequirePermission(rbac.PermRolesManage), middleware.RequireIfMatch("id"), handler.RoleHandler.UpdateUserRoles)                // Replace user roles
+
+	// Plan catalog routes
+	plans := v1.Group("/plans")
+	plans.Use(authMiddleware.Authenticate)
+	plans.Use(securityMiddleware.Monitor)
+	plans.Use(rateLimitMiddleware.ByUser("plans"))
+	plans.Use(quotaMiddleware.MeterRequests)
+	plans.Get("/", handler.PlanHandler.ListPlans)
+	plans.Put("/:code", authMiddleware.RequirePermission(rbac.PermPlansManage), handler.PlanHandler.UpdatePlan)
+
+	// Feature registry routes
+	features := v1.Group("/features")
+	features.Use(authMiddleware.Authenticate)
+	features.Use(securityMiddleware.Monitor)
+	features.Use(rateLimitMiddleware.ByUser("features"))
+	features.Use(quotaMiddleware.MeterRequests)
+	features.Get("/", handler.PlanHandler.ListFeatures)
+	features.Put("/:key", authMiddleware.RequirePermission(rbac.PermPlansManage), handler.PlanHandler.SaveFeature)
+
+	// Organization routes
+	orgs := v1.Group("/organizations")
+	orgs.Use(authMiddleware.Authenticate)
+	orgs.Use(securityMiddleware.Monitor)
+	orgs.Use(rateLimitMiddleware.ByUser("organizations"))
+	orgs.Use(quotaMiddleware.MeterRequests)
+	orgs.Get("/", handler.OrganizationHandler.ListOrganizations)
+	orgs.Post("/", authMiddleware.RequireWriteAccess, quotaMiddleware.MeterResources, securityMiddleware.TrackCreations, handler.OrganizationHandler.CreateOrganization)
+	orgs.Post("/invitations/accept", handler.OrganizationHandler.AcceptInvitation)
+	orgs.Get("/:id", handler.OrganizationHandler.GetOrganization)
+	orgs.Put("/:id", authMiddleware.RequireWriteAccess, handler.OrganizationHandler.UpdateOrganization)
+	orgs.Post("/:id/switch", handler.OrganizationHandler.SwitchOrganization)
+	orgs.Get("/:id/members", handler.OrganizationHandler.ListMembers)
+	orgs.Patch("/:id/members/:userId", authMiddleware.RequireWriteAccess, handler.OrganizationHandler.UpdateMemberRole)
+	orgs.Delete("/:id/members/:userId", handler.OrganizationHandler.RemoveMember)
+	orgs.Get("/:id/invitations", handler.OrganizationHandler.ListInvitations)
+	orgs.Post("/:id/invitations", authMiddleware.RequireWriteAccess, handler.OrganizationHandler.CreateInvitation)
+	orgs.Delete("/:id/invitations/:invitationId", handler.OrganizationHandler.RevokeInvitation)
+	orgs.Patch("/:id/plan", authMiddleware.RequirePermission(rbac.PermUsersPlan), handler.OrganizationHandler.UpdatePlan) // Update organization plan (admin)
+
+	// Role routes
+	roles := v1.Group("/roles")
+	roles.Use(authMiddleware.Authenticate)
+	roles.Use(securityMiddleware.Monitor)
+	roles.Use(rateLimitMiddleware.ByUser("roles"))
+	roles.Use(quotaMiddleware.MeterRequests)
+	roles.Get("/", authMiddleware.RequirePermission(rbac.PermRolesManage), handler.RoleHandler.ListRoles)
+
+	// Security console routes
+	securityConsole := v1.Group("/security")
+	securityConsole.Use(authMiddleware.Authenticate)
+	securityConsole.Use(securityMiddleware.Monitor)
+	securityConsole.Use(rateLimitMiddleware.ByUser("security"))
+	securityConsole.Use(quotaMiddleware.MeterRequests)
+	securityConsole.Get("/activities", authMiddleware.RequirePermission(rbac.PermSecurityRead), handler.SecurityHandler.FindActivities)        // List suspicious activities with counts per severity
+	securityConsole.Get("/users/:id/blocks", authMiddleware.RequirePermission(rbac.PermSecurityRead), handler.SecurityHandler.FindBlocks)      // Block history of a user
+	securityConsole.Post("/users/:id/block", authMiddleware.RequirePermission(rbac.PermSecurityManage), handler.SecurityHandler.BlockUser)     // Block a user manually
+	securityConsole.Post("/users/:id/unblock", authMiddleware.RequirePermission(rbac.PermSecurityManage), handler.SecurityHandler.UnblockUser) // Lift the active block
 }
@@ -47,8 +47,13 @@ func StartServer(lc fx.Lifecycle, p ServerParams) {
 	})
 }
 
-func newRouter(cfg *config.Config) *fiber.App {
-	app := fiber.New()
+// newRouter renders errors returned by middlewares (authentication,
+// permissions, quotas, preconditions) like the handlers do, instead of
+// Fiber's default plain-text 500.
+func newRouter(cfg *config.Config, errorHandler func(c *fiber.Ctx, err error) error) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: errorHandler,
+	})
 
 	origins := cfg.Server.AllowedOrigins
 	if origins == "" || origins == "*" {
@@ -62,7 +67,8 @@ func newRouter(cfg *config.Config) *fiber.App {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     origins,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, If-Match",
+		ExposeHeaders:    "ETag, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After, X-Quota-Limit, X-Quota-Remaining, X-Quota-Reset",
 		AllowMethods:     "GET, POST, PUT, DELETE, PATCH, OPTIONS",
 		AllowCredentials: true,
 	}))
@@ -0,0 +1,94 @@
+package delivery
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/accountdeletion"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
+)
+
+type AccountDeletionHandler struct {
+	service      *accountdeletion.Service
+	jwtService   *jwt.JwtService
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewAccountDeletionHandler(
+	service *accountdeletion.Service,
+	jwtService *jwt.JwtService,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *AccountDeletionHandler {
+	return &AccountDeletionHandler{
+		service:      service,
+		jwtService:   jwtService,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *AccountDeletionHandler) DeleteCurrentUser(c *fiber.Ctx) error {
+	var req dto.DeleteAccountRequestDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errors.New(errors.EBADREQUEST, "common.invalid_body")
+		}
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	purgeAfter, err := h.service.ScheduleSelfDeletion(c.UserContext(), userID, req.Password)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	for _, cookie := range h.jwtService.CleanAllFromHeader(c.Get("Cookie")) {
+		setHTTPCookieToFiber(c, cookie)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.AccountDeletionResponseDTO{
+		Message:    translate(c, "account.deleted_notice"),
+		PurgeAfter: purgeAfter,
+	})
+}
+
+func (h *AccountDeletionHandler) FindDeletedUsers(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	size, _ := strconv.Atoi(c.Query("size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	users, total, err := h.service.ListDeleted(c.UserContext(), page, size)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	mapper := NewUserMapper()
+	return c.Status(fiber.StatusOK).JSON(mapper.ToPageResponse(users, total, page, size))
+}
+
+func (h *AccountDeletionHandler) RestoreUser(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	u, err := h.service.Restore(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag(u))
+	mapper := NewUserMapper()
+	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(u))
+}
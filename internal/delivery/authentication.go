@@ -7,6 +7,7 @@ import (
 
 	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/loginhistory"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/internal/errors"
 	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
@@ -15,7 +16,7 @@ import (
 func (h *Handler) Login(c *fiber.Ctx) error {
 	var req dto.LoginRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	deviceID := extractDeviceID(c)
@@ -23,8 +24,16 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 	ipAddress := c.IP()
 
 	login := auth.Login{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:     req.Email,
+		Password:  req.Password,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		DeviceID:  deviceID,
+	}
+
+	profile, err := h.resolveTokenProfile(c, req.RememberMe)
+	if err != nil {
+		return h.ErrorHandler(c, err)
 	}
 
 	ctx := c.UserContext()
@@ -33,37 +42,76 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return h.ErrorHandler(c, err)
 	}
 
-	accessToken, _, cookies, err := h.AuthService.CreateSession(ctx, userEntity, userAgent, ipAddress, deviceID)
+	if userEntity.Metadata.PasswordChangeRequired {
+		accessToken, cookie, err := h.AuthService.CreatePasswordChangeSession(ctx, userEntity, loginhistory.MethodPassword, profile, userAgent, ipAddress, deviceID)
+		if err != nil {
+			return h.ErrorHandler(c, err)
+		}
+
+		setHTTPCookieToFiber(c, cookie)
+
+		return c.Status(fiber.StatusOK).JSON(dto.LoginResponseDTO{
+			UserID:                 userEntity.ID,
+			Email:                  userEntity.Email,
+			AccessToken:            accessToken,
+			ExpiresIn:              h.JwtService.GetAccessTokenExpirationSeconds(profile),
+			PasswordChangeRequired: true,
+		})
+	}
+
+	session, err := h.AuthService.CreateSession(ctx, userEntity, loginhistory.MethodPassword, profile, userAgent, ipAddress, deviceID)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
-	for _, cookie := range cookies {
-		setHTTPCookieToFiber(c, cookie)
+	return c.Status(fiber.StatusOK).JSON(h.sessionResponse(c, userEntity, session))
+}
+
+func (h *Handler) ChangeRequiredPassword(c *fiber.Ctx) error {
+	var req dto.ChangeRequiredPasswordRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	expiresIn := h.JwtService.GetAccessTokenExpirationSeconds()
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return errors.New(errors.EBADREQUEST, "password.current_and_new_required")
+	}
 
-	response := dto.LoginResponseDTO{
-		UserID:      userEntity.ID,
-		Email:       userEntity.Email,
-		AccessToken: accessToken,
-		ExpiresIn:   expiresIn,
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
 	}
 
-	_ = deviceID
-	_ = userAgent
-	_ = ipAddress
+	profile, _ := c.Locals("tokenProfile").(string)
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	ctx := c.UserContext()
+	userEntity, session, err := h.AuthService.ChangeRequiredPassword(
+		ctx, userID, req.CurrentPassword, req.NewPassword, profile, c.Get("User-Agent"), c.IP(), extractDeviceID(c),
+	)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(h.sessionResponse(c, userEntity, session))
 }
 
 func (h *Handler) Refresh(c *fiber.Ctx) error {
 
 	refreshToken := c.Cookies(jwt.RefreshTokenCookieName)
 
+	if apiKey := c.Get("X-API-Key"); refreshToken == "" && apiKey != "" {
+		if !h.JwtService.IsCLIAPIKey(apiKey) {
+			return errors.New(errors.EUNAUTHORIZED, "auth.invalid_api_key")
+		}
+
+		var req dto.MobileRefreshRequestDTO
+		if err := c.BodyParser(&req); err == nil {
+			refreshToken = req.RefreshToken
+		}
+	}
+
 	if refreshToken == "" {
-		return errors.Errorf(errors.EUNAUTHORIZED, "Refresh token not found in cookies")
+		return errors.New(errors.EUNAUTHORIZED, "auth.refresh_cookie_missing")
 	}
 
 	userAgent := c.Get("User-Agent")
@@ -71,23 +119,62 @@ func (h *Handler) Refresh(c *fiber.Ctx) error {
 	deviceID := extractDeviceID(c)
 
 	ctx := c.UserContext()
-	newAccessToken, _, cookies, err := h.AuthService.RefreshToken(ctx, refreshToken, userAgent, ipAddress, deviceID)
+	session, err := h.AuthService.RefreshToken(ctx, refreshToken, userAgent, ipAddress, deviceID)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
-	for _, cookie := range cookies {
-		setHTTPCookieToFiber(c, cookie)
+	response := dto.RefreshResponseDTO{
+		AccessToken:      session.AccessToken,
+		ExpiresIn:        int(session.Profile.AccessTokenTTL),
+		RefreshExpiresIn: int(session.Profile.RefreshTokenTTL),
 	}
 
-	response := dto.RefreshResponseDTO{
-		AccessToken: newAccessToken,
-		ExpiresIn:   int(h.JwtService.GetAccessTokenExpirationSeconds()),
+	if session.Profile.ClientType == auth.ClientTypeCLI {
+		response.RefreshToken = session.RefreshToken
+	} else {
+		for _, cookie := range session.Cookies {
+			setHTTPCookieToFiber(c, cookie)
+		}
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+func (h *Handler) Introspect(c *fiber.Ctx) error {
+	var req dto.IntrospectRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.Token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	result := h.AuthService.Introspect(c.UserContext(), req.Token)
+	if !result.Active {
+		return c.Status(fiber.StatusOK).JSON(dto.IntrospectResponseDTO{Active: false})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.IntrospectResponseDTO{
+		Active:     true,
+		Sub:        result.Subject,
+		Username:   result.Username,
+		Scope:      result.Scope,
+		Roles:      result.Roles,
+		Plan:       result.Plan,
+		AccessMode: result.AccessMode,
+		TokenType:  result.TokenType,
+		Profile:    result.Profile,
+		Sid:        result.SessionID,
+		Jti:        result.Jti,
+		Iss:        result.Issuer,
+		Aud:        result.Audience,
+		Iat:        result.IssuedAt,
+		Exp:        result.ExpiresAt,
+	})
+}
+
 func (h *Handler) Logout(c *fiber.Ctx) error {
 
 	refreshToken := c.Cookies(jwt.RefreshTokenCookieName)
@@ -104,7 +191,7 @@ func (h *Handler) Logout(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).JSON(dto.MessageResponse{
-		Message: "Logged out successfully",
+		Message: translate(c, "auth.logged_out"),
 	})
 }
 
@@ -112,7 +199,7 @@ func (h *Handler) LogoutAll(c *fiber.Ctx) error {
 
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		return errors.Errorf(errors.EUNAUTHORIZED, "User not authenticated")
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
 	}
 
 	refreshToken := c.Cookies(jwt.RefreshTokenCookieName)
@@ -124,14 +211,14 @@ func (h *Handler) LogoutAll(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).JSON(dto.MessageResponse{
-		Message: "Logged out from all other devices",
+		Message: translate(c, "auth.logged_out_all"),
 	})
 }
 
 func (h *Handler) Signup(c *fiber.Ctx) error {
 	var req dto.SignupUserRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	newUser := &user.User{
@@ -153,6 +240,42 @@ func (h *Handler) Signup(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(mapper.ToResponseDTO(newUser))
 }
 
+func (h *Handler) resolveTokenProfile(c *fiber.Ctx, rememberMe bool) (string, error) {
+	if apiKey := c.Get("X-API-Key"); apiKey != "" {
+		if !h.JwtService.IsCLIAPIKey(apiKey) {
+			return "", errors.New(errors.EUNAUTHORIZED, "auth.invalid_api_key")
+		}
+		return jwt.ProfileCLI, nil
+	}
+
+	if rememberMe {
+		return jwt.ProfileWebRemember, nil
+	}
+
+	return jwt.ProfileWeb, nil
+}
+
+func (h *Handler) sessionResponse(c *fiber.Ctx, u *user.User, session *auth.Session) dto.LoginResponseDTO {
+	response := dto.LoginResponseDTO{
+		UserID:           u.ID,
+		Email:            u.Email,
+		AccessToken:      session.AccessToken,
+		ExpiresIn:        session.Profile.AccessTokenTTL,
+		RefreshExpiresIn: session.Profile.RefreshTokenTTL,
+	}
+
+	if session.Profile.ClientType == auth.ClientTypeCLI {
+		response.RefreshToken = session.RefreshToken
+		return response
+	}
+
+	for _, cookie := range session.Cookies {
+		setHTTPCookieToFiber(c, cookie)
+	}
+
+	return response
+}
+
 func extractDeviceID(c *fiber.Ctx) string {
 
 	deviceID := c.Get("X-Device-ID")
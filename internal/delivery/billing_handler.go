@@ -0,0 +1,37 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/billing"
+)
+
+type BillingHandler struct {
+	service      *billing.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewBillingHandler(
+	service *billing.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *BillingHandler {
+	return &BillingHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+// HandleWebhook receives the provider notifications. The signature is
+// checked against the raw body, so it must not be parsed before.
+func (h *BillingHandler) HandleWebhook(c *fiber.Ctx) error {
+	provider, err := h.service.Provider(c.Params("provider"))
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	err = h.service.HandleWebhook(c.UserContext(), provider.Name(), c.Body(), c.Get(provider.SignatureHeader()))
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"received": true})
+}
@@ -0,0 +1,64 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/dataexport"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type DataExportHandler struct {
+	service      *dataexport.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewDataExportHandler(
+	service *dataexport.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *DataExportHandler {
+	return &DataExportHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *DataExportHandler) RequestExport(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	export, err := h.service.RequestExport(c.UserContext(), userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	response := toDataExportResponse(export)
+	response.Message = translate(c, "data_export.requested")
+	return c.Status(fiber.StatusAccepted).JSON(response)
+}
+
+func (h *DataExportHandler) GetLatestExport(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	export, err := h.service.GetLatest(c.UserContext(), userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toDataExportResponse(export))
+}
+
+func toDataExportResponse(export *dataexport.DataExport) dto.DataExportResponseDTO {
+	return dto.DataExportResponseDTO{
+		ID:          export.ID,
+		Status:      string(export.Status),
+		FileSize:    export.FileSize,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
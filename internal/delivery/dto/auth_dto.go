@@ -1,20 +1,54 @@
 package dto
 
 type LoginRequestDTO struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required"`
+	RememberMe bool   `json:"rememberMe"`
 }
 
 type LoginResponseDTO struct {
-	UserID      int64  `json:"userId"`
-	Email       string `json:"email"`
-	AccessToken string `json:"accessToken"`
-	ExpiresIn   int64  `json:"expiresIn"`
+	UserID                 int64  `json:"userId"`
+	Email                  string `json:"email"`
+	AccessToken            string `json:"accessToken"`
+	RefreshToken           string `json:"refreshToken,omitempty"`
+	ExpiresIn              int64  `json:"expiresIn"`
+	RefreshExpiresIn       int64  `json:"refreshExpiresIn,omitempty"`
+	PasswordChangeRequired bool   `json:"passwordChangeRequired"`
+}
+
+type ChangeRequiredPasswordRequestDTO struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+}
+
+type IntrospectRequestDTO struct {
+	Token         string `json:"token" form:"token"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint"`
+}
+
+type IntrospectResponseDTO struct {
+	Active     bool     `json:"active"`
+	Sub        string   `json:"sub,omitempty"`
+	Username   string   `json:"username,omitempty"`
+	Scope      string   `json:"scope,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	Plan       string   `json:"plan,omitempty"`
+	AccessMode string   `json:"access_mode,omitempty"`
+	TokenType  string   `json:"token_type,omitempty"`
+	Profile    string   `json:"profile,omitempty"`
+	Sid        string   `json:"sid,omitempty"`
+	Jti        string   `json:"jti,omitempty"`
+	Iss        string   `json:"iss,omitempty"`
+	Aud        string   `json:"aud,omitempty"`
+	Iat        int64    `json:"iat,omitempty"`
+	Exp        int64    `json:"exp,omitempty"`
 }
 
 type RefreshResponseDTO struct {
-	AccessToken string `json:"accessToken"`
-	ExpiresIn   int    `json:"expiresIn"`
+	AccessToken      string `json:"accessToken"`
+	RefreshToken     string `json:"refreshToken,omitempty"`
+	ExpiresIn        int    `json:"expiresIn"`
+	RefreshExpiresIn int    `json:"refreshExpiresIn"`
 }
 type MobileOAuth2RequestDTO struct {
 	IdToken  string `json:"idToken" validate:"required"`
@@ -22,13 +56,15 @@ type MobileOAuth2RequestDTO struct {
 }
 
 type MobileLoginResponseDTO struct {
-	UserID       int64  `json:"userId"`
-	Email        string `json:"email"`
-	Name         string `json:"name"`
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
-	ExpiresIn    int64  `json:"expiresIn"`
-	IsNewUser    bool   `json:"isNewUser"`
+	UserID                 int64  `json:"userId"`
+	Email                  string `json:"email"`
+	Name                   string `json:"name"`
+	AccessToken            string `json:"accessToken"`
+	RefreshToken           string `json:"refreshToken,omitempty"`
+	ExpiresIn              int64  `json:"expiresIn"`
+	RefreshExpiresIn       int64  `json:"refreshExpiresIn,omitempty"`
+	IsNewUser              bool   `json:"isNewUser"`
+	PasswordChangeRequired bool   `json:"passwordChangeRequired"`
 }
 
 type MobileRefreshRequestDTO struct {
@@ -36,7 +72,8 @@ type MobileRefreshRequestDTO struct {
 }
 
 type MobileRefreshResponseDTO struct {
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
-	ExpiresIn    int64  `json:"expiresIn"`
+	AccessToken      string `json:"accessToken"`
+	RefreshToken     string `json:"refreshToken"`
+	ExpiresIn        int64  `json:"expiresIn"`
+	RefreshExpiresIn int64  `json:"refreshExpiresIn"`
 }
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+type VerifyInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Response DTOs
+
+type InvitationResponseDTO struct {
+	UserID    int64     `json:"userId"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type LoginEventResponseDTO struct {
+	ID            int64     `json:"id"`
+	Email         string    `json:"email,omitempty"`
+	Method        string    `json:"method"`
+	Success       bool      `json:"success"`
+	FailureReason *string   `json:"failureReason,omitempty"`
+	IPAddress     string    `json:"ipAddress,omitempty"`
+	UserAgent     string    `json:"userAgent,omitempty"`
+	DeviceID      string    `json:"deviceId,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type LoginEventPageResponse struct {
+	Content       []LoginEventResponseDTO `json:"content"`
+	TotalElements int64                   `json:"totalElements"`
+	TotalPages    int                     `json:"totalPages"`
+	Size          int                     `json:"size"`
+	Number        int                     `json:"number"`
+	First         bool                    `json:"first"`
+	Last          bool                    `json:"last"`
+}
@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type OrganizationRequestDTO struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type OrganizationMemberRoleRequestDTO struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type OrganizationInvitationRequestDTO struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role,omitempty"`
+}
+
+type AcceptOrganizationInvitationRequestDTO struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type SwitchOrganizationRequestDTO struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type OrganizationPlanRequestDTO struct {
+	PlanType                *string         `json:"planType,omitempty"`
+	PlanExpirationDate      *time.Time      `json:"planExpirationDate,omitempty"`
+	MaxAccounts             *int            `json:"maxAccounts,omitempty"`
+	MaxCategoriesPerAccount *int            `json:"maxCategoriesPerAccount,omitempty"`
+	MaxTransactionsPerMonth *int            `json:"maxTransactionsPerMonth,omitempty"`
+	Features                map[string]bool `json:"features,omitempty"`
+}
+
+// Response DTOs
+
+type OrganizationPlanDTO struct {
+	PlanType                string     `json:"planType"`
+	PlanExpirationDate      *time.Time `json:"planExpirationDate,omitempty"`
+	MaxAccounts             int        `json:"maxAccounts"`
+	MaxCategoriesPerAccount int        `json:"maxCategoriesPerAccount"`
+	MaxTransactionsPerMonth int        `json:"maxTransactionsPerMonth"`
+	Features                []string   `json:"features"`
+}
+
+type OrganizationResponseDTO struct {
+	ID        int64               `json:"id"`
+	Name      string              `json:"name"`
+	Slug      string              `json:"slug"`
+	Personal  bool                `json:"personal"`
+	OwnerID   int64               `json:"ownerId"`
+	Role      string              `json:"role,omitempty"`
+	Active    bool                `json:"active"`
+	Plan      OrganizationPlanDTO `json:"plan"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+type OrganizationMemberResponseDTO struct {
+	UserID   int64     `json:"userId"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type OrganizationInvitationResponseDTO struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organizationId"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
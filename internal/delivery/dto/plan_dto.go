@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Request DTOs
+
+type PlanUpdateRequestDTO struct {
+	Name                    *string `json:"name,omitempty"`
+	Rank                    *int    `json:"rank,omitempty"`
+	MaxAccounts             *int    `json:"maxAccounts,omitempty"`
+	MaxCategoriesPerAccount *int    `json:"maxCategoriesPerAccount,omitempty"`
+	MaxTransactionsPerMonth *int    `json:"maxTransactionsPerMonth,omitempty"`
+
+	// Quotas accept null to make the plan unlimited.
+	MaxResources        NullableInt `json:"maxResources"`
+	MaxRequestsPerMonth NullableInt `json:"maxRequestsPerMonth"`
+}
+
+// NullableInt tells a field left out of the body (Set is false) from an
+// explicit null (Set is true and Value is nil).
+type NullableInt struct {
+	Set   bool
+	Value *int
+}
+
+func (n *NullableInt) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	n.Value = nil
+	if string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+type FeatureRequestDTO struct {
+	Description *string   `json:"description,omitempty"`
+	Plans       *[]string `json:"plans,omitempty"`
+}
+
+// Response DTOs
+
+type PlanResponseDTO struct {
+	Code                    string     `json:"code"`
+	Name                    string     `json:"name"`
+	Rank                    int        `json:"rank"`
+	MaxResources            *int       `json:"maxResources,omitempty"`
+	MaxRequestsPerMonth     *int       `json:"maxRequestsPerMonth,omitempty"`
+	MaxAccounts             int        `json:"maxAccounts"`
+	MaxCategoriesPerAccount int        `json:"maxCategoriesPerAccount"`
+	MaxTransactionsPerMonth int        `json:"maxTransactionsPerMonth"`
+	Features                []string   `json:"features"`
+	UpdatedAt               *time.Time `json:"updatedAt,omitempty"`
+}
+
+type PlanOverridesDTO struct {
+	MaxAccounts             *int            `json:"maxAccounts,omitempty"`
+	MaxCategoriesPerAccount *int            `json:"maxCategoriesPerAccount,omitempty"`
+	MaxTransactionsPerMonth *int            `json:"maxTransactionsPerMonth,omitempty"`
+	Features                map[string]bool `json:"features,omitempty"`
+}
+
+type FeatureResponseDTO struct {
+	Key         string    `json:"key"`
+	Description string    `json:"description"`
+	Plans       []string  `json:"plans"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// Request DTOs
+
+type UserRolesRequestDTO struct {
+	Roles []string `json:"roles" validate:"required,min=1"`
+}
+
+// Response DTOs
+
+type RoleResponseDTO struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UserRolesResponseDTO struct {
+	UserID int64             `json:"userId"`
+	Roles  []RoleResponseDTO `json:"roles"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// Request DTOs
+
+type BlockUserRequestDTO struct {
+	Reason       string     `json:"reason"`
+	BlockedUntil *time.Time `json:"blockedUntil,omitempty"`
+}
+
+type UnblockUserRequestDTO struct {
+	Notes *string `json:"notes,omitempty"`
+}
+
+// Response DTOs
+
+type SuspiciousActivityResponseDTO struct {
+	ID           int64          `json:"id"`
+	UserID       int64          `json:"userId"`
+	ActivityType string         `json:"activityType"`
+	Severity     string         `json:"severity"`
+	Endpoint     string         `json:"endpoint"`
+	IPAddress    *string        `json:"ipAddress,omitempty"`
+	UserAgent    *string        `json:"userAgent,omitempty"`
+	RequestCount int            `json:"requestCount"`
+	Details      map[string]any `json:"details,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+}
+
+type SeverityCountsDTO struct {
+	Low      int64 `json:"low"`
+	Medium   int64 `json:"medium"`
+	High     int64 `json:"high"`
+	Critical int64 `json:"critical"`
+	Total    int64 `json:"total"`
+}
+
+type SuspiciousActivityPageResponse struct {
+	Content        []SuspiciousActivityResponseDTO `json:"content"`
+	SeverityCounts SeverityCountsDTO               `json:"severityCounts"`
+	TotalElements  int64                           `json:"totalElements"`
+	TotalPages     int                             `json:"totalPages"`
+	Size           int                             `json:"size"`
+	Number         int                             `json:"number"`
+	First          bool                            `json:"first"`
+	Last           bool                            `json:"last"`
+}
+
+type SecurityBlockResponseDTO struct {
+	ID                      int64      `json:"id"`
+	UserID                  int64      `json:"userId"`
+	Reason                  string     `json:"reason"`
+	SuspiciousActivityCount int        `json:"suspiciousActivityCount"`
+	BlockedAt               time.Time  `json:"blockedAt"`
+	BlockedUntil            *time.Time `json:"blockedUntil,omitempty"`
+	BlockedBy               *int64     `json:"blockedBy,omitempty"`
+	UnblockedAt             *time.Time `json:"unblockedAt,omitempty"`
+	UnblockedBy             *int64     `json:"unblockedBy,omitempty"`
+	Notes                   *string    `json:"notes,omitempty"`
+	Active                  bool       `json:"active"`
+}
+
+type SecurityBlockPageResponse struct {
+	Content       []SecurityBlockResponseDTO `json:"content"`
+	TotalElements int64                      `json:"totalElements"`
+	TotalPages    int                        `json:"totalPages"`
+	Size          int                        `json:"size"`
+	Number        int                        `json:"number"`
+	First         bool                       `json:"first"`
+	Last          bool                       `json:"last"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// Response DTOs
+
+type QuotaDTO struct {
+	Used      int64  `json:"used"`
+	Limit     *int   `json:"limit,omitempty"`
+	Remaining *int64 `json:"remaining,omitempty"`
+}
+
+type UsageResponseDTO struct {
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Requests    QuotaDTO  `json:"requests"`
+	Resources   QuotaDTO  `json:"resources"`
+}
+
+type UsageReportRowDTO struct {
+	UserID    int64  `json:"userId"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	PlanType  string `json:"planType"`
+	Requests  int64  `json:"requests"`
+	Resources int64  `json:"resources"`
+}
+
+type UsageReportPageResponse struct {
+	Period        string              `json:"period"`
+	Content       []UsageReportRowDTO `json:"content"`
+	TotalElements int64               `json:"totalElements"`
+	TotalPages    int                 `json:"totalPages"`
+	Size          int                 `json:"size"`
+	Number        int                 `json:"number"`
+	First         bool                `json:"first"`
+	Last          bool                `json:"last"`
+}
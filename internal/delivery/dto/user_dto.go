@@ -13,12 +13,12 @@ type SignupUserRequestDTO struct {
 }
 
 type UserPostRequestDTO struct {
-	Name     string  `json:"name" validate:"required,min=3,max=255"`
-	Email    string  `json:"email" validate:"required,email"`
-	Password *string `json:"password,omitempty" validate:"omitempty,min=6"`
-	Admin    *bool   `json:"admin,omitempty"`
-	Active   *bool   `json:"active,omitempty"`
-	Source   *string `json:"source,omitempty"`
+	Name   string   `json:"name" validate:"required,min=3,max=255"`
+	Email  string   `json:"email" validate:"required,email"`
+	Admin  *bool    `json:"admin,omitempty"`
+	Active *bool    `json:"active,omitempty"`
+	Source *string  `json:"source,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
 }
 
 type UserPutRequestDTO struct {
@@ -27,11 +27,31 @@ type UserPutRequestDTO struct {
 	ImgURL *string `json:"imgUrl,omitempty"`
 }
 
+type EmailChangeRequestDTO struct {
+	NewEmail string `json:"newEmail" validate:"required,email"`
+}
+
+type EmailChangeTokenRequestDTO struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type UserPutPasswordRequestDTO struct {
 	CurrentPassword *string `json:"currentPassword,omitempty"`
 	Password        string  `json:"password" validate:"required,min=6"`
 }
 
+type PreferencesPatchRequestDTO struct {
+	Locale           *string                       `json:"locale,omitempty"`
+	Currency         *string                       `json:"currency,omitempty"`
+	Timezone         *string                       `json:"timezone,omitempty"`
+	Notifications    *NotificationSettingsPatchDTO `json:"notifications,omitempty"`
+	MarketingConsent *bool                         `json:"marketingConsent,omitempty"`
+}
+
+type NotificationSettingsPatchDTO struct {
+	PlanReminders *bool `json:"planReminders,omitempty"`
+}
+
 type UploadImageRequestDTO struct {
 	FileName      string `json:"fileName" validate:"required"`
 	ContentType   string `json:"contentType" validate:"required"`
@@ -42,11 +62,10 @@ type UserUpdateAccessModeDTO struct {
 	AccessMode string `json:"accessMode" validate:"required,oneof=READ_WRITE READ_ONLY DISABLED"`
 }
 
+// UserUpdateFeaturesDTO grants (true) or denies (false) feature keys; null
+// removes the override.
 type UserUpdateFeaturesDTO struct {
-	CanCreateBudgets *bool `json:"canCreateBudgets,omitempty"`
-	CanExportData    *bool `json:"canExportData,omitempty"`
-	CanUseReports    *bool `json:"canUseReports,omitempty"`
-	CanUseGoals      *bool `json:"canUseGoals,omitempty"`
+	Features map[string]*bool `json:"features" validate:"required"`
 }
 
 type UserUpdateLimitsDTO struct {
@@ -59,33 +78,56 @@ type UserGrantLifetimeProDTO struct {
 	Reason string `json:"reason" validate:"required"`
 }
 
+type UserForcePasswordChangeDTO struct {
+	IDs []int64 `json:"ids,omitempty"`
+	All bool    `json:"all"`
+}
+
+type DeleteAccountRequestDTO struct {
+	Password string `json:"password,omitempty"`
+}
+
 type ResendVerificationRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
 type UserMetadataDTO struct {
-	AccessMode              string     `json:"accessMode"`
-	PlanType                string     `json:"planType"`
-	PlanExpirationDate      *time.Time `json:"planExpirationDate,omitempty"`
-	ProSource               *string    `json:"proSource,omitempty"`
-	MaxResources            *int       `json:"maxResources,omitempty"`
-	MaxRequestsPerMonth     *int       `json:"maxRequestsPerMonth,omitempty"`
-	MaxAccounts             int        `json:"maxAccounts"`
-	MaxCategoriesPerAccount int        `json:"maxCategoriesPerAccount"`
-	MaxTransactionsPerMonth int        `json:"maxTransactionsPerMonth"`
-	CanExportData           bool       `json:"canExportData"`
-	CanUseReports           bool       `json:"canUseReports"`
-	CanUseAdvancedFeatures  bool       `json:"canUseAdvancedFeatures"`
-	CanCreateBudgets        bool       `json:"canCreateBudgets"`
-	CanUseGoals             bool       `json:"canUseGoals"`
-	EmailVerified           bool       `json:"emailVerified"`
-	ReputationStatus        string     `json:"reputationStatus"`
-	SuspiciousActivityCount int        `json:"suspiciousActivityCount"`
-	LastSecurityCheck       *time.Time `json:"lastSecurityCheck,omitempty"`
-	LastPermissionCheck     *time.Time `json:"lastPermissionCheck,omitempty"`
-	Notes                   *string    `json:"notes,omitempty"`
-	Locale                  string     `json:"locale"`
-	Currency                string     `json:"currency"`
+	AccessMode              string            `json:"accessMode"`
+	PlanType                string            `json:"planType"`
+	PlanExpirationDate      *time.Time        `json:"planExpirationDate,omitempty"`
+	ProSource               *string           `json:"proSource,omitempty"`
+	MaxResources            *int              `json:"maxResources,omitempty"`
+	MaxRequestsPerMonth     *int              `json:"maxRequestsPerMonth,omitempty"`
+	MaxAccounts             int               `json:"maxAccounts"`
+	MaxCategoriesPerAccount int               `json:"maxCategoriesPerAccount"`
+	MaxTransactionsPerMonth int               `json:"maxTransactionsPerMonth"`
+	Features                []string          `json:"features"`
+	PlanOverrides           *PlanOverridesDTO `json:"planOverrides,omitempty"`
+	TrialUsedAt             *time.Time        `json:"trialUsedAt,omitempty"`
+	EmailVerified           bool              `json:"emailVerified"`
+	MustSetPassword         bool              `json:"mustSetPassword"`
+	PasswordChangeRequired  bool              `json:"passwordChangeRequired"`
+	ReputationStatus        string            `json:"reputationStatus"`
+	SuspiciousActivityCount int               `json:"suspiciousActivityCount"`
+	LastSecurityCheck       *time.Time        `json:"lastSecurityCheck,omitempty"`
+	LastPermissionCheck     *time.Time        `json:"lastPermissionCheck,omitempty"`
+	Notes                   *string           `json:"notes,omitempty"`
+	Locale                  string            `json:"locale"`
+	Currency                string            `json:"currency"`
+	Timezone                string            `json:"timezone"`
+}
+
+type PreferencesResponseDTO struct {
+	Locale             string                  `json:"locale"`
+	Currency           string                  `json:"currency"`
+	Timezone           string                  `json:"timezone"`
+	Notifications      NotificationSettingsDTO `json:"notifications"`
+	MarketingConsent   bool                    `json:"marketingConsent"`
+	MarketingConsentAt *time.Time              `json:"marketingConsentAt,omitempty"`
+}
+
+type NotificationSettingsDTO struct {
+	PlanReminders bool `json:"planReminders"`
 }
 
 // Response DTOs
@@ -102,6 +144,93 @@ type UserResponseDTO struct {
 	LastAccess *time.Time      `json:"lastAccess,omitempty"`
 	CreatedAt  time.Time       `json:"createdAt"`
 	UpdatedAt  time.Time       `json:"updatedAt"`
+	DeletedAt  *time.Time      `json:"deletedAt,omitempty"`
+	PurgeAfter *time.Time      `json:"purgeAfter,omitempty"`
+	Version    int64           `json:"version"`
+
+	PendingEmail *string `json:"pendingEmail,omitempty"`
+}
+
+type EmailChangeResponseDTO struct {
+	Message      string    `json:"message"`
+	PendingEmail string    `json:"pendingEmail"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+type AccountDeletionResponseDTO struct {
+	Message    string    `json:"message"`
+	PurgeAfter time.Time `json:"purgeAfter"`
+}
+
+type DataExportResponseDTO struct {
+	ID          int64      `json:"id"`
+	Status      string     `json:"status"`
+	FileSize    int64      `json:"fileSize,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Message     string     `json:"message,omitempty"`
+}
+
+type UserImportErrorDTO struct {
+	Row     int    `json:"row"`
+	Email   string `json:"email,omitempty"`
+	Message string `json:"message"`
+}
+
+type UserImportJobResponseDTO struct {
+	ID              int64                `json:"id"`
+	Format          string               `json:"format"`
+	Status          string               `json:"status"`
+	DryRun          bool                 `json:"dryRun"`
+	SendInvitations bool                 `json:"sendInvitations"`
+	TotalRows       int                  `json:"totalRows"`
+	ValidRows       int                  `json:"validRows"`
+	ProcessedRows   int                  `json:"processedRows"`
+	CreatedRows     int                  `json:"createdRows"`
+	FailedRows      int                  `json:"failedRows"`
+	Errors          []UserImportErrorDTO `json:"errors"`
+	Message         *string              `json:"message,omitempty"`
+	CreatedAt       time.Time            `json:"createdAt"`
+	StartedAt       *time.Time           `json:"startedAt,omitempty"`
+	CompletedAt     *time.Time           `json:"completedAt,omitempty"`
+}
+
+type UserExportJobResponseDTO struct {
+	ID          int64      `json:"id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Columns     []string   `json:"columns"`
+	RowCount    int64      `json:"rowCount"`
+	FileSize    int64      `json:"fileSize,omitempty"`
+	DownloadURL string     `json:"downloadUrl,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Message     string     `json:"message,omitempty"`
+}
+
+type PlanEventResponseDTO struct {
+	ID        int64      `json:"id"`
+	Type      string     `json:"type"`
+	FromPlan  string     `json:"fromPlan,omitempty"`
+	ToPlan    string     `json:"toPlan,omitempty"`
+	ProSource *string    `json:"proSource,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	ActorID   *int64     `json:"actorId,omitempty"`
+	Reason    *string    `json:"reason,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type PlanEventPageResponse struct {
+	Content       []PlanEventResponseDTO `json:"content"`
+	TotalElements int64                  `json:"totalElements"`
+	TotalPages    int                    `json:"totalPages"`
+	Size          int                    `json:"size"`
+	Number        int                    `json:"number"`
+	First         bool                   `json:"first"`
+	Last          bool                   `json:"last"`
 }
 
 type UploadResponseDTO struct {
@@ -109,6 +238,10 @@ type UploadResponseDTO struct {
 	PublicURL       string `json:"publicUrl"`
 }
 
+type ForcePasswordChangeResponseDTO struct {
+	Affected int `json:"affected"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
@@ -122,3 +255,10 @@ type PageResponse struct {
 	First         bool              `json:"first"`
 	Last          bool              `json:"last"`
 }
+
+type CursorPageResponse struct {
+	Content    []UserResponseDTO `json:"content"`
+	Size       int               `json:"size"`
+	NextCursor *string           `json:"nextCursor,omitempty"`
+	HasNext    bool              `json:"hasNext"`
+}
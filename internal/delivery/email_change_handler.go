@@ -0,0 +1,183 @@
+package delivery
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/emailchange"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
+)
+
+type EmailChangeHandler struct {
+	service      *emailchange.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewEmailChangeHandler(
+	service *emailchange.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *EmailChangeHandler) RequestEmailChange(c *fiber.Ctx) error {
+	var req dto.EmailChangeRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.NewEmail == "" {
+		return errors.New(errors.EBADREQUEST, "email_change.new_email_required")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	request, err := h.service.RequestChange(c.UserContext(), userID, req.NewEmail)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(toEmailChangeResponse(c, request))
+}
+
+func (h *EmailChangeHandler) GetPendingEmailChange(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	request, err := h.service.GetPending(c.UserContext(), userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toEmailChangeResponse(c, request))
+}
+
+func (h *EmailChangeHandler) CancelPendingEmailChange(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	if err := h.service.CancelPending(c.UserContext(), userID); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *EmailChangeHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req dto.EmailChangeTokenRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.Token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	u, err := h.service.Confirm(c.UserContext(), req.Token)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag(u))
+	mapper := NewUserMapper()
+	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(u))
+}
+
+func (h *EmailChangeHandler) ConfirmEmailChangeByQuery(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	if _, err := h.service.Confirm(c.UserContext(), token); err != nil {
+		return renderEmailChangePage(c, fiber.StatusBadRequest, "❌", translate(c, "page.error_title"), errors.LocalizedMessage(err, requestLocale(c)))
+	}
+
+	return renderEmailChangePage(c, fiber.StatusOK, "✅", translate(c, "page.email_changed.title"),
+		translate(c, "page.email_changed.message"))
+}
+
+func (h *EmailChangeHandler) CancelEmailChange(c *fiber.Ctx) error {
+	var req dto.EmailChangeTokenRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.Token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	if err := h.service.Cancel(c.UserContext(), req.Token); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *EmailChangeHandler) CancelEmailChangeByQuery(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	if err := h.service.Cancel(c.UserContext(), token); err != nil {
+		return renderEmailChangePage(c, fiber.StatusBadRequest, "❌", translate(c, "page.error_title"), errors.LocalizedMessage(err, requestLocale(c)))
+	}
+
+	return renderEmailChangePage(c, fiber.StatusOK, "🔒", translate(c, "page.email_change_cancelled.title"),
+		translate(c, "page.email_change_cancelled.message"))
+}
+
+func toEmailChangeResponse(c *fiber.Ctx, request *emailchange.EmailChangeRequest) dto.EmailChangeResponseDTO {
+	return dto.EmailChangeResponseDTO{
+		Message:      translate(c, "email_change.requested"),
+		PendingEmail: request.NewEmail,
+		ExpiresAt:    request.ExpiresAt,
+	}
+}
+
+func renderEmailChangePage(c *fiber.Ctx, status int, icon, title, message string) error {
+	locale := requestLocale(c)
+
+	c.Set("Content-Type", "text/html")
+	return c.Status(status).SendString(fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html lang="%s">
+		<head>
+			<meta charset="UTF-8">
+			<meta name="viewport" content="width=device-width, initial-scale=1.0">
+			<title>%s</title>
+			<style>
+				body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; display: flex; justify-content: center; align-items: center; height: 100vh; margin: 0; background-color: #f0f2f5; }
+				.card { background: white; padding: 2.5rem; border-radius: 12px; box-shadow: 0 4px 20px rgba(0,0,0,0.08); text-align: center; max-width: 450px; width: 90%%; }
+				.icon { font-size: 4rem; margin-bottom: 1rem; }
+				h1 { color: #1a1a1a; margin-bottom: 1rem; font-size: 1.5rem; }
+				p { color: #666; line-height: 1.6; margin-bottom: 1.5rem; }
+			</style>
+		</head>
+		<body>
+			<div class="card">
+				<div class="icon">%s</div>
+				<h1>%s</h1>
+				<p>%s</p>
+				<p>%s</p>
+			</div>
+		</body>
+		</html>
+	`, locale, html.EscapeString(title), icon, html.EscapeString(title), html.EscapeString(message), i18n.T(locale, "page.close_tab")))
+}
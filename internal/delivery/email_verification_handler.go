@@ -9,6 +9,7 @@ import (
 	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
 )
 
 type EmailVerificationHandler struct {
@@ -33,19 +34,19 @@ func (h *EmailVerificationHandler) SendVerificationEmail(c *fiber.Ctx) error {
 
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		return errors.Errorf(errors.EUNAUTHORIZED, "User not authenticated")
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
 	}
 
 	ctx := c.UserContext()
 	u, err := h.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return h.ErrorHandler(c, errors.Errorf(errors.ENOTFOUND, "User not found"))
+		return h.ErrorHandler(c, errors.New(errors.ENOTFOUND, "user.not_found"))
 	}
 
 	if u.Active {
 		return c.Status(fiber.StatusOK).JSON(dto.EmailVerificationResponse{
 			Success: true,
-			Message: "Email já está verificado",
+			Message: translate(c, "email_verification.already_verified"),
 		})
 	}
 
@@ -56,18 +57,18 @@ func (h *EmailVerificationHandler) SendVerificationEmail(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(dto.EmailVerificationResponse{
 		Success: true,
-		Message: "Email de verificação enviado com sucesso!",
+		Message: translate(c, "email_verification.sent"),
 	})
 }
 
 func (h *EmailVerificationHandler) VerifyEmail(c *fiber.Ctx) error {
 	var req dto.VerifyEmailRequest
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	if req.Token == "" {
-		return errors.Errorf(errors.EBADREQUEST, "Token is required")
+		return errors.New(errors.EBADREQUEST, "common.token_required")
 	}
 
 	ctx := c.UserContext()
@@ -75,7 +76,7 @@ func (h *EmailVerificationHandler) VerifyEmail(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(dto.EmailVerificationResponse{
 		Success: result.Success,
-		Message: result.Message,
+		Message: translate(c, result.MessageKey),
 		UserID:  result.UserID,
 		Email:   result.Email,
 	})
@@ -84,24 +85,25 @@ func (h *EmailVerificationHandler) VerifyEmail(c *fiber.Ctx) error {
 func (h *EmailVerificationHandler) VerifyEmailByQuery(c *fiber.Ctx) error {
 	token := c.Query("token")
 	if token == "" {
-		return errors.Errorf(errors.EBADREQUEST, "Token is required")
+		return errors.New(errors.EBADREQUEST, "common.token_required")
 	}
 
 	ctx := c.UserContext()
 	result := h.service.VerifyToken(ctx, token)
+	locale := requestLocale(c)
 
 	c.Set("Content-Type", "text/html")
 	if result.Success {
-		return c.Status(fiber.StatusOK).SendString(`
+		return c.Status(fiber.StatusOK).SendString(fmt.Sprintf(`
 			<!DOCTYPE html>
-			<html lang="pt-BR">
+			<html lang="%s">
 			<head>
 				<meta charset="UTF-8">
 				<meta name="viewport" content="width=device-width, initial-scale=1.0">
-				<title>Email Verificado</title>
+				<title>%s</title>
 				<style>
 					body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; display: flex; justify-content: center; align-items: center; height: 100vh; margin: 0; background-color: #f0f2f5; }
-					.card { background: white; padding: 2.5rem; border-radius: 12px; box-shadow: 0 4px 20px rgba(0,0,0,0.08); text-align: center; max-width: 450px; width: 90%; }
+					.card { background: white; padding: 2.5rem; border-radius: 12px; box-shadow: 0 4px 20px rgba(0,0,0,0.08); text-align: center; max-width: 450px; width: 90%%; }
 					.icon { font-size: 4rem; margin-bottom: 1rem; }
 					h1 { color: #1a1a1a; margin-bottom: 1rem; font-size: 1.5rem; }
 					p { color: #666; line-height: 1.6; margin-bottom: 1.5rem; }
@@ -113,25 +115,30 @@ func (h *EmailVerificationHandler) VerifyEmailByQuery(c *fiber.Ctx) error {
 			<body>
 				<div class="card">
 					<div class="icon success-icon">✅</div>
-					<h1>Email Verificado!</h1>
-					<p>Excelente! Seu endereço de email foi confirmado com sucesso. Agora você tem acesso total a todas as funcionalidades do sistema.</p>
-					<p>Você já pode fechar esta aba e voltar para o aplicativo.</p>
+					<h1>%s</h1>
+					<p>%s</p>
+					<p>%s</p>
 				</div>
 			</body>
 			</html>
-		`)
+		`, locale,
+			i18n.T(locale, "page.email_verified.title"),
+			i18n.T(locale, "page.email_verified.title"),
+			i18n.T(locale, "page.email_verified.message"),
+			i18n.T(locale, "page.close_tab"),
+		))
 	}
 
 	return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf(`
 		<!DOCTYPE html>
-		<html lang="pt-BR">
+		<html lang="%s">
 		<head>
 			<meta charset="UTF-8">
 			<meta name="viewport" content="width=device-width, initial-scale=1.0">
-			<title>Erro na Verificação</title>
+			<title>%s</title>
 			<style>
 				body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; display: flex; justify-content: center; align-items: center; height: 100vh; margin: 0; background-color: #f0f2f5; }
-				.card { background: white; padding: 2.5rem; border-radius: 12px; box-shadow: 0 4px 20px rgba(0,0,0,0.08); text-align: center; max-width: 450px; width: 90%; }
+				.card { background: white; padding: 2.5rem; border-radius: 12px; box-shadow: 0 4px 20px rgba(0,0,0,0.08); text-align: center; max-width: 450px; width: 90%%; }
 				.icon { font-size: 4rem; margin-bottom: 1rem; }
 				h1 { color: #1a1a1a; margin-bottom: 1rem; font-size: 1.5rem; }
 				p { color: #666; line-height: 1.6; margin-bottom: 1.5rem; }
@@ -141,23 +148,28 @@ func (h *EmailVerificationHandler) VerifyEmailByQuery(c *fiber.Ctx) error {
 		<body>
 			<div class="card">
 				<div class="icon error-icon">❌</div>
-				<h1>Ops! Algo deu errado</h1>
+				<h1>%s</h1>
+				<p>%s</p>
 				<p>%s</p>
-				<p>Por favor, verifique se o link está correto ou tente solicitar um novo código de verificação no aplicativo.</p>
 			</div>
 		</body>
 		</html>
-	`, result.Message))
+	`, locale,
+		i18n.T(locale, "page.email_verification_failed.title"),
+		i18n.T(locale, "page.error_title"),
+		i18n.T(locale, result.MessageKey),
+		i18n.T(locale, "page.email_verification_failed.hint"),
+	))
 }
 
 func (h *EmailVerificationHandler) ResendVerificationEmail(c *fiber.Ctx) error {
 	var req dto.ResendEmailVerificationRequest
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	if req.Email == "" {
-		return errors.Errorf(errors.EBADREQUEST, "Email is required")
+		return errors.New(errors.EBADREQUEST, "validation.email_required")
 	}
 
 	ctx := c.UserContext()
@@ -167,6 +179,6 @@ func (h *EmailVerificationHandler) ResendVerificationEmail(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(dto.EmailVerificationResponse{
 		Success: true,
-		Message: "Email de verificação reenviado com sucesso!",
+		Message: translate(c, "email_verification.resent"),
 	})
 }
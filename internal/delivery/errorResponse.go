@@ -13,21 +13,24 @@ func NewErrorHandler() func(c *fiber.Ctx, err error) error {
 }
 
 var codes = map[string]int{
-	errors.ECONFLICT:       http.StatusConflict,
-	errors.EINVALID:        http.StatusBadRequest,
-	errors.ENOTFOUND:       http.StatusNotFound,
-	errors.ENOTIMPLEMENTED: http.StatusNotImplemented,
-	errors.EUNAUTHORIZED:   http.StatusUnauthorized,
-	errors.EINTERNAL:       http.StatusInternalServerError,
-	errors.EDUPLICATION:    http.StatusConflict,
-	errors.EBADREQUEST:     http.StatusBadRequest,
-	errors.EFORBIDDEN:      http.StatusForbidden,
-	errors.ETIMEOUT:        http.StatusRequestTimeout,
-	errors.EUNAVAILABLE:    http.StatusServiceUnavailable,
+	errors.ECONFLICT:        http.StatusConflict,
+	errors.EINVALID:         http.StatusBadRequest,
+	errors.ENOTFOUND:        http.StatusNotFound,
+	errors.ENOTIMPLEMENTED:  http.StatusNotImplemented,
+	errors.EUNAUTHORIZED:    http.StatusUnauthorized,
+	errors.EINTERNAL:        http.StatusInternalServerError,
+	errors.EDUPLICATION:     http.StatusConflict,
+	errors.EBADREQUEST:      http.StatusBadRequest,
+	errors.EFORBIDDEN:       http.StatusForbidden,
+	errors.ETIMEOUT:         http.StatusRequestTimeout,
+	errors.ERATELIMIT:       http.StatusTooManyRequests,
+	errors.EPRECONDITION:    http.StatusPreconditionFailed,
+	errors.EPRECONDREQUIRED: http.StatusPreconditionRequired,
+	errors.EUNAVAILABLE:     http.StatusServiceUnavailable,
 }
 
 func Error(c *fiber.Ctx, err error) error {
-	code, message := errors.ErrorCode(err), errors.ErrorMessage(err)
+	code, message := errors.ErrorCode(err), errors.LocalizedMessage(err, requestLocale(c))
 	if code == errors.EINTERNAL {
 		LogError(c, err)
 	}
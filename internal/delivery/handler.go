@@ -2,8 +2,14 @@ package delivery
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/accountdeletion"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/emailchange"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/invitation"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/planlifecycle"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/rbac"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
@@ -14,7 +20,27 @@ type Handler struct {
 	UserService              *user.Service
 	EmailVerificationService *emailverification.Service
 	EmailVerificationHandler *EmailVerificationHandler
+	EmailChangeService       *emailchange.Service
+	EmailChangeHandler       *EmailChangeHandler
 	PasswordRecoveryHandler  *PasswordRecoveryHandler
+	InvitationService        *invitation.Service
+	InvitationHandler        *InvitationHandler
+	RbacService              *rbac.Service
+	RoleHandler              *RoleHandler
+	OrganizationHandler      *OrganizationHandler
+	AccountDeletionService   *accountdeletion.Service
+	AccountDeletionHandler   *AccountDeletionHandler
+	DataExportHandler        *DataExportHandler
+	UserImportHandler        *UserImportHandler
+	UserExportHandler        *UserExportHandler
+	PlanLifecycleService     *planlifecycle.Service
+	PlanCatalog              *plancatalog.Service
+	PlanHandler              *PlanHandler
+	BillingHandler           *BillingHandler
+	UsageHandler             *UsageHandler
+	PreferencesHandler       *PreferencesHandler
+	SecurityHandler          *SecurityHandler
+	LoginHistoryHandler      *LoginHistoryHandler
 	UploadHandler            *UploadHandler
 	MobileAuthHandler        *MobileAuthHandler
 	JwtService               *jwt.JwtService
@@ -28,7 +54,27 @@ func NewHandler(
 	UserService *user.Service,
 	EmailVerificationService *emailverification.Service,
 	EmailVerificationHandler *EmailVerificationHandler,
+	EmailChangeService *emailchange.Service,
+	EmailChangeHandler *EmailChangeHandler,
 	PasswordRecoveryHandler *PasswordRecoveryHandler,
+	InvitationService *invitation.Service,
+	InvitationHandler *InvitationHandler,
+	RbacService *rbac.Service,
+	RoleHandler *RoleHandler,
+	OrganizationHandler *OrganizationHandler,
+	AccountDeletionService *accountdeletion.Service,
+	AccountDeletionHandler *AccountDeletionHandler,
+	DataExportHandler *DataExportHandler,
+	UserImportHandler *UserImportHandler,
+	UserExportHandler *UserExportHandler,
+	PlanLifecycleService *planlifecycle.Service,
+	PlanCatalog *plancatalog.Service,
+	PlanHandler *PlanHandler,
+	BillingHandler *BillingHandler,
+	UsageHandler *UsageHandler,
+	PreferencesHandler *PreferencesHandler,
+	SecurityHandler *SecurityHandler,
+	LoginHistoryHandler *LoginHistoryHandler,
 	UploadHandler *UploadHandler,
 	MobileAuthHandler *MobileAuthHandler,
 	JwtService *jwt.JwtService,
@@ -41,7 +87,27 @@ func NewHandler(
 		UserService:              UserService,
 		EmailVerificationService: EmailVerificationService,
 		EmailVerificationHandler: EmailVerificationHandler,
+		EmailChangeService:       EmailChangeService,
+		EmailChangeHandler:       EmailChangeHandler,
 		PasswordRecoveryHandler:  PasswordRecoveryHandler,
+		InvitationService:        InvitationService,
+		InvitationHandler:        InvitationHandler,
+		RbacService:              RbacService,
+		RoleHandler:              RoleHandler,
+		OrganizationHandler:      OrganizationHandler,
+		AccountDeletionService:   AccountDeletionService,
+		AccountDeletionHandler:   AccountDeletionHandler,
+		DataExportHandler:        DataExportHandler,
+		UserImportHandler:        UserImportHandler,
+		UserExportHandler:        UserExportHandler,
+		PlanLifecycleService:     PlanLifecycleService,
+		PlanCatalog:              PlanCatalog,
+		PlanHandler:              PlanHandler,
+		BillingHandler:           BillingHandler,
+		UsageHandler:             UsageHandler,
+		PreferencesHandler:       PreferencesHandler,
+		SecurityHandler:          SecurityHandler,
+		LoginHistoryHandler:      LoginHistoryHandler,
 		UploadHandler:            UploadHandler,
 		MobileAuthHandler:        MobileAuthHandler,
 		JwtService:               JwtService,
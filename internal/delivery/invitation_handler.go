@@ -0,0 +1,127 @@
+package delivery
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/invitation"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type InvitationHandler struct {
+	service      *invitation.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewInvitationHandler(
+	service *invitation.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *InvitationHandler {
+	return &InvitationHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *InvitationHandler) VerifyInvitation(c *fiber.Ctx) error {
+	var req dto.VerifyInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.Token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	inv, err := h.service.VerifyToken(c.UserContext(), req.Token)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.InvitationResponseDTO{
+		UserID:    inv.UserID,
+		Email:     inv.Email,
+		ExpiresAt: inv.ExpiresAt,
+	})
+}
+
+func (h *InvitationHandler) VerifyInvitationByQuery(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	inv, err := h.service.VerifyToken(c.UserContext(), token)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.InvitationResponseDTO{
+		UserID:    inv.UserID,
+		Email:     inv.Email,
+		ExpiresAt: inv.ExpiresAt,
+	})
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	var req dto.AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.Token == "" || req.Password == "" {
+		return errors.New(errors.EBADREQUEST, "invitation.token_password_required")
+	}
+
+	if err := auth.PasswordRequirements(req.Password); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	if _, err := h.service.AcceptInvitation(c.UserContext(), req.Token, req.Password); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.MessageResponse{
+		Message: translate(c, "invitation.password_set"),
+	})
+}
+
+func (h *InvitationHandler) ResendInvitation(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	inv, err := h.service.ResendInvitation(c.UserContext(), id, &currentUserID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.InvitationResponseDTO{
+		UserID:    inv.UserID,
+		Email:     inv.Email,
+		ExpiresAt: inv.ExpiresAt,
+	})
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	if err := h.service.RevokeInvitation(c.UserContext(), id); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,17 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
+)
+
+// requestLocale resolves the locale of a request from the authenticated
+// user's locale, then the Accept-Language header.
+func requestLocale(c *fiber.Ctx) string {
+	userLocale, _ := c.Locals("userLocale").(string)
+	return i18n.Resolve(userLocale, c.Get(fiber.HeaderAcceptLanguage))
+}
+
+func translate(c *fiber.Ctx, key string, params ...any) string {
+	return i18n.T(requestLocale(c), key, params...)
+}
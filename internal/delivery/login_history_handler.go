@@ -0,0 +1,89 @@
+package delivery
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/loginhistory"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type LoginHistoryHandler struct {
+	service      *loginhistory.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewLoginHistoryHandler(
+	service *loginhistory.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *LoginHistoryHandler) GetMyActivity(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	return h.findActivity(c, userID)
+}
+
+func (h *LoginHistoryHandler) GetUserActivity(c *fiber.Ctx) error {
+	userID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+	return h.findActivity(c, userID)
+}
+
+// findActivity lists the sign-in attempts of the user, newest first.
+func (h *LoginHistoryHandler) findActivity(c *fiber.Ctx, userID int64) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	size, _ := strconv.Atoi(c.Query("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	events, total, err := h.service.FindByUserID(c.UserContext(), userID, page, size)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	content := make([]dto.LoginEventResponseDTO, len(events))
+	for i, e := range events {
+		content[i] = dto.LoginEventResponseDTO{
+			ID:            e.ID,
+			Email:         e.Email,
+			Method:        string(e.Method),
+			Success:       e.Success,
+			FailureReason: e.FailureReason,
+			IPAddress:     e.IPAddress,
+			UserAgent:     e.UserAgent,
+			DeviceID:      e.DeviceID,
+			CreatedAt:     e.CreatedAt,
+		}
+	}
+
+	totalPages := int(total) / size
+	if int(total)%size != 0 {
+		totalPages++
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.LoginEventPageResponse{
+		Content:       content,
+		TotalElements: total,
+		TotalPages:    totalPages,
+		Size:          size,
+		Number:        page,
+		First:         page == 1,
+		Last:          page >= totalPages,
+	})
+}
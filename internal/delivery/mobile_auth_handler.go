@@ -22,7 +22,7 @@ func NewMobileAuthHandler(authService *auth.Service, errorHandler func(c *fiber.
 func (h *MobileAuthHandler) AuthenticateWithGoogleMobile(c *fiber.Ctx) error {
 	var req dto.MobileOAuth2RequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	deviceID := req.DeviceId
@@ -39,13 +39,15 @@ func (h *MobileAuthHandler) AuthenticateWithGoogleMobile(c *fiber.Ctx) error {
 	}
 
 	response := dto.MobileLoginResponseDTO{
-		UserID:       result.UserID,
-		Email:        result.Email,
-		Name:         result.Name,
-		AccessToken:  result.AccessToken,
-		RefreshToken: result.RefreshToken,
-		ExpiresIn:    result.ExpiresIn,
-		IsNewUser:    result.IsNewUser,
+		UserID:                 result.UserID,
+		Email:                  result.Email,
+		Name:                   result.Name,
+		AccessToken:            result.AccessToken,
+		RefreshToken:           result.RefreshToken,
+		ExpiresIn:              result.ExpiresIn,
+		RefreshExpiresIn:       result.RefreshExpiresIn,
+		IsNewUser:              result.IsNewUser,
+		PasswordChangeRequired: result.PasswordChangeRequired,
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -54,7 +56,7 @@ func (h *MobileAuthHandler) AuthenticateWithGoogleMobile(c *fiber.Ctx) error {
 func (h *MobileAuthHandler) RefreshMobileToken(c *fiber.Ctx) error {
 	var req dto.MobileRefreshRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	userAgent := c.Get("User-Agent")
@@ -68,9 +70,10 @@ func (h *MobileAuthHandler) RefreshMobileToken(c *fiber.Ctx) error {
 	}
 
 	response := dto.MobileRefreshResponseDTO{
-		AccessToken:  result.AccessToken,
-		RefreshToken: result.RefreshToken,
-		ExpiresIn:    result.ExpiresIn,
+		AccessToken:      result.AccessToken,
+		RefreshToken:     result.RefreshToken,
+		ExpiresIn:        result.ExpiresIn,
+		RefreshExpiresIn: result.RefreshExpiresIn,
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -0,0 +1,458 @@
+package delivery
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/organization"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
+)
+
+type OrganizationHandler struct {
+	service      *organization.Service
+	authService  *auth.Service
+	planCatalog  *plancatalog.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewOrganizationHandler(
+	service *organization.Service,
+	authService *auth.Service,
+	planCatalog *plancatalog.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *OrganizationHandler {
+	return &OrganizationHandler{
+		service:      service,
+		authService:  authService,
+		planCatalog:  planCatalog,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *OrganizationHandler) ListOrganizations(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	activeID, _ := c.Locals("organizationID").(int64)
+
+	members, err := h.service.ListForUser(c.UserContext(), userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	response := make([]dto.OrganizationResponseDTO, 0, len(members))
+	for _, m := range members {
+		if m.Organization == nil {
+			continue
+		}
+		response = append(response, toOrganizationResponseDTO(m.Organization, m.Role, activeID))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	var req dto.OrganizationRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	activeID, _ := c.Locals("organizationID").(int64)
+
+	org, err := h.service.Create(c.UserContext(), userID, req.Name)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toOrganizationResponseDTO(org, organization.RoleOwner, activeID))
+}
+
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	activeID, _ := c.Locals("organizationID").(int64)
+
+	member, err := h.service.Get(c.UserContext(), id, userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toOrganizationResponseDTO(member.Organization, member.Role, activeID))
+}
+
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.OrganizationRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	activeID, _ := c.Locals("organizationID").(int64)
+
+	if _, err := h.service.Rename(c.UserContext(), id, userID, req.Name); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	member, err := h.service.Get(c.UserContext(), id, userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toOrganizationResponseDTO(member.Organization, member.Role, activeID))
+}
+
+func (h *OrganizationHandler) SwitchOrganization(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	currentToken := c.Cookies(jwt.RefreshTokenCookieName)
+	if currentToken == "" {
+		var req dto.SwitchOrganizationRequestDTO
+		if err := c.BodyParser(&req); err == nil {
+			currentToken = req.RefreshToken
+		}
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	session, err := h.authService.SwitchOrganization(
+		c.UserContext(),
+		userID,
+		id,
+		currentToken,
+		c.Get("User-Agent"),
+		c.IP(),
+		extractDeviceID(c),
+	)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	response := dto.RefreshResponseDTO{
+		AccessToken:      session.AccessToken,
+		ExpiresIn:        int(session.Profile.AccessTokenTTL),
+		RefreshExpiresIn: int(session.Profile.RefreshTokenTTL),
+	}
+
+	if session.Profile.ClientType == auth.ClientTypeCLI {
+		response.RefreshToken = session.RefreshToken
+	} else {
+		for _, cookie := range session.Cookies {
+			setHTTPCookieToFiber(c, cookie)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *OrganizationHandler) ListMembers(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	members, err := h.service.ListMembers(c.UserContext(), id, userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	response := make([]dto.OrganizationMemberResponseDTO, 0, len(members))
+	for _, m := range members {
+		item := dto.OrganizationMemberResponseDTO{
+			UserID:   m.UserID,
+			Role:     m.Role,
+			JoinedAt: m.CreatedAt,
+		}
+		if m.User != nil {
+			item.Name = m.User.Name
+			item.Email = m.User.Email
+		}
+		response = append(response, item)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *OrganizationHandler) UpdateMemberRole(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	memberID, err := strconv.ParseInt(c.Params("userId"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	var req dto.OrganizationMemberRoleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	if err := h.service.UpdateMemberRole(c.UserContext(), id, userID, memberID, strings.ToLower(req.Role)); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.MessageResponse{
+		Message: translate(c, "organization.member_updated"),
+	})
+}
+
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	memberID, err := strconv.ParseInt(c.Params("userId"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	if err := h.service.RemoveMember(c.UserContext(), id, userID, memberID); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *OrganizationHandler) CreateInvitation(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.OrganizationInvitationRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	inv, err := h.service.Invite(c.UserContext(), id, userID, req.Email, strings.ToLower(req.Role))
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toOrganizationInvitationResponseDTO(inv))
+}
+
+func (h *OrganizationHandler) ListInvitations(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	invitations, err := h.service.ListInvitations(c.UserContext(), id, userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	response := make([]dto.OrganizationInvitationResponseDTO, 0, len(invitations))
+	for i := range invitations {
+		response = append(response, toOrganizationInvitationResponseDTO(&invitations[i]))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *OrganizationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	invitationID, err := strconv.ParseInt(c.Params("invitationId"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "invitation.invalid_id")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	if err := h.service.RevokeInvitation(c.UserContext(), id, userID, invitationID); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *OrganizationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	var req dto.AcceptOrganizationInvitationRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if req.Token == "" {
+		return errors.New(errors.EBADREQUEST, "common.token_required")
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	activeID, _ := c.Locals("organizationID").(int64)
+
+	member, err := h.service.AcceptInvitation(c.UserContext(), req.Token, userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toOrganizationResponseDTO(member.Organization, member.Role, activeID))
+}
+
+func (h *OrganizationHandler) UpdatePlan(c *fiber.Ctx) error {
+	id, err := parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.OrganizationPlanRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	org, err := h.service.GetByID(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	plan := org.Plan
+	if req.PlanType != nil {
+		planType, err := h.planCatalog.ParsePlanType(*req.PlanType)
+		if err != nil {
+			return errors.New(errors.EBADREQUEST, "plan.invalid_type")
+		}
+		// A new plan starts from the catalog entitlements; the fields below
+		// adjust them for this organization.
+		if planType != plan.PlanType {
+			expiresAt := plan.PlanExpirationDate
+			if plan, err = h.planCatalog.Entitlements(planType, nil); err != nil {
+				return h.ErrorHandler(c, err)
+			}
+			plan.PlanExpirationDate = expiresAt
+		}
+	}
+	if req.PlanExpirationDate != nil {
+		plan.PlanExpirationDate = req.PlanExpirationDate
+	}
+	if req.MaxAccounts != nil {
+		plan.MaxAccounts = *req.MaxAccounts
+	}
+	if req.MaxCategoriesPerAccount != nil {
+		plan.MaxCategoriesPerAccount = *req.MaxCategoriesPerAccount
+	}
+	if req.MaxTransactionsPerMonth != nil {
+		plan.MaxTransactionsPerMonth = *req.MaxTransactionsPerMonth
+	}
+	for key := range req.Features {
+		if err := h.planCatalog.ValidateFeature(key); err != nil {
+			return h.ErrorHandler(c, err)
+		}
+	}
+	overrides := user.PlanOverrides{Features: req.Features}
+	overrides.Apply(&plan)
+
+	org, err = h.service.UpdatePlan(c.UserContext(), id, plan)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toOrganizationResponseDTO(org, "", 0))
+}
+
+func parseOrganizationID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, errors.New(errors.EBADREQUEST, "organization.invalid_id")
+	}
+	return id, nil
+}
+
+func toOrganizationResponseDTO(org *organization.Organization, role string, activeID int64) dto.OrganizationResponseDTO {
+	return dto.OrganizationResponseDTO{
+		ID:       org.ID,
+		Name:     org.Name,
+		Slug:     org.Slug,
+		Personal: org.Personal,
+		OwnerID:  org.OwnerID,
+		Role:     role,
+		Active:   org.ID == activeID,
+		Plan: dto.OrganizationPlanDTO{
+			PlanType:                string(org.Plan.PlanType),
+			PlanExpirationDate:      org.Plan.PlanExpirationDate,
+			MaxAccounts:             org.Plan.MaxAccounts,
+			MaxCategoriesPerAccount: org.Plan.MaxCategoriesPerAccount,
+			MaxTransactionsPerMonth: org.Plan.MaxTransactionsPerMonth,
+			Features:                org.Plan.Features,
+		},
+		CreatedAt: org.CreatedAt,
+	}
+}
+
+func toOrganizationInvitationResponseDTO(inv *organization.Invitation) dto.OrganizationInvitationResponseDTO {
+	return dto.OrganizationInvitationResponseDTO{
+		ID:             inv.ID,
+		OrganizationID: inv.OrganizationID,
+		Email:          inv.Email,
+		Role:           inv.Role,
+		ExpiresAt:      inv.ExpiresAt,
+		CreatedAt:      inv.CreatedAt,
+	}
+}
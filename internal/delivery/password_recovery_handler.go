@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/passwordRecovery"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
 )
 
 type PasswordRecoveryHandler struct {
@@ -30,18 +31,18 @@ func (h *PasswordRecoveryHandler) RequestPasswordRecovery(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return h.ErrorHandler(c, fiber.ErrBadRequest)
+		return h.ErrorHandler(c, errors.New(errors.EBADREQUEST, "common.invalid_body"))
 	}
 
 	if req.Email == "" {
-		return h.ErrorHandler(c, fiber.NewError(fiber.StatusBadRequest, "Email é obrigatório"))
+		return h.ErrorHandler(c, errors.New(errors.EINVALID, "validation.email_required"))
 	}
 
 	ctx := c.UserContext()
 	u, err := h.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 
-		return c.JSON(fiber.Map{"message": "Se o email estiver cadastrado, um link de recuperação será enviado."})
+		return c.JSON(fiber.Map{"message": translate(c, "password_recovery.requested")})
 	}
 
 	_, err = h.service.CreateAndSendRecoveryToken(ctx, u)
@@ -49,7 +50,7 @@ func (h *PasswordRecoveryHandler) RequestPasswordRecovery(c *fiber.Ctx) error {
 		return h.ErrorHandler(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Se o email estiver cadastrado, um link de recuperação será enviado."})
+	return c.JSON(fiber.Map{"message": translate(c, "password_recovery.requested")})
 }
 
 func (h *PasswordRecoveryHandler) VerifyPasswordRecovery(c *fiber.Ctx) error {
@@ -58,11 +59,11 @@ func (h *PasswordRecoveryHandler) VerifyPasswordRecovery(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return h.ErrorHandler(c, fiber.ErrBadRequest)
+		return h.ErrorHandler(c, errors.New(errors.EBADREQUEST, "common.invalid_body"))
 	}
 
 	if req.Token == "" {
-		return h.ErrorHandler(c, fiber.NewError(fiber.StatusBadRequest, "Token é obrigatório"))
+		return h.ErrorHandler(c, errors.New(errors.EINVALID, "common.token_required"))
 	}
 
 	_, err := h.service.VerifyToken(c.UserContext(), req.Token)
@@ -76,7 +77,7 @@ func (h *PasswordRecoveryHandler) VerifyPasswordRecovery(c *fiber.Ctx) error {
 func (h *PasswordRecoveryHandler) VerifyPasswordRecoveryByQuery(c *fiber.Ctx) error {
 	token := c.Query("token")
 	if token == "" {
-		return h.ErrorHandler(c, fiber.NewError(fiber.StatusBadRequest, "Token é obrigatório"))
+		return h.ErrorHandler(c, errors.New(errors.EINVALID, "common.token_required"))
 	}
 
 	_, err := h.service.VerifyToken(c.UserContext(), token)
@@ -94,11 +95,11 @@ func (h *PasswordRecoveryHandler) ResetPassword(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return h.ErrorHandler(c, fiber.ErrBadRequest)
+		return h.ErrorHandler(c, errors.New(errors.EBADREQUEST, "common.invalid_body"))
 	}
 
 	if req.Token == "" || req.Password == "" {
-		return h.ErrorHandler(c, fiber.NewError(fiber.StatusBadRequest, "Token e senha são obrigatórios"))
+		return h.ErrorHandler(c, errors.New(errors.EINVALID, "password_recovery.token_password_required"))
 	}
 
 	err := h.service.ResetPassword(c.UserContext(), req.Token, req.Password)
@@ -106,5 +107,5 @@ func (h *PasswordRecoveryHandler) ResetPassword(c *fiber.Ctx) error {
 		return h.ErrorHandler(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Senha redefinida com sucesso!"})
+	return c.JSON(fiber.Map{"message": translate(c, "password_recovery.reset_done")})
 }
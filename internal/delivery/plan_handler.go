@@ -0,0 +1,230 @@
+package delivery
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/planlifecycle"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type PlanHandler struct {
+	service      *planlifecycle.Service
+	catalog      *plancatalog.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewPlanHandler(
+	service *planlifecycle.Service,
+	catalog *plancatalog.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *PlanHandler {
+	return &PlanHandler{
+		service:      service,
+		catalog:      catalog,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *PlanHandler) StartTrial(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	u, err := h.service.StartTrial(c.UserContext(), userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag(u))
+	mapper := NewUserMapper()
+	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(u))
+}
+
+func (h *PlanHandler) FindPlanEvents(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	size, _ := strconv.Atoi(c.Query("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	events, total, err := h.service.FindEvents(c.UserContext(), id, page, size)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	content := make([]dto.PlanEventResponseDTO, len(events))
+	for i, e := range events {
+		content[i] = dto.PlanEventResponseDTO{
+			ID:        e.ID,
+			Type:      string(e.Type),
+			FromPlan:  string(e.FromPlan),
+			ToPlan:    string(e.ToPlan),
+			ExpiresAt: e.ExpiresAt,
+			ActorID:   e.ActorID,
+			Reason:    e.Reason,
+			CreatedAt: e.CreatedAt,
+		}
+		if e.ProSource != nil {
+			source := string(*e.ProSource)
+			content[i].ProSource = &source
+		}
+	}
+
+	totalPages := int(total) / size
+	if int(total)%size != 0 {
+		totalPages++
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.PlanEventPageResponse{
+		Content:       content,
+		TotalElements: total,
+		TotalPages:    totalPages,
+		Size:          size,
+		Number:        page,
+		First:         page == 1,
+		Last:          page >= totalPages,
+	})
+}
+
+func (h *PlanHandler) ListPlans(c *fiber.Ctx) error {
+	plans := h.catalog.List()
+
+	response := make([]dto.PlanResponseDTO, len(plans))
+	for i := range plans {
+		response[i] = toPlanResponseDTO(&plans[i])
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *PlanHandler) UpdatePlan(c *fiber.Ctx) error {
+	var req dto.PlanUpdateRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	code := user.PlanType(strings.ToUpper(c.Params("code")))
+	plan, err := h.catalog.Update(c.UserContext(), code, plancatalog.PlanUpdate{
+		Name:                    req.Name,
+		Rank:                    req.Rank,
+		MaxAccounts:             req.MaxAccounts,
+		MaxCategoriesPerAccount: req.MaxCategoriesPerAccount,
+		MaxTransactionsPerMonth: req.MaxTransactionsPerMonth,
+		MaxResources:            toQuotaUpdate(req.MaxResources),
+		MaxRequestsPerMonth:     toQuotaUpdate(req.MaxRequestsPerMonth),
+	})
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toPlanResponseDTO(plan))
+}
+
+func (h *PlanHandler) ResetPlanOverrides(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	u, err := h.catalog.ResetOverrides(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag(u))
+	mapper := NewUserMapper()
+	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(u))
+}
+
+func (h *PlanHandler) ListFeatures(c *fiber.Ctx) error {
+	features := h.catalog.ListFeatures()
+
+	response := make([]dto.FeatureResponseDTO, len(features))
+	for i := range features {
+		response[i] = h.toFeatureResponseDTO(&features[i])
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *PlanHandler) SaveFeature(c *fiber.Ctx) error {
+	var req dto.FeatureRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	feature, err := h.catalog.SaveFeature(c.UserContext(), strings.Clone(c.Params("key")), plancatalog.FeatureUpdate{
+		Description: req.Description,
+		Plans:       req.Plans,
+	})
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(h.toFeatureResponseDTO(feature))
+}
+
+func (h *PlanHandler) toFeatureResponseDTO(f *plancatalog.Feature) dto.FeatureResponseDTO {
+	plans := h.catalog.FeaturePlans(f.Key)
+	codes := make([]string, len(plans))
+	for i, p := range plans {
+		codes[i] = string(p)
+	}
+
+	return dto.FeatureResponseDTO{
+		Key:         f.Key,
+		Description: f.Description,
+		Plans:       codes,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+func toPlanResponseDTO(p *plancatalog.Plan) dto.PlanResponseDTO {
+	response := dto.PlanResponseDTO{
+		Code:                    string(p.Code),
+		Name:                    p.Name,
+		Rank:                    p.Rank,
+		MaxResources:            p.MaxResources,
+		MaxRequestsPerMonth:     p.MaxRequestsPerMonth,
+		MaxAccounts:             p.MaxAccounts,
+		MaxCategoriesPerAccount: p.MaxCategoriesPerAccount,
+		MaxTransactionsPerMonth: p.MaxTransactionsPerMonth,
+		Features:                p.Features,
+	}
+	if !p.UpdatedAt.IsZero() {
+		response.UpdatedAt = &p.UpdatedAt
+	}
+	return response
+}
+
+func toQuotaUpdate(value dto.NullableInt) *plancatalog.QuotaUpdate {
+	if !value.Set {
+		return nil
+	}
+	return &plancatalog.QuotaUpdate{Limit: value.Value}
+}
+
+func toPlanOverridesDTO(o *user.PlanOverrides) *dto.PlanOverridesDTO {
+	if o.IsEmpty() {
+		return nil
+	}
+	return &dto.PlanOverridesDTO{
+		MaxAccounts:             o.MaxAccounts,
+		MaxCategoriesPerAccount: o.MaxCategoriesPerAccount,
+		MaxTransactionsPerMonth: o.MaxTransactionsPerMonth,
+		Features:                o.Features,
+	}
+}
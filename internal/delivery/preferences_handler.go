@@ -0,0 +1,86 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/preferences"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type PreferencesHandler struct {
+	service      *preferences.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewPreferencesHandler(
+	service *preferences.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *PreferencesHandler {
+	return &PreferencesHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *PreferencesHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	u, err := h.service.Get(c.UserContext(), userID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag(u))
+	return c.Status(fiber.StatusOK).JSON(toPreferencesResponse(u.Metadata))
+}
+
+func (h *PreferencesHandler) UpdatePreferences(c *fiber.Ctx) error {
+	var req dto.PreferencesPatchRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	update := preferences.Update{
+		Locale:           req.Locale,
+		Currency:         req.Currency,
+		Timezone:         req.Timezone,
+		MarketingConsent: req.MarketingConsent,
+	}
+	if req.Notifications != nil {
+		update.PlanReminders = req.Notifications.PlanReminders
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	u, err := h.service.Update(c.UserContext(), userID, update)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	// The token keeps the previous locale until it is refreshed
+	c.Locals("userLocale", u.Metadata.Locale)
+
+	c.Set(fiber.HeaderETag, user.ETag(u))
+	return c.Status(fiber.StatusOK).JSON(toPreferencesResponse(u.Metadata))
+}
+
+func toPreferencesResponse(meta user.UserMetadata) dto.PreferencesResponseDTO {
+	return dto.PreferencesResponseDTO{
+		Locale:   meta.Locale,
+		Currency: meta.Currency,
+		Timezone: meta.Timezone,
+		Notifications: dto.NotificationSettingsDTO{
+			PlanReminders: meta.Notifications.PlanReminders,
+		},
+		MarketingConsent:   meta.MarketingConsent,
+		MarketingConsentAt: meta.MarketingConsentAt,
+	}
+}
@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/rbac"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type RoleHandler struct {
+	service      *rbac.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewRoleHandler(
+	service *rbac.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *RoleHandler {
+	return &RoleHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.service.ListRoles(c.UserContext())
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toRoleResponseDTOs(roles))
+}
+
+func (h *RoleHandler) GetUserRoles(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	roles, err := h.service.GetUserRoles(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.UserRolesResponseDTO{
+		UserID: id,
+		Roles:  toRoleResponseDTOs(roles),
+	})
+}
+
+func (h *RoleHandler) UpdateUserRoles(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	var req dto.UserRolesRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	roles, err := h.service.AssignRoles(c.UserContext(), id, currentUserID, req.Roles)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.UserRolesResponseDTO{
+		UserID: id,
+		Roles:  toRoleResponseDTOs(roles),
+	})
+}
+
+func toRoleResponseDTOs(roles []rbac.Role) []dto.RoleResponseDTO {
+	response := make([]dto.RoleResponseDTO, 0, len(roles))
+	for _, role := range roles {
+		permissions := make([]string, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			permissions = append(permissions, p.Name)
+		}
+
+		response = append(response, dto.RoleResponseDTO{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: permissions,
+		})
+	}
+	return response
+}
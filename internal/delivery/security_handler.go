@@ -0,0 +1,234 @@
+package delivery
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+type SecurityHandler struct {
+	service      *security.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewSecurityHandler(
+	service *security.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *SecurityHandler {
+	return &SecurityHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+// FindActivities lists suspicious activities, newest first, with the counts
+// per severity of everything matching the filter.
+func (h *SecurityHandler) FindActivities(c *fiber.Ctx) error {
+	filter, err := parseActivityFilter(c)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	size, _ := strconv.Atoi(c.Query("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	activities, total, err := h.service.FindActivities(c.UserContext(), filter, page, size)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	counts, err := h.service.CountActivities(c.UserContext(), filter)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	content := make([]dto.SuspiciousActivityResponseDTO, len(activities))
+	for i, a := range activities {
+		content[i] = dto.SuspiciousActivityResponseDTO{
+			ID:           a.ID,
+			UserID:       a.UserID,
+			ActivityType: string(a.ActivityType),
+			Severity:     string(a.Severity),
+			Endpoint:     a.Endpoint,
+			IPAddress:    a.IPAddress,
+			UserAgent:    a.UserAgent,
+			RequestCount: a.RequestCount,
+			Details:      a.Details,
+			CreatedAt:    a.CreatedAt,
+		}
+	}
+
+	totalPages := int(total) / size
+	if int(total)%size != 0 {
+		totalPages++
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.SuspiciousActivityPageResponse{
+		Content: content,
+		SeverityCounts: dto.SeverityCountsDTO{
+			Low:      counts.Low,
+			Medium:   counts.Medium,
+			High:     counts.High,
+			Critical: counts.Critical,
+			Total:    counts.Total,
+		},
+		TotalElements: total,
+		TotalPages:    totalPages,
+		Size:          size,
+		Number:        page,
+		First:         page == 1,
+		Last:          page >= totalPages,
+	})
+}
+
+func (h *SecurityHandler) FindBlocks(c *fiber.Ctx) error {
+	userID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	size, _ := strconv.Atoi(c.Query("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	blocks, total, err := h.service.FindBlocks(c.UserContext(), userID, page, size)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	content := make([]dto.SecurityBlockResponseDTO, len(blocks))
+	for i := range blocks {
+		content[i] = toSecurityBlockDTO(&blocks[i])
+	}
+
+	totalPages := int(total) / size
+	if int(total)%size != 0 {
+		totalPages++
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.SecurityBlockPageResponse{
+		Content:       content,
+		TotalElements: total,
+		TotalPages:    totalPages,
+		Size:          size,
+		Number:        page,
+		First:         page == 1,
+		Last:          page >= totalPages,
+	})
+}
+
+func (h *SecurityHandler) BlockUser(c *fiber.Ctx) error {
+	userID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	var req dto.BlockUserRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	actorID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	block, err := h.service.BlockUser(c.UserContext(), userID, actorID, req.Reason, req.BlockedUntil)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toSecurityBlockDTO(block))
+}
+
+func (h *SecurityHandler) UnblockUser(c *fiber.Ctx) error {
+	userID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	// The body is optional
+	var req dto.UnblockUserRequestDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errors.New(errors.EBADREQUEST, "common.invalid_body")
+		}
+	}
+
+	actorID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	block, err := h.service.UnblockUser(c.UserContext(), userID, actorID, req.Notes)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toSecurityBlockDTO(block))
+}
+
+func parseActivityFilter(c *fiber.Ctx) (security.ActivityFilter, error) {
+	filter := security.ActivityFilter{
+		ActivityType: security.ActivityType(strings.ToUpper(c.Query("type"))),
+		Severity:     security.Severity(strings.ToUpper(c.Query("severity"))),
+	}
+
+	if filter.ActivityType != "" && !filter.ActivityType.IsValid() {
+		return filter, errors.New(errors.EINVALID, "security.invalid_activity_type", c.Query("type"))
+	}
+	if filter.Severity != "" && !filter.Severity.IsValid() {
+		return filter, errors.New(errors.EINVALID, "security.invalid_severity", c.Query("severity"))
+	}
+
+	if value := c.Query("userId"); value != "" {
+		userID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, errors.New(errors.EBADREQUEST, "user.invalid_id")
+		}
+		filter.UserID = &userID
+	}
+
+	var err error
+	if filter.From, err = parseDateQuery(c, "from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseDateQuery(c, "to", true); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func toSecurityBlockDTO(b *security.Block) dto.SecurityBlockResponseDTO {
+	return dto.SecurityBlockResponseDTO{
+		ID:                      b.ID,
+		UserID:                  b.UserID,
+		Reason:                  b.Reason,
+		SuspiciousActivityCount: b.SuspiciousActivityCount,
+		BlockedAt:               b.BlockedAt,
+		BlockedUntil:            b.BlockedUntil,
+		BlockedBy:               b.BlockedBy,
+		UnblockedAt:             b.UnblockedAt,
+		UnblockedBy:             b.UnblockedBy,
+		Notes:                   b.Notes,
+		Active:                  b.ActiveAt(utils.Now()),
+	}
+}
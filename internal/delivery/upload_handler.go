@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
 	"github.com/lkgiovani/go-boilerplate/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -23,13 +24,13 @@ func NewUploadHandler(storageService *storage.Service, logger logger.Logger) *Up
 func (h *UploadHandler) GetUploadUrl(c *fiber.Ctx) error {
 	var req dto.UploadImageRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	result, err := h.storageService.GetPresignedUploadUrl(c.Context(), req.FileName, req.ContentType, req.ContentLength)
 	if err != nil {
 		h.logger.Error("Failed to generate presigned URL", zap.Error(err))
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate upload URL")
+		return errors.New(errors.EINTERNAL, "upload.url_failed")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(dto.UploadResponseDTO{
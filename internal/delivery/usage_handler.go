@@ -0,0 +1,114 @@
+package delivery
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/metering"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+type UsageHandler struct {
+	service      *metering.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewUsageHandler(
+	service *metering.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *UsageHandler {
+	return &UsageHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+func (h *UsageHandler) GetMyUsage(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	period := metering.PeriodStart(utils.Now())
+	response := dto.UsageResponseDTO{
+		PeriodStart: period,
+		PeriodEnd:   metering.PeriodEnd(period),
+	}
+	for _, quota := range h.service.Usage(c.UserContext(), userID) {
+		switch quota.Metric {
+		case metering.MetricRequests:
+			response.Requests = toQuotaDTO(quota)
+		case metering.MetricResources:
+			response.Resources = toQuotaDTO(quota)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetUsageReport lists the usage of every user in a period (period=YYYY-MM,
+// current month by default), heaviest users first.
+func (h *UsageHandler) GetUsageReport(c *fiber.Ctx) error {
+	period := metering.PeriodStart(utils.Now())
+	if value := c.Query("period"); value != "" {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			return errors.New(errors.EBADREQUEST, "usage.invalid_period")
+		}
+		period = parsed
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	size, _ := strconv.Atoi(c.Query("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	rows, total, err := h.service.Report(c.UserContext(), period, page, size)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	content := make([]dto.UsageReportRowDTO, len(rows))
+	for i, r := range rows {
+		content[i] = dto.UsageReportRowDTO{
+			UserID:    r.UserID,
+			Name:      r.Name,
+			Email:     r.Email,
+			PlanType:  r.PlanType,
+			Requests:  r.Requests,
+			Resources: r.Resources,
+		}
+	}
+
+	totalPages := int(total) / size
+	if int(total)%size != 0 {
+		totalPages++
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.UsageReportPageResponse{
+		Period:        period.Format("2006-01"),
+		Content:       content,
+		TotalElements: total,
+		TotalPages:    totalPages,
+		Size:          size,
+		Number:        page,
+		First:         page == 1,
+		Last:          page >= totalPages,
+	})
+}
+
+func toQuotaDTO(q metering.Quota) dto.QuotaDTO {
+	quota := dto.QuotaDTO{Used: q.Used, Limit: q.Limit}
+	if q.Limit != nil {
+		remaining := q.Remaining()
+		quota.Remaining = &remaining
+	}
+	return quota
+}
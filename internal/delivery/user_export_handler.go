@@ -0,0 +1,138 @@
+package delivery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/userexport"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type UserExportHandler struct {
+	service      *userexport.Service
+	logger       logger.Logger
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewUserExportHandler(
+	service *userexport.Service,
+	logger logger.Logger,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *UserExportHandler {
+	return &UserExportHandler{
+		service:      service,
+		logger:       logger,
+		ErrorHandler: errorHandler,
+	}
+}
+
+// ExportUsers accepts the same filters as the admin list. Small exports are
+// streamed in the response; large ones, or any export with async=true, run
+// in the background and return the job to poll.
+func (h *UserExportHandler) ExportUsers(c *fiber.Ctx) error {
+	filter, err := parseUserListFilter(c)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	req := userexport.Request{
+		// The stream writer and the background job outlive the request, so
+		// nothing may reference the request buffers.
+		Filter: detachListFilter(filter),
+		Format: userexport.Format(strings.ToLower(c.Query("format", "csv"))),
+		Query:  string(c.Context().QueryArgs().QueryString()),
+	}
+	if columns := c.Query("columns"); columns != "" {
+		req.Columns = strings.Split(strings.Clone(columns), ",")
+	}
+
+	cols, small, err := h.service.Prepare(c.UserContext(), req)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	if !small || c.QueryBool("async", false) {
+		adminID, ok := c.Locals("userID").(int64)
+		if !ok {
+			return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+		}
+
+		job, err := h.service.StartAsync(c.UserContext(), adminID, cols, req)
+		if err != nil {
+			return h.ErrorHandler(c, err)
+		}
+
+		response := toUserExportJobResponse(job, "")
+		response.Message = translate(c, "export.started")
+		return c.Status(fiber.StatusAccepted).JSON(response)
+	}
+
+	filename := fmt.Sprintf("users-%s.%s", utils.Now().Format("20060102-150405"), req.Format)
+	c.Set(fiber.HeaderContentType, req.Format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		count, err := h.service.Write(context.Background(), w, req.Format, cols, req.Filter)
+		if err != nil {
+			// The status line is already sent, so the client only sees a
+			// truncated file.
+			h.logger.Error("User export stream failed", zap.Int64("rows", count), zap.Error(err))
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+func (h *UserExportHandler) GetExport(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("exportId"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "export.invalid_id")
+	}
+
+	job, err := h.service.GetJob(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	downloadURL, err := h.service.DownloadURL(c.UserContext(), job)
+	if err != nil {
+		return h.ErrorHandler(c, errors.New(errors.EINTERNAL, "export.download_link_failed"))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toUserExportJobResponse(job, downloadURL))
+}
+
+func detachListFilter(f user.ListFilter) user.ListFilter {
+	f.Keyword = strings.Clone(f.Keyword)
+	f.Source = strings.Clone(f.Source)
+	f.PlanType = user.PlanType(strings.Clone(string(f.PlanType)))
+	f.AccessMode = user.AccessMode(strings.Clone(string(f.AccessMode)))
+	f.ReputationStatus = user.ReputationStatus(strings.Clone(string(f.ReputationStatus)))
+	f.Sort.Field = strings.Clone(f.Sort.Field)
+	return f
+}
+
+func toUserExportJobResponse(job *userexport.ExportJob, downloadURL string) dto.UserExportJobResponseDTO {
+	return dto.UserExportJobResponseDTO{
+		ID:          job.ID,
+		Format:      string(job.Format),
+		Status:      string(job.Status),
+		Columns:     strings.Split(job.Columns, ","),
+		RowCount:    job.RowCount,
+		FileSize:    job.FileSize,
+		DownloadURL: downloadURL,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+}
@@ -3,11 +3,14 @@ package delivery
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/rbac"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/middleware"
 )
 
 type UserMapper struct{}
@@ -17,6 +20,11 @@ func NewUserMapper() *UserMapper {
 }
 
 func (m *UserMapper) ToResponseDTO(u *user.User) dto.UserResponseDTO {
+	var deletedAt *time.Time
+	if u.DeletedAt.Valid {
+		deletedAt = &u.DeletedAt.Time
+	}
+
 	return dto.UserResponseDTO{
 		ID:         u.ID,
 		Name:       u.Name,
@@ -29,6 +37,9 @@ func (m *UserMapper) ToResponseDTO(u *user.User) dto.UserResponseDTO {
 		LastAccess: u.LastAccess,
 		CreatedAt:  u.CreatedAt,
 		UpdatedAt:  u.UpdatedAt,
+		DeletedAt:  deletedAt,
+		PurgeAfter: u.PurgeAfter,
+		Version:    u.Version,
 	}
 }
 
@@ -49,12 +60,12 @@ func (m *UserMapper) ToMetadataDTO(meta user.UserMetadata) dto.UserMetadataDTO {
 		MaxAccounts:             meta.MaxAccounts,
 		MaxCategoriesPerAccount: meta.MaxCategoriesPerAccount,
 		MaxTransactionsPerMonth: meta.MaxTransactionsPerMonth,
-		CanExportData:           meta.CanExportData,
-		CanUseReports:           meta.CanUseReports,
-		CanUseAdvancedFeatures:  meta.CanUseAdvancedFeatures,
-		CanCreateBudgets:        meta.CanCreateBudgets,
-		CanUseGoals:             meta.CanUseGoals,
+		Features:                meta.Features,
+		PlanOverrides:           toPlanOverridesDTO(meta.PlanOverrides),
+		TrialUsedAt:             meta.TrialUsedAt,
 		EmailVerified:           meta.EmailVerified,
+		MustSetPassword:         meta.MustSetPassword,
+		PasswordChangeRequired:  meta.PasswordChangeRequired,
 		ReputationStatus:        string(meta.ReputationStatus),
 		SuspiciousActivityCount: meta.SuspiciousActivityCount,
 		LastSecurityCheck:       meta.LastSecurityCheck,
@@ -62,6 +73,7 @@ func (m *UserMapper) ToMetadataDTO(meta user.UserMetadata) dto.UserMetadataDTO {
 		Notes:                   meta.Notes,
 		Locale:                  meta.Locale,
 		Currency:                meta.Currency,
+		Timezone:                meta.Timezone,
 	}
 }
 
@@ -87,31 +99,68 @@ func (m *UserMapper) ToPageResponse(users []user.User, total int64, page, size i
 	}
 }
 
+func (m *UserMapper) ToCursorPageResponse(users []user.User, next *user.Cursor, size int) dto.CursorPageResponse {
+	content := make([]dto.UserResponseDTO, len(users))
+	for i, u := range users {
+		content[i] = m.ToResponseDTO(&u)
+	}
+
+	var nextCursor *string
+	if next != nil {
+		encoded := next.Encode()
+		nextCursor = &encoded
+	}
+
+	return dto.CursorPageResponse{
+		Content:    content,
+		Size:       size,
+		NextCursor: nextCursor,
+		HasNext:    next != nil,
+	}
+}
+
 func (h *Handler) SaveUser(c *fiber.Ctx) error {
 	var req dto.UserPostRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
+	// Granting admin or roles is role management, not part of users:create
+	canManageRoles := middleware.HasPermission(c, rbac.PermRolesManage)
+
 	newUser := &user.User{
 		Name:     req.Name,
 		Email:    req.Email,
-		Admin:    req.Admin != nil && *req.Admin,
+		Admin:    canManageRoles && req.Admin != nil && *req.Admin,
 		Active:   req.Active == nil || *req.Active,
 		Source:   "LOCAL",
-		Metadata: user.NewDefaultMetadata(),
-	}
-	newUser.Metadata.EmailVerified = true
-
-	if req.Password != nil {
-		newUser.Password = req.Password
+		Metadata: h.PlanCatalog.DefaultMetadata(),
 	}
+	newUser.Metadata.MustSetPassword = true
 
 	if req.Source != nil {
 		newUser.Source = *req.Source
 	}
 
-	if err := h.UserService.Repository.Create(c.Context(), newUser); err != nil {
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	if err := h.UserService.Repository.Create(c.UserContext(), newUser); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	// Without roles or an invitation the account could never be activated
+	if canManageRoles && len(req.Roles) > 0 {
+		if _, err := h.RbacService.AssignRoles(c.UserContext(), newUser.ID, currentUserID, req.Roles); err != nil {
+			_ = h.UserService.Repository.Discard(c.UserContext(), newUser.ID)
+			return h.ErrorHandler(c, err)
+		}
+	}
+
+	if _, err := h.InvitationService.CreateAndSendInvitation(c.UserContext(), newUser, &currentUserID); err != nil {
+		_ = h.UserService.Repository.Discard(c.UserContext(), newUser.ID)
 		return h.ErrorHandler(c, err)
 	}
 
@@ -122,7 +171,7 @@ func (h *Handler) SaveUser(c *fiber.Ctx) error {
 func (h *Handler) SignupUser(c *fiber.Ctx) error {
 	var req dto.SignupUserRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	newUser := &user.User{
@@ -131,12 +180,12 @@ func (h *Handler) SignupUser(c *fiber.Ctx) error {
 		Admin:    false,
 		Active:   true,
 		Source:   "LOCAL",
-		Metadata: user.NewDefaultMetadata(),
+		Metadata: h.PlanCatalog.DefaultMetadata(),
 	}
 
 	newUser.Password = &req.Password
 
-	if err := h.AuthService.Register(c.Context(), newUser); err != nil {
+	if err := h.AuthService.Register(c.UserContext(), newUser); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
@@ -147,7 +196,7 @@ func (h *Handler) SignupUser(c *fiber.Ctx) error {
 func (h *Handler) ResendVerification(c *fiber.Ctx) error {
 	var req dto.ResendVerificationRequest
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	if err := h.EmailVerificationService.ResendVerification(c.UserContext(), req.Email); err != nil {
@@ -155,49 +204,65 @@ func (h *Handler) ResendVerification(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).JSON(dto.MessageResponse{
-		Message: "Email de verificação reenviado com sucesso!",
+		Message: translate(c, "email_verification.resent"),
 	})
 }
 
 func (h *Handler) UpdateUser(c *fiber.Ctx) error {
 	var req dto.UserPutRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	email := c.Locals("userEmail").(string)
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
 
-	existingUser, err := h.UserService.Repository.GetByEmail(c.Context(), email)
+	existingUser, err := h.UserService.Repository.GetByID(c.UserContext(), userID)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
-	existingUser.Name = req.Name
-	if existingUser.Email != req.Email {
-		existingUser.Email = req.Email
-		existingUser.Metadata.EmailVerified = false
+	if err := user.CheckVersion(c.UserContext(), existingUser); err != nil {
+		return h.ErrorHandler(c, err)
 	}
+
+	// A new email is only applied once confirmed through the link sent to it
+	var pendingEmail *string
+	if req.Email != "" && req.Email != existingUser.Email {
+		request, err := h.EmailChangeService.RequestChange(c.UserContext(), existingUser.ID, req.Email)
+		if err != nil {
+			return h.ErrorHandler(c, err)
+		}
+		pendingEmail = &request.NewEmail
+	}
+
+	existingUser.Name = req.Name
 	if req.ImgURL != nil {
 		existingUser.ImgURL = req.ImgURL
 	}
 
-	if err := h.UserService.Repository.Update(c.Context(), existingUser); err != nil {
+	if err := h.UserService.Repository.Update(c.UserContext(), existingUser); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(existingUser))
 	mapper := NewUserMapper()
-	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(existingUser))
+	response := mapper.ToResponseDTO(existingUser)
+	response.PendingEmail = pendingEmail
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 func (h *Handler) UpdatePassword(c *fiber.Ctx) error {
 	var req dto.UserPutPasswordRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
 	email := c.Locals("userEmail").(string)
 
-	existingUser, err := h.UserService.Repository.GetByEmail(c.Context(), email)
+	existingUser, err := h.UserService.Repository.GetByEmail(c.UserContext(), email)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
@@ -207,7 +272,7 @@ func (h *Handler) UpdatePassword(c *fiber.Ctx) error {
 		currentPassword = *req.CurrentPassword
 	}
 
-	if err := h.UserService.Repository.ChangePassword(c.Context(), existingUser.ID, currentPassword, req.Password); err != nil {
+	if err := h.UserService.Repository.ChangePassword(c.UserContext(), existingUser.ID, currentPassword, req.Password); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
@@ -217,10 +282,10 @@ func (h *Handler) UpdatePassword(c *fiber.Ctx) error {
 func (h *Handler) AddImage(c *fiber.Ctx) error {
 	var req dto.UploadImageRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	result, err := h.StorageService.GetPresignedUploadUrl(c.Context(), req.FileName, req.ContentType, req.ContentLength)
+	result, err := h.StorageService.GetPresignedUploadUrl(c.UserContext(), req.FileName, req.ContentType, req.ContentLength)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
@@ -235,14 +300,15 @@ func (h *Handler) FindUserByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
-	foundUser, err := h.UserService.Repository.GetByID(c.Context(), id)
+	foundUser, err := h.UserService.Repository.GetByID(c.UserContext(), id)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(foundUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(foundUser))
 }
@@ -250,11 +316,12 @@ func (h *Handler) FindUserByID(c *fiber.Ctx) error {
 func (h *Handler) FindUserByEmail(c *fiber.Ctx) error {
 	email := c.Params("email")
 
-	foundUser, err := h.UserService.Repository.GetByEmail(c.Context(), email)
+	foundUser, err := h.UserService.Repository.GetByEmail(c.UserContext(), email)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(foundUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(foundUser))
 }
@@ -263,11 +330,12 @@ func (h *Handler) GetCurrentUser(c *fiber.Ctx) error {
 
 	email := c.Locals("userEmail").(string)
 
-	foundUser, err := h.UserService.Repository.GetByEmail(c.Context(), email)
+	foundUser, err := h.UserService.Repository.GetByEmail(c.UserContext(), email)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(foundUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(foundUser))
 }
@@ -276,15 +344,18 @@ func (h *Handler) DeleteUserByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
-	currentUserID := c.Locals("userID").(int64)
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
 	if currentUserID == id {
-		return errors.Errorf(errors.EINVALID, "You cannot delete your own account")
+		return errors.New(errors.EINVALID, "user.cannot_delete_self")
 	}
 
-	if err := h.UserService.Repository.Delete(c.Context(), id); err != nil {
+	if _, err := h.AccountDeletionService.DeleteUsers(c.UserContext(), []int64{id}, currentUserID); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
@@ -294,7 +365,7 @@ func (h *Handler) DeleteUserByID(c *fiber.Ctx) error {
 func (h *Handler) DeleteUsersByIDs(c *fiber.Ctx) error {
 	idsParam := c.Query("ids")
 	if idsParam == "" {
-		return errors.Errorf(errors.EBADREQUEST, "IDs parameter is required")
+		return errors.New(errors.EBADREQUEST, "user.ids_required")
 	}
 
 	parts := strings.Split(idsParam, ",")
@@ -307,65 +378,179 @@ func (h *Handler) DeleteUsersByIDs(c *fiber.Ctx) error {
 	}
 
 	if len(ids) == 0 {
-		return errors.Errorf(errors.EBADREQUEST, "Valid IDs are required")
+		return errors.New(errors.EBADREQUEST, "user.valid_ids_required")
+	}
+
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
 	}
 
-	if err := h.UserService.Repository.DeleteByIDs(c.Context(), ids); err != nil {
+	if _, err := h.AccountDeletionService.DeleteUsers(c.UserContext(), ids, currentUserID); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+const maxUserPageSize = 100
+
 func (h *Handler) FindAllUsers(c *fiber.Ctx) error {
-	keyword := c.Query("keyword", "")
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	size, _ := strconv.Atoi(c.Query("size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+	if size > maxUserPageSize {
+		size = maxUserPageSize
+	}
 
-	var users []user.User
-	var total int64
-	var err error
+	filter, err := parseUserListFilter(c)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
 
-	if keyword != "" {
-		users, total, err = h.UserService.Repository.FindAllWithFilter(c.Context(), keyword, page, size)
-	} else {
-		users, total, err = h.UserService.Repository.FindAll(c.Context(), page, size)
+	mapper := NewUserMapper()
+
+	// Keyset pagination is used when the cursor parameter is present (empty for the first page)
+	if c.Context().QueryArgs().Has("cursor") {
+		var cursor *user.Cursor
+		if token := c.Query("cursor"); token != "" {
+			cursor, err = user.DecodeCursor(token)
+			if err != nil || !cursor.Matches(filter.Sort) {
+				return h.ErrorHandler(c, errors.New(errors.EINVALID, "common.invalid_cursor"))
+			}
+		}
+
+		users, next, err := h.UserService.Repository.FindWithCursor(c.UserContext(), filter, cursor, size)
+		if err != nil {
+			return h.ErrorHandler(c, err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(mapper.ToCursorPageResponse(users, next, size))
 	}
 
+	users, total, err := h.UserService.Repository.FindAllWithFilter(c.UserContext(), filter, page, size)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
-	mapper := NewUserMapper()
 	pageResponse := mapper.ToPageResponse(users, total, page, size)
 
 	return c.Status(fiber.StatusOK).JSON(pageResponse)
 }
 
+func parseUserListFilter(c *fiber.Ctx) (user.ListFilter, error) {
+	filter := user.ListFilter{
+		Keyword:          c.Query("keyword"),
+		Source:           c.Query("source"),
+		PlanType:         user.PlanType(strings.ToUpper(c.Query("planType"))),
+		AccessMode:       user.AccessMode(strings.ToUpper(c.Query("accessMode"))),
+		ReputationStatus: user.ReputationStatus(strings.ToUpper(c.Query("reputationStatus"))),
+		Sort:             user.DefaultSortOrder,
+	}
+
+	var err error
+	if filter.Active, err = parseBoolQuery(c, "active"); err != nil {
+		return filter, err
+	}
+	if filter.Admin, err = parseBoolQuery(c, "admin"); err != nil {
+		return filter, err
+	}
+	if filter.EmailVerified, err = parseBoolQuery(c, "emailVerified"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = parseDateQuery(c, "createdFrom", false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseDateQuery(c, "createdTo", true); err != nil {
+		return filter, err
+	}
+	if filter.LastAccessFrom, err = parseDateQuery(c, "lastAccessFrom", false); err != nil {
+		return filter, err
+	}
+	if filter.LastAccessTo, err = parseDateQuery(c, "lastAccessTo", true); err != nil {
+		return filter, err
+	}
+
+	// sort=<field>[,asc|desc]
+	if sort := c.Query("sort"); sort != "" {
+		field, direction, _ := strings.Cut(sort, ",")
+		if !user.IsValidSortField(field) {
+			return filter, errors.New(errors.EINVALID, "common.invalid_sort", field)
+		}
+		filter.Sort = user.SortOrder{Field: field, Desc: strings.EqualFold(direction, "desc")}
+	}
+
+	return filter, nil
+}
+
+func parseBoolQuery(c *fiber.Ctx, key string) (*bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.New(errors.EINVALID, "common.invalid_bool", key)
+	}
+	return &b, nil
+}
+
+// parseDateQuery accepts RFC 3339 timestamps or plain dates. Plain dates used
+// as an upper bound include the whole day.
+func parseDateQuery(c *fiber.Ctx, key string, upperBound bool) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New(errors.EINVALID, "common.invalid_date", key)
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
 func (h *Handler) ToggleUserStatus(c *fiber.Ctx) error {
 	userIDParam := c.Params("userId")
 	userID, err := strconv.ParseInt(userIDParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	activeParam := c.Query("active")
 	active := activeParam == "true"
 
-	currentUserID := c.Locals("userID").(int64)
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
 	if currentUserID == userID && !active {
-		return errors.Errorf(errors.EINVALID, "You cannot deactivate your own account")
+		return errors.New(errors.EINVALID, "user.cannot_deactivate_self")
 	}
 
-	if err := h.UserService.Repository.ToggleStatus(c.Context(), userID, active); err != nil {
+	if err := h.UserService.Repository.ToggleStatus(c.UserContext(), userID, active); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
-	updatedUser, err := h.UserService.Repository.GetByID(c.Context(), userID)
+	updatedUser, err := h.UserService.Repository.GetByID(c.UserContext(), userID)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
@@ -374,15 +559,15 @@ func (h *Handler) UpdateUserAdmin(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	var req dto.UserPutRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	existingUser, err := h.UserService.Repository.GetByID(c.Context(), id)
+	existingUser, err := h.UserService.Repository.GetByID(c.UserContext(), id)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
@@ -396,10 +581,11 @@ func (h *Handler) UpdateUserAdmin(c *fiber.Ctx) error {
 		existingUser.ImgURL = req.ImgURL
 	}
 
-	if err := h.UserService.Repository.Update(c.Context(), existingUser); err != nil {
+	if err := h.UserService.Repository.Update(c.UserContext(), existingUser); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(existingUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(existingUser))
 }
@@ -408,38 +594,99 @@ func (h *Handler) UpdatePasswordAdmin(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	var req dto.UserPutPasswordRequestDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if err := h.UserService.Repository.ResetUserPassword(c.UserContext(), id, req.Password); err != nil {
+		return h.ErrorHandler(c, err)
 	}
 
-	if err := h.UserService.Repository.ResetUserPassword(c.Context(), id, req.Password); err != nil {
+	if err := h.AuthService.RevokeAllRefreshTokens(c.UserContext(), id, ""); err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusOK)
 }
 
+func (h *Handler) ForcePasswordChange(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
+	}
+
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	if err := h.AuthService.RequirePasswordChange(c.UserContext(), id, currentUserID); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	existingUser, err := h.UserService.Repository.GetByID(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag(existingUser))
+	mapper := NewUserMapper()
+	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(existingUser))
+}
+
+func (h *Handler) ForcePasswordChangeBulk(c *fiber.Ctx) error {
+	var req dto.UserForcePasswordChangeDTO
+	if err := c.BodyParser(&req); err != nil {
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
+	}
+
+	if !req.All && len(req.IDs) == 0 {
+		return errors.New(errors.EBADREQUEST, "user.ids_or_all_required")
+	}
+
+	ids := req.IDs
+	if req.All {
+		ids = nil
+	}
+
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	affected, err := h.AuthService.RequirePasswordChangeForUsers(c.UserContext(), ids, currentUserID)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ForcePasswordChangeResponseDTO{
+		Affected: affected,
+	})
+}
+
 func (h *Handler) UpdateAccessMode(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	var req dto.UserUpdateAccessModeDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	updatedUser, err := h.UserService.Repository.UpdateAccessMode(c.Context(), id, req.AccessMode)
+	updatedUser, err := h.UserService.Repository.UpdateAccessMode(c.UserContext(), id, req.AccessMode)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
@@ -448,19 +695,20 @@ func (h *Handler) UpdateFeatures(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	var req dto.UserUpdateFeaturesDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	updatedUser, err := h.UserService.Repository.UpdateFeatures(c.Context(), id, req.CanCreateBudgets, req.CanExportData, req.CanUseReports, req.CanUseGoals)
+	updatedUser, err := h.PlanCatalog.SetFeatureOverrides(c.UserContext(), id, req.Features)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
@@ -469,19 +717,20 @@ func (h *Handler) UpdateLimits(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	var req dto.UserUpdateLimitsDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	updatedUser, err := h.UserService.Repository.UpdateLimits(c.Context(), id, req.MaxAccounts, req.MaxTransactionsPerMonth, req.MaxCategoriesPerAccount)
+	updatedUser, err := h.UserService.Repository.UpdateLimits(c.UserContext(), id, req.MaxAccounts, req.MaxTransactionsPerMonth, req.MaxCategoriesPerAccount)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
@@ -490,19 +739,25 @@ func (h *Handler) GrantLifetimePro(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
 	var req dto.UserGrantLifetimeProDTO
 	if err := c.BodyParser(&req); err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid request body")
+		return errors.New(errors.EBADREQUEST, "common.invalid_body")
 	}
 
-	updatedUser, err := h.UserService.Repository.GrantLifetimePro(c.Context(), id, req.Reason)
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	updatedUser, err := h.PlanLifecycleService.GrantLifetimePro(c.UserContext(), currentUserID, id, req.Reason)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
@@ -511,14 +766,15 @@ func (h *Handler) EnsureMetadata(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
-	updatedUser, err := h.UserService.Repository.EnsureMetadata(c.Context(), id)
+	updatedUser, err := h.UserService.Repository.EnsureMetadata(c.UserContext(), id, h.PlanCatalog.DefaultMetadata())
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
@@ -527,19 +783,23 @@ func (h *Handler) RevokeLifetimePro(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		return errors.Errorf(errors.EBADREQUEST, "Invalid user ID")
+		return errors.New(errors.EBADREQUEST, "user.invalid_id")
 	}
 
-	currentUserID := c.Locals("userID").(int64)
+	currentUserID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
 	if currentUserID == id {
-		return errors.Errorf(errors.EINVALID, "You cannot revoke your own Lifetime Pro status")
+		return errors.New(errors.EINVALID, "user.cannot_revoke_own_lifetime_pro")
 	}
 
-	updatedUser, err := h.UserService.Repository.RevokeLifetimePro(c.Context(), id)
+	updatedUser, err := h.PlanLifecycleService.RevokeLifetimePro(c.UserContext(), currentUserID, id)
 	if err != nil {
 		return h.ErrorHandler(c, err)
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag(updatedUser))
 	mapper := NewUserMapper()
 	return c.Status(fiber.StatusOK).JSON(mapper.ToResponseDTO(updatedUser))
 }
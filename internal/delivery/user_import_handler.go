@@ -0,0 +1,163 @@
+package delivery
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/delivery/dto"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/userimport"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type UserImportHandler struct {
+	service      *userimport.Service
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+func NewUserImportHandler(
+	service *userimport.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+) *UserImportHandler {
+	return &UserImportHandler{
+		service:      service,
+		ErrorHandler: errorHandler,
+	}
+}
+
+// StartImport accepts the file either as the multipart field "file" or as the
+// raw request body. The format comes from the "format" query parameter, the
+// file extension or the content type, in that order.
+func (h *UserImportHandler) StartImport(c *fiber.Ctx) error {
+	var (
+		data     []byte
+		filename string
+	)
+
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return errors.New(errors.EBADREQUEST, "import.invalid_file")
+		}
+		defer file.Close()
+
+		if data, err = io.ReadAll(file); err != nil {
+			return errors.New(errors.EBADREQUEST, "import.invalid_file")
+		}
+		filename = fileHeader.Filename
+	} else {
+		data = c.Body()
+	}
+
+	if len(data) == 0 {
+		return errors.New(errors.EBADREQUEST, "import.file_required")
+	}
+
+	format, ok := detectImportFormat(c.Query("format"), filename, string(c.Request().Header.ContentType()))
+	if !ok {
+		return errors.New(errors.EBADREQUEST, "import.unsupported_format")
+	}
+
+	opts := userimport.Options{
+		Format:          format,
+		DryRun:          c.QueryBool("dryRun", false),
+		SendInvitations: c.QueryBool("sendInvitations", false),
+	}
+
+	adminID, ok := c.Locals("userID").(int64)
+	if !ok {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+
+	job, err := h.service.Start(c.UserContext(), adminID, data, opts)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(toUserImportJobResponse(c, job))
+}
+
+func (h *UserImportHandler) GetImport(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("importId"), 10, 64)
+	if err != nil {
+		return errors.New(errors.EBADREQUEST, "import.invalid_id")
+	}
+
+	job, err := h.service.GetJob(c.UserContext(), id)
+	if err != nil {
+		return h.ErrorHandler(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toUserImportJobResponse(c, job))
+}
+
+func detectImportFormat(format, filename, contentType string) (userimport.Format, bool) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			format = "csv"
+		case ".jsonl", ".ndjson":
+			format = "jsonl"
+		}
+	}
+
+	if format == "" {
+		switch {
+		case strings.HasPrefix(contentType, "text/csv"):
+			format = "csv"
+		case strings.HasPrefix(contentType, "application/x-ndjson"), strings.HasPrefix(contentType, "application/jsonl"):
+			format = "jsonl"
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "csv":
+		return userimport.FormatCSV, true
+	case "jsonl", "ndjson":
+		return userimport.FormatJSONL, true
+	default:
+		return "", false
+	}
+}
+
+func toUserImportJobResponse(c *fiber.Ctx, job *userimport.ImportJob) dto.UserImportJobResponseDTO {
+	rowErrors := make([]dto.UserImportErrorDTO, len(job.Errors))
+	for i, e := range job.Errors {
+		params := make([]any, len(e.Params))
+		for j, param := range e.Params {
+			params[j] = param
+		}
+
+		rowErrors[i] = dto.UserImportErrorDTO{
+			Row:     e.Row,
+			Email:   e.Email,
+			Message: translate(c, e.Message, params...),
+		}
+	}
+
+	var message *string
+	if job.Message != nil {
+		translated := translate(c, *job.Message)
+		message = &translated
+	}
+
+	return dto.UserImportJobResponseDTO{
+		ID:              job.ID,
+		Format:          string(job.Format),
+		Status:          string(job.Status),
+		DryRun:          job.DryRun,
+		SendInvitations: job.SendInvitations,
+		TotalRows:       job.TotalRows,
+		ValidRows:       job.ValidRows,
+		ProcessedRows:   job.ProcessedRows,
+		CreatedRows:     job.CreatedRows,
+		FailedRows:      job.FailedRows,
+		Errors:          rowErrors,
+		Message:         message,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		CompletedAt:     job.CompletedAt,
+	}
+}
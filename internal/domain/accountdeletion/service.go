@@ -0,0 +1,188 @@
+package accountdeletion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/encrypt"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// SessionStore is the subset of the auth repository needed to end the
+// sessions of deleted accounts.
+type SessionStore interface {
+	RevokeRefreshTokensByUserIDs(ctx context.Context, userIDs []int64) error
+	DeleteRefreshTokensByUserID(ctx context.Context, userID int64) error
+}
+
+// ExportStore removes the personal data exports of purged accounts.
+type ExportStore interface {
+	DeleteUserExports(ctx context.Context, userID int64) error
+}
+
+type Service struct {
+	userRepo       user.UserService
+	sessions       SessionStore
+	exports        ExportStore
+	storageService *storage.Service
+	gracePeriod    time.Duration
+	batchSize      int
+	logger         logger.Logger
+}
+
+func NewService(
+	userRepo user.UserService,
+	sessions SessionStore,
+	exports ExportStore,
+	storageService *storage.Service,
+	gracePeriodDays int,
+	batchSize int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		userRepo:       userRepo,
+		sessions:       sessions,
+		exports:        exports,
+		storageService: storageService,
+		gracePeriod:    time.Duration(gracePeriodDays) * 24 * time.Hour,
+		batchSize:      batchSize,
+		logger:         logger,
+	}
+}
+
+func (s *Service) GracePeriod() time.Duration {
+	return s.gracePeriod
+}
+
+func (s *Service) ScheduleSelfDeletion(ctx context.Context, userID int64, password string) (time.Time, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return time.Time{}, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if u.Password != nil {
+		if password == "" {
+			return time.Time{}, errors.New(errors.EBADREQUEST, "account.password_required")
+		}
+		if err := encrypt.VerifyPassword(password, *u.Password); err != nil {
+			return time.Time{}, errors.New(errors.EUNAUTHORIZED, "account.password_incorrect")
+		}
+	}
+
+	purgeAfter := utils.Now().Add(s.gracePeriod)
+	if _, err := s.userRepo.Delete(ctx, u.ID, u.ID, purgeAfter); err != nil {
+		s.logger.Error("Failed to schedule account deletion", zap.Int64("userId", u.ID), zap.Error(err))
+		return time.Time{}, errors.New(errors.EINTERNAL, "account.delete_failed")
+	}
+
+	if err := s.sessions.RevokeRefreshTokensByUserIDs(ctx, []int64{u.ID}); err != nil {
+		s.logger.Warn("Failed to revoke sessions of deleted account", zap.Int64("userId", u.ID), zap.Error(err))
+	}
+
+	s.logger.Info("Account deletion scheduled", zap.Int64("userId", u.ID), zap.Time("purgeAfter", purgeAfter))
+	return purgeAfter, nil
+}
+
+func (s *Service) DeleteUsers(ctx context.Context, ids []int64, adminID int64) (int64, error) {
+	for _, id := range ids {
+		if id == adminID {
+			return 0, errors.New(errors.EINVALID, "user.cannot_delete_self")
+		}
+	}
+
+	affected, err := s.userRepo.DeleteByIDs(ctx, ids, adminID, utils.Now().Add(s.gracePeriod))
+	if err != nil {
+		if user.IsVersionConflict(err) {
+			return 0, err
+		}
+		s.logger.Error("Failed to delete users", zap.Int64s("userIds", ids), zap.Error(err))
+		return 0, errors.New(errors.EINTERNAL, "user.delete_failed")
+	}
+
+	if affected == 0 {
+		return 0, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if err := s.sessions.RevokeRefreshTokensByUserIDs(ctx, ids); err != nil {
+		s.logger.Warn("Failed to revoke sessions of deleted users", zap.Error(err))
+	}
+
+	s.logger.Info("Users deleted", zap.Int64s("userIds", ids), zap.Int64("deletedBy", adminID))
+	return affected, nil
+}
+
+func (s *Service) Restore(ctx context.Context, userID int64) (*user.User, error) {
+	if _, err := s.userRepo.GetDeletedByID(ctx, userID); err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.deleted_not_found")
+	}
+
+	if err := s.userRepo.Restore(ctx, userID); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		return nil, errors.New(errors.EINTERNAL, "user.restore_failed")
+	}
+
+	s.logger.Info("Account restored", zap.Int64("userId", userID))
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+func (s *Service) ListDeleted(ctx context.Context, page, size int) ([]user.User, int64, error) {
+	users, total, err := s.userRepo.FindDeleted(ctx, page, size)
+	if err != nil {
+		return nil, 0, errors.New(errors.EINTERNAL, "user.list_deleted_failed")
+	}
+	return users, total, nil
+}
+
+// PurgeExpired permanently removes accounts whose grace period is over,
+// including their stored files, data exports and refresh tokens.
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	users, err := s.userRepo.FindPurgeable(ctx, utils.Now(), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, u := range users {
+		if err := s.purge(ctx, u.ID); err != nil {
+			s.logger.Error("Failed to purge account", zap.Int64("userId", u.ID), zap.Error(err))
+
+			// Accounts that keep failing go to the end of the queue so they
+			// do not hold back the rest of the batch
+			if err := s.userRepo.RecordPurgeFailure(ctx, u.ID); err != nil {
+				s.logger.Warn("Failed to record purge failure", zap.Int64("userId", u.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		purged++
+	}
+
+	if purged > 0 {
+		s.logger.Info("Deleted accounts purged", zap.Int("count", purged))
+	}
+	return purged, nil
+}
+
+func (s *Service) purge(ctx context.Context, userID int64) error {
+	if err := s.storageService.DeleteUserFiles(ctx, userID); err != nil {
+		return fmt.Errorf("delete files: %w", err)
+	}
+
+	if err := s.exports.DeleteUserExports(ctx, userID); err != nil {
+		return fmt.Errorf("delete data exports: %w", err)
+	}
+
+	if err := s.sessions.DeleteRefreshTokensByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("delete refresh tokens: %w", err)
+	}
+
+	return s.userRepo.Purge(ctx, userID)
+}
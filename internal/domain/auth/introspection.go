@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+type Introspection struct {
+	Active     bool
+	Subject    string
+	Username   string
+	Scope      string
+	Roles      []string
+	Plan       string
+	TokenType  string
+	Profile    string
+	SessionID  string
+	Jti        string
+	Issuer     string
+	Audience   string
+	IssuedAt   int64
+	ExpiresAt  int64
+	AccessMode string
+}
+
+func (s *Service) Introspect(ctx context.Context, token string) *Introspection {
+	inactive := &Introspection{Active: false}
+
+	claims, err := s.JwtService.ParseToken(token)
+	if err != nil {
+		return inactive
+	}
+
+	userID, err := strconv.ParseInt(claims.ID, 10, 64)
+	if err != nil {
+		return inactive
+	}
+
+	u, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return inactive
+	}
+
+	if !u.Admin && !u.Active {
+		return inactive
+	}
+
+	if blocked, err := s.SecurityService.IsBlocked(ctx, u.ID); err != nil || blocked {
+		return inactive
+	}
+
+	switch claims.Type {
+	case "refresh":
+		// Same checks the token would go through when used to refresh
+		storedToken, err := s.AuthRepo.GetRefreshTokenByHash(ctx, utils.HashToken(token))
+		if err != nil || storedToken.RevokedAt != nil || storedToken.Used || utils.Now().After(storedToken.ExpiresAt) {
+			return inactive
+		}
+
+		if u.Metadata.PasswordChangeRequired || (!u.Admin && !u.Metadata.EmailVerified) {
+			return inactive
+		}
+
+		if err := s.SessionPolicy.Check(storedToken, u.Metadata.PlanType, utils.Now()); err != nil {
+			return inactive
+		}
+	default:
+		if claims.Sid != "" {
+			familyID, err := uuid.Parse(claims.Sid)
+			if err != nil {
+				return inactive
+			}
+
+			active, err := s.AuthRepo.IsFamilyActive(ctx, familyID)
+			if err != nil || !active {
+				return inactive
+			}
+		}
+	}
+
+	scope := claims.Scope
+	if scope == "" {
+		scope = strings.ToLower(strings.Join(claims.Roles, " "))
+	}
+
+	tokenType := claims.Type
+	if tokenType == "" {
+		tokenType = "access"
+	}
+
+	return &Introspection{
+		Active:     true,
+		Subject:    claims.ID,
+		Username:   claims.Subject,
+		Scope:      scope,
+		Roles:      claims.Roles,
+		Plan:       claims.Plan,
+		TokenType:  tokenType,
+		Profile:    s.JwtService.Profile(claims.Profile).Name,
+		SessionID:  claims.Sid,
+		Jti:        claims.Jti,
+		Issuer:     claims.Issuer,
+		Audience:   claims.Audience,
+		IssuedAt:   claims.IssuedAt,
+		ExpiresAt:  claims.ExpiresAt,
+		AccessMode: claims.AccessMode,
+	}
+}
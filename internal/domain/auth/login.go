@@ -3,4 +3,9 @@ package auth
 type Login struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+
+	// Where the attempt comes from, for the login history
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+	DeviceID  string `json:"-"`
 }
@@ -0,0 +1,360 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/loginhistory"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/organization"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
+	"github.com/lkgiovani/go-boilerplate/pkg/encrypt"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type loginEventRepo struct {
+	loginhistory.Repository
+	events []*loginhistory.LoginEvent
+}
+
+func (r *loginEventRepo) Create(_ context.Context, event *loginhistory.LoginEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+// loginUserRepo knows a single user by email.
+type loginUserRepo struct {
+	user.UserService
+	user       *user.User
+	lastAccess int
+}
+
+func (r *loginUserRepo) GetByEmail(_ context.Context, email string) (*user.User, error) {
+	if r.user == nil || r.user.Email != email {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+func (r *loginUserRepo) GetDeletedByEmail(_ context.Context, _ string) (*user.User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *loginUserRepo) UpdateLastAccess(_ context.Context, _ int64, _ time.Time) error {
+	r.lastAccess++
+	return nil
+}
+
+func TestLoginRecordsFailedAttempts(t *testing.T) {
+	hash, err := encrypt.HashPassword("Secret@123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	known := &user.User{ID: 7, Email: "user@example.com", Password: &hash}
+
+	tests := []struct {
+		name       string
+		email      string
+		password   string
+		wantUserID *int64
+	}{
+		{"wrong password", "user@example.com", "Wrong@123", &known.ID},
+		{"unknown email", "nobody@example.com", "Secret@123", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			events := &loginEventRepo{}
+			users := &loginUserRepo{user: known}
+			s := &Service{
+				UserRepo:     users,
+				LoginHistory: loginhistory.NewService(events, users, 90, log),
+			}
+
+			_, err := s.Login(context.Background(), &Login{Email: tt.email, Password: tt.password, IPAddress: "10.0.0.1"})
+			if errors.ErrorKey(err) != "auth.invalid_credentials" {
+				t.Fatalf("Login() error = %v, want auth.invalid_credentials", err)
+			}
+
+			if len(events.events) != 1 {
+				t.Fatalf("events = %d, want 1", len(events.events))
+			}
+			event := events.events[0]
+			if event.Success || event.Email != tt.email || event.Method != loginhistory.MethodPassword {
+				t.Errorf("event = %+v, want a failed PASSWORD attempt for %s", event, tt.email)
+			}
+			if tt.wantUserID == nil && event.UserID != nil {
+				t.Errorf("UserID = %d, want nil", *event.UserID)
+			}
+			if tt.wantUserID != nil && (event.UserID == nil || *event.UserID != *tt.wantUserID) {
+				t.Errorf("UserID = %v, want %d", event.UserID, *tt.wantUserID)
+			}
+			if users.lastAccess != 0 {
+				t.Errorf("last access updated %d times, want 0", users.lastAccess)
+			}
+		})
+	}
+}
+
+// deletedUserRepo only knows a self-deleted account and counts restores.
+type deletedUserRepo struct {
+	user.UserService
+	user     *user.User
+	restored int
+}
+
+func (r *deletedUserRepo) GetByEmail(context.Context, string) (*user.User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *deletedUserRepo) GetDeletedByEmail(context.Context, string) (*user.User, error) {
+	return r.user, nil
+}
+
+func (r *deletedUserRepo) Restore(context.Context, int64) error {
+	r.restored++
+	return nil
+}
+
+type noBlockRepo struct {
+	security.Repository
+}
+
+func (noBlockRepo) FindActiveBlock(context.Context, int64, time.Time) (*security.Block, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestLoginRestoresOnlyAllowedAccounts(t *testing.T) {
+	hash, err := encrypt.HashPassword("Secret@123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		active       bool
+		verified     bool
+		password     string
+		wantKey      string
+		wantRestored int
+	}{
+		{"allowed login", true, true, "Secret@123", "", 1},
+		{"wrong password", true, true, "Wrong@123", "auth.invalid_credentials", 0},
+		{"inactive", false, true, "Secret@123", "auth.account_inactive_contact", 0},
+		{"email not verified", true, false, "Secret@123", "auth.email_not_verified_login", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			id := int64(7)
+			u := &user.User{ID: id, Email: "user@example.com", Password: &hash, Active: tt.active, DeletedBy: &id}
+			u.DeletedAt = gorm.DeletedAt{Time: utils.Now(), Valid: true}
+			u.Metadata.EmailVerified = tt.verified
+
+			users := &deletedUserRepo{user: u}
+			s := &Service{
+				UserRepo:        users,
+				SecurityService: security.NewService(noBlockRepo{}, nil, nil, security.Rules{}, time.Minute, 0, time.Second, security.ReportQueue{}, log),
+			}
+
+			_, err := s.authenticate(context.Background(), &Login{Email: u.Email, Password: tt.password})
+			if errors.ErrorKey(err) != tt.wantKey {
+				t.Fatalf("authenticate() error = %v, want %q", err, tt.wantKey)
+			}
+			if users.restored != tt.wantRestored {
+				t.Errorf("restored %d times, want %d", users.restored, tt.wantRestored)
+			}
+			if tt.wantRestored == 0 && !u.DeletedAt.Valid {
+				t.Error("account no longer marked as deleted")
+			}
+		})
+	}
+}
+
+// refreshTokenRepo serves the stored refresh tokens by hash.
+type refreshTokenRepo struct {
+	Repository
+	tokens map[string]*RefreshToken
+}
+
+func (r *refreshTokenRepo) GetRefreshTokenByHash(_ context.Context, hash string) (*RefreshToken, error) {
+	if token, ok := r.tokens[hash]; ok {
+		return token, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *refreshTokenRepo) RevokeAllUserRefreshTokens(_ context.Context, _ int64) error {
+	return nil
+}
+
+func TestRefreshRecordsAttempts(t *testing.T) {
+	jwtService, err := jwt.NewJwtService(config.JWTConfig{SecretKey: "test-secret", Issuer: "test", ExpirationMs: 60000}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	known := &user.User{ID: 7, Email: "user@example.com"}
+	revoked, _, err := jwtService.GenerateRefreshToken(known)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, _, err := jwtService.GenerateRefreshToken(&user.User{ID: 8, Email: "other@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revokedAt := utils.Now()
+	tokens := map[string]*RefreshToken{
+		utils.HashToken(revoked): {UserID: known.ID, UserEmail: known.Email, RevokedAt: &revokedAt},
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantKey    string
+		wantEvents int
+	}{
+		{"revoked token", revoked, "auth.token_revoked", 1},
+		{"unknown token", unknown, "auth.refresh_token_revoked", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			events := &loginEventRepo{}
+			users := &loginUserRepo{user: known}
+			s := &Service{
+				AuthRepo:     &refreshTokenRepo{tokens: tokens},
+				JwtService:   jwtService,
+				UserRepo:     users,
+				LoginHistory: loginhistory.NewService(events, users, 90, log),
+			}
+
+			_, err := s.RefreshToken(context.Background(), tt.token, "test-agent", "10.0.0.1", "")
+			if errors.ErrorKey(err) != tt.wantKey {
+				t.Fatalf("RefreshToken() error = %v, want %s", err, tt.wantKey)
+			}
+
+			if len(events.events) != tt.wantEvents {
+				t.Fatalf("events = %d, want %d", len(events.events), tt.wantEvents)
+			}
+			if tt.wantEvents == 0 {
+				return
+			}
+			event := events.events[0]
+			if event.Success || event.Method != loginhistory.MethodRefresh || event.UserID == nil || *event.UserID != known.ID {
+				t.Errorf("event = %+v, want a failed REFRESH attempt of user %d", event, known.ID)
+			}
+		})
+	}
+}
+
+type googleGateway struct {
+	info *GoogleUserInfo
+}
+
+func (g googleGateway) VerifyAndExtract(_ context.Context, _ string) (*GoogleUserInfo, error) {
+	return g.info, nil
+}
+
+func TestGoogleMobileRestrictsPasswordChange(t *testing.T) {
+	jwtService, err := jwt.NewJwtService(config.JWTConfig{SecretKey: "test-secret", Issuer: "test", ExpirationMs: 60000}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flagged := &user.User{ID: 7, Email: "user@example.com", Source: "GOOGLE", Active: true}
+	flagged.Metadata.PasswordChangeRequired = true
+
+	log, _ := logger.NewLogger("test", "none")
+	events := &loginEventRepo{}
+	users := &loginUserRepo{user: flagged}
+	s := &Service{
+		UserRepo:           users,
+		JwtService:         jwtService,
+		GoogleTokenGateway: googleGateway{info: &GoogleUserInfo{Email: flagged.Email, Name: "User"}},
+		LoginHistory:       loginhistory.NewService(events, users, 90, log),
+	}
+
+	result, err := s.AuthenticateWithGoogleMobile(context.Background(), "id-token", "device", "test-agent", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("AuthenticateWithGoogleMobile() error = %v", err)
+	}
+	if !result.PasswordChangeRequired || result.RefreshToken != "" {
+		t.Fatalf("result = %+v, want only a password change token", result)
+	}
+
+	claims, err := jwtService.ParseToken(result.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Scope != jwt.ScopePasswordChange {
+		t.Errorf("scope = %q, want %q", claims.Scope, jwt.ScopePasswordChange)
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("events = %d, want 1", len(events.events))
+	}
+	event := events.events[0]
+	if !event.Success || event.Method != loginhistory.MethodGoogle || event.IPAddress != "10.0.0.1" || event.UserAgent != "test-agent" {
+		t.Errorf("event = %+v, want a successful GOOGLE sign-in from 10.0.0.1", event)
+	}
+	if users.lastAccess != 1 {
+		t.Errorf("last access updated %d times, want 1", users.lastAccess)
+	}
+}
+
+type memberRepo struct {
+	organization.Repository
+}
+
+func (memberRepo) FindMember(_ context.Context, orgID, userID int64) (*organization.Member, error) {
+	return &organization.Member{OrganizationID: orgID, UserID: userID, Organization: &organization.Organization{ID: orgID}}, nil
+}
+
+func TestSwitchOrganizationRequiresOwnRefreshToken(t *testing.T) {
+	jwtService, err := jwt.NewJwtService(config.JWTConfig{SecretKey: "test-secret", Issuer: "test", ExpirationMs: 60000}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &user.User{ID: 8, Email: "other@example.com"}
+	otherToken, _, err := jwtService.GenerateRefreshToken(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := map[string]*RefreshToken{
+		utils.HashToken(otherToken): {UserID: other.ID, UserEmail: other.Email},
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantKey string
+	}{
+		{"missing token", "", "auth.refresh_token_required"},
+		{"token of another user", otherToken, "auth.invalid_refresh_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			s := &Service{
+				AuthRepo:            &refreshTokenRepo{tokens: tokens},
+				JwtService:          jwtService,
+				OrganizationService: organization.NewService(memberRepo{}, nil, nil, nil, "", 24, log),
+			}
+
+			_, err := s.SwitchOrganization(context.Background(), 7, 3, tt.token, "test-agent", "10.0.0.1", "")
+			if errors.ErrorKey(err) != tt.wantKey {
+				t.Fatalf("SwitchOrganization() error = %v, want %s", err, tt.wantKey)
+			}
+		})
+	}
+}
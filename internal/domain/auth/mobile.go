@@ -10,20 +10,25 @@ type GoogleUserInfo struct {
 	PictureURL string
 }
 
+// MobileAuthResult is a mobile sign-in. With PasswordChangeRequired only
+// the restricted access token is set, as in the password login.
 type MobileAuthResult struct {
-	UserID       int64
-	Email        string
-	Name         string
-	AccessToken  string
-	RefreshToken string
-	ExpiresIn    int64
-	IsNewUser    bool
+	UserID                 int64
+	Email                  string
+	Name                   string
+	AccessToken            string
+	RefreshToken           string
+	ExpiresIn              int64
+	RefreshExpiresIn       int64
+	IsNewUser              bool
+	PasswordChangeRequired bool
 }
 
 type MobileRefreshResult struct {
-	AccessToken  string
-	RefreshToken string
-	ExpiresIn    int64
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        int64
+	RefreshExpiresIn int64
 }
 
 type GoogleTokenGateway interface {
@@ -13,6 +13,8 @@ type RefreshToken struct {
 	DeviceID    string    `gorm:"not null"`
 	Jti         string    `gorm:"uniqueIndex;not null"`
 	FamilyID    uuid.UUID `gorm:"type:uuid;not null"`
+	ClientType  string    `gorm:"size:20;not null;default:web"`
+	Profile     string    `gorm:"size:30;not null;default:web"`
 	TokenHash   string    `gorm:"uniqueIndex;not null"`
 	ExpiresAt   time.Time `gorm:"not null"`
 	CreatedAt   time.Time `gorm:"not null"`
@@ -22,4 +24,7 @@ type RefreshToken struct {
 	RevokedAt   *time.Time
 	UserAgent   string `gorm:"size:500"`
 	IpAddress   string `gorm:"size:45"`
+
+	FamilyCreatedAt time.Time `gorm:"not null"`
+	OrganizationID  *int64
 }
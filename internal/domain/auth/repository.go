@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/lkgiovani/go-boilerplate/pkg/utils"
 	"gorm.io/gorm"
 )
@@ -13,7 +14,12 @@ type Repository interface {
 	RevokeRefreshToken(ctx context.Context, hash string) error
 	RevokeAllUserRefreshTokens(ctx context.Context, userID int64) error
 	RevokeAllUserRefreshTokensExcept(ctx context.Context, userID int64, exceptHash string) error
+	RevokeRefreshTokensByUserIDs(ctx context.Context, userIDs []int64) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	IsFamilyActive(ctx context.Context, familyID uuid.UUID) (bool, error)
 	MarkAsUsed(ctx context.Context, hash string) error
+	DeleteRefreshTokensByUserID(ctx context.Context, userID int64) error
+	FindRefreshTokensByUserID(ctx context.Context, userID int64) ([]RefreshToken, error)
 }
 
 type GormRepository struct {
@@ -54,6 +60,29 @@ func (r *GormRepository) RevokeAllUserRefreshTokensExcept(ctx context.Context, u
 		Update("revoked_at", utils.Now()).Error
 }
 
+func (r *GormRepository) RevokeRefreshTokensByUserIDs(ctx context.Context, userIDs []int64) error {
+	return r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("user_id IN ? AND revoked_at IS NULL", userIDs).
+		Update("revoked_at", utils.Now()).Error
+}
+
+func (r *GormRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", utils.Now()).Error
+}
+
+func (r *GormRepository) IsFamilyActive(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("family_id = ? AND used = ? AND revoked_at IS NULL AND expires_at > ?", familyID, false, utils.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *GormRepository) MarkAsUsed(ctx context.Context, hash string) error {
 	return r.db.WithContext(ctx).Model(&RefreshToken{}).
 		Where("token_hash = ?", hash).
@@ -62,3 +91,20 @@ func (r *GormRepository) MarkAsUsed(ctx context.Context, hash string) error {
 			"used_at": utils.Now(),
 		}).Error
 }
+
+func (r *GormRepository) DeleteRefreshTokensByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&RefreshToken{}).Error
+}
+
+func (r *GormRepository) FindRefreshTokensByUserID(ctx context.Context, userID int64) ([]RefreshToken, error) {
+	var tokens []RefreshToken
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
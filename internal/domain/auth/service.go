@@ -10,11 +10,17 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/loginhistory"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/organization"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/rbac"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
 	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/internal/errors"
 	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
 	"github.com/lkgiovani/go-boilerplate/pkg/encrypt"
 	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
 )
 
 type Service struct {
@@ -24,6 +30,12 @@ type Service struct {
 	JwtService               *jwt.JwtService
 	EmailVerificationService *emailverification.Service
 	GoogleTokenGateway       GoogleTokenGateway
+	SessionPolicy            *SessionPolicy
+	RbacService              *rbac.Service
+	OrganizationService      *organization.Service
+	PlanCatalog              *plancatalog.Service
+	SecurityService          *security.Service
+	LoginHistory             *loginhistory.Service
 }
 
 func NewService(
@@ -33,6 +45,12 @@ func NewService(
 	jwtService *jwt.JwtService,
 	emailVerSvc *emailverification.Service,
 	googleTokenGateway GoogleTokenGateway,
+	sessionPolicy *SessionPolicy,
+	rbacService *rbac.Service,
+	organizationService *organization.Service,
+	planCatalog *plancatalog.Service,
+	securityService *security.Service,
+	loginHistory *loginhistory.Service,
 ) *Service {
 	return &Service{
 		UserRepo:                 userRepo,
@@ -41,144 +59,472 @@ func NewService(
 		JwtService:               jwtService,
 		EmailVerificationService: emailVerSvc,
 		GoogleTokenGateway:       googleTokenGateway,
+		SessionPolicy:            sessionPolicy,
+		RbacService:              rbacService,
+		OrganizationService:      organizationService,
+		PlanCatalog:              planCatalog,
+		SecurityService:          securityService,
+		LoginHistory:             loginHistory,
 	}
 }
 
+// Login checks the credentials. Failed attempts are recorded in the login
+// history here, with the user when the email is known; a successful one is
+// recorded by CreateSession or CreatePasswordChangeSession.
 func (s *Service) Login(ctx context.Context, login *Login) (*user.User, error) {
+	u, err := s.authenticate(ctx, login)
+	if err != nil {
+		attempt := loginhistory.Attempt{
+			Email:     login.Email,
+			Method:    loginhistory.MethodPassword,
+			IPAddress: login.IPAddress,
+			UserAgent: login.UserAgent,
+			DeviceID:  login.DeviceID,
+		}
+		if u != nil {
+			attempt.UserID = &u.ID
+		}
+		s.LoginHistory.Record(ctx, attempt, err)
+		return nil, err
+	}
+	return u, nil
+}
+
+// authenticate checks the credentials and the account state. Once the user
+// is identified it is returned along with the error, so failed attempts
+// reach the login history.
+func (s *Service) authenticate(ctx context.Context, login *Login) (*user.User, error) {
 	u, err := s.UserRepo.GetByEmail(ctx, login.Email)
 	if err != nil {
-		return nil, errors.Errorf(errors.EUNAUTHORIZED, "invalid email or password")
+		// Self-deleted accounts can still log in during the grace period, which cancels the deletion.
+		u, err = s.UserRepo.GetDeletedByEmail(ctx, login.Email)
+		if err != nil || !isRestorable(u) {
+			return nil, errors.New(errors.EUNAUTHORIZED, "auth.invalid_credentials")
+		}
 	}
 
 	if u.Password == nil {
-		return nil, errors.Errorf(errors.EUNAUTHORIZED, "invalid email or password")
+		return u, errors.New(errors.EUNAUTHORIZED, "auth.invalid_credentials")
 	}
 
 	if err := encrypt.VerifyPassword(login.Password, *u.Password); err != nil {
-		return nil, errors.Errorf(errors.EUNAUTHORIZED, "invalid email or password")
+		return u, errors.New(errors.EUNAUTHORIZED, "auth.invalid_credentials")
+	}
+
+	if err := s.SecurityService.EnsureNotBlocked(ctx, u.ID); err != nil {
+		return u, err
 	}
 
 	if !u.Admin {
 		if !u.Active {
-			return nil, errors.Errorf(errors.EUNAUTHORIZED, "Sua conta está inativa. Entre em contato com o suporte.")
+			return u, errors.New(errors.EUNAUTHORIZED, "auth.account_inactive_contact")
 		}
 		if !u.Metadata.EmailVerified {
-			return nil, errors.Errorf(errors.EUNAUTHORIZED, "Email não verificado. Verifique seu email para acessar a conta.")
+			return u, errors.New(errors.EUNAUTHORIZED, "auth.email_not_verified_login")
+		}
+	}
+
+	// Only a login that would succeed cancels the deletion
+	if u.DeletedAt.Valid {
+		if err := s.restoreAccount(ctx, u); err != nil {
+			return u, err
 		}
 	}
 
 	return u, nil
 }
 
-func (s *Service) CreateSession(ctx context.Context, u *user.User, userAgent, ipAddress, deviceID string) (string, string, []*http.Cookie, error) {
-	accessToken, refreshToken, refreshClaims, cookies, err := s.JwtService.GenerateCookies(u)
+func (s *Service) restoreAccount(ctx context.Context, u *user.User) error {
+	if err := s.UserRepo.Restore(ctx, u.ID); err != nil {
+		return errors.New(errors.EINTERNAL, "account.restore_failed")
+	}
+
+	u.DeletedAt = gorm.DeletedAt{}
+	u.DeletedBy = nil
+	u.PurgeAfter = nil
+	return nil
+}
+
+func isRestorable(u *user.User) bool {
+	return u.IsSelfDeleted() && (u.PurgeAfter == nil || utils.Now().Before(*u.PurgeAfter))
+}
+
+// CreateSession signs the user in with method and records the attempt in
+// the login history, as a success only once the session is stored.
+func (s *Service) CreateSession(ctx context.Context, u *user.User, method loginhistory.Method, profileName, userAgent, ipAddress, deviceID string) (*Session, error) {
+	session, err := s.createSession(ctx, u, profileName, userAgent, ipAddress, deviceID)
+	s.LoginHistory.Record(ctx, loginhistory.Attempt{
+		UserID:    &u.ID,
+		Email:     u.Email,
+		Method:    method,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		DeviceID:  deviceID,
+	}, err)
+	return session, err
+}
+
+// SwitchOrganization rotates the user's refresh token within its family,
+// binding the new tokens to orgID. The session keeps its absolute lifetime
+// and goes through the same checks as a refresh.
+func (s *Service) SwitchOrganization(ctx context.Context, userID, orgID int64, currentToken, userAgent, ipAddress, deviceID string) (*Session, error) {
+	if currentToken == "" {
+		return nil, errors.New(errors.EUNAUTHORIZED, "auth.refresh_token_required")
+	}
+
+	if _, err := s.OrganizationService.GetMembership(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	session, _, err := s.refresh(ctx, currentToken, rotation{userID: userID, orgID: orgID}, userAgent, ipAddress, deviceID)
+	return session, err
+}
+
+func (s *Service) createSession(ctx context.Context, u *user.User, profileName, userAgent, ipAddress, deviceID string) (*Session, error) {
+	if err := s.SecurityService.EnsureNotBlocked(ctx, u.ID); err != nil {
+		return nil, err
+	}
+
+	now := utils.Now()
+	profile := s.JwtService.Profile(profileName)
+	profile = profile.WithRefreshTTL(s.SessionPolicy.RefreshTTL(profile.ClientType, u.Metadata.PlanType, now, now))
+	familyID := uuid.New()
+
+	opts, err := s.sessionOptions(ctx, u, 0)
 	if err != nil {
-		return "", "", nil, err
+		return nil, err
+	}
+	opts.Profile = profile.Name
+	opts.SessionID = familyID.String()
+	opts.RefreshTokenTTL = profile.RefreshTokenTTL
+
+	accessToken, refreshToken, refreshClaims, cookies, err := s.JwtService.GenerateCookies(u, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	rt := &RefreshToken{
-		ID:        uuid.New(),
-		UserID:    u.ID,
-		UserEmail: u.Email,
-		DeviceID:  deviceID,
-		Jti:       refreshClaims.Jti,
-		FamilyID:  uuid.New(),
-		TokenHash: utils.HashToken(refreshToken),
-		ExpiresAt: time.Unix(refreshClaims.ExpiresAt, 0),
-		CreatedAt: utils.Now(),
-		UserAgent: userAgent,
-		IpAddress: ipAddress,
+		ID:              uuid.New(),
+		UserID:          u.ID,
+		UserEmail:       u.Email,
+		DeviceID:        deviceID,
+		Jti:             refreshClaims.Jti,
+		FamilyID:        familyID,
+		ClientType:      profile.ClientType,
+		Profile:         profile.Name,
+		TokenHash:       utils.HashToken(refreshToken),
+		ExpiresAt:       time.Unix(refreshClaims.ExpiresAt, 0),
+		CreatedAt:       now,
+		FamilyCreatedAt: now,
+		OrganizationID:  &opts.OrganizationID,
+		UserAgent:       userAgent,
+		IpAddress:       ipAddress,
 	}
 
 	if err := s.AuthRepo.CreateRefreshToken(ctx, rt); err != nil {
-		return "", "", nil, err
+		return nil, err
 	}
 
-	return accessToken, refreshToken, cookies, nil
+	return &Session{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Profile:      profile,
+		Cookies:      cookies,
+	}, nil
 }
 
-func (s *Service) RefreshToken(ctx context.Context, token, userAgent, ipAddress, deviceID string) (string, string, []*http.Cookie, error) {
+// sessionOptions resolves the roles, permissions and active organization
+// embedded in the tokens of a session.
+func (s *Service) sessionOptions(ctx context.Context, u *user.User, orgID int64) (jwt.SessionOptions, error) {
+	roles, permissions, err := s.RbacService.ResolveAuthorization(ctx, u)
+	if err != nil {
+		return jwt.SessionOptions{}, err
+	}
+
+	org, member, err := s.OrganizationService.ResolveActiveOrganization(ctx, u, orgID)
+	if err != nil {
+		return jwt.SessionOptions{}, err
+	}
+
+	plan := organization.EffectivePlan(org, u)
+
+	return jwt.SessionOptions{
+		Roles:            roles,
+		Permissions:      permissions,
+		OrganizationID:   org.ID,
+		OrganizationRole: member.Role,
+		Plan:             &plan,
+	}, nil
+}
+
+func (s *Service) RefreshToken(ctx context.Context, token, userAgent, ipAddress, deviceID string) (*Session, error) {
+	session, storedToken, err := s.refresh(ctx, token, rotation{}, userAgent, ipAddress, deviceID)
+	if storedToken != nil {
+		s.LoginHistory.Record(ctx, loginhistory.Attempt{
+			UserID:    &storedToken.UserID,
+			Email:     storedToken.UserEmail,
+			Method:    loginhistory.MethodRefresh,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			DeviceID:  deviceID,
+		}, err)
+	}
+	return session, err
+}
+
+// rotation narrows a refresh: the token must belong to userID and the new
+// tokens are bound to orgID, when set.
+type rotation struct {
+	userID int64
+	orgID  int64
+}
+
+// refresh rotates the refresh token. The stored token is returned as soon
+// as it is found, so attempts of known sessions reach the login history.
+func (s *Service) refresh(ctx context.Context, token string, to rotation, userAgent, ipAddress, deviceID string) (*Session, *RefreshToken, error) {
 
 	claims, err := s.JwtService.ParseToken(token)
 	if err != nil {
-		return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "invalid refresh token")
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "auth.invalid_refresh_token")
 	}
 
 	if claims.Type != "refresh" {
-		return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "invalid token type")
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "auth.invalid_token_type")
 	}
 
 	hash := utils.HashToken(token)
 	storedToken, err := s.AuthRepo.GetRefreshTokenByHash(ctx, hash)
 	if err != nil {
-		return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "refresh token not found or revoked")
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "auth.refresh_token_revoked")
+	}
+
+	if to.userID != 0 && storedToken.UserID != to.userID {
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "auth.invalid_refresh_token")
 	}
 
 	if storedToken.RevokedAt != nil {
 
 		_ = s.AuthRepo.RevokeAllUserRefreshTokens(ctx, storedToken.UserID)
-		return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "token revoked")
+		return nil, storedToken, errors.New(errors.EUNAUTHORIZED, "auth.token_revoked")
 	}
 
 	if storedToken.Used {
+		// A rotated token presented again may have been stolen. The request
+		// values are copied since the activity is recorded in the background.
+		ip, ua := strings.Clone(ipAddress), strings.Clone(userAgent)
+		s.SecurityService.Report(security.SuspiciousActivity{
+			UserID:       storedToken.UserID,
+			ActivityType: security.ActivitySuspiciousPattern,
+			Endpoint:     "auth:refresh",
+			IPAddress:    &ip,
+			UserAgent:    &ua,
+			Details:      security.Details{"reason": "refresh_token_reuse", "familyId": storedToken.FamilyID.String()},
+		})
 
 		_ = s.AuthRepo.RevokeAllUserRefreshTokens(ctx, storedToken.UserID)
-		return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "token already used")
+		return nil, storedToken, errors.New(errors.EUNAUTHORIZED, "auth.token_already_used")
 	}
 
 	u, err := s.UserRepo.GetByID(ctx, storedToken.UserID)
 	if err != nil || u == nil {
-		return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "user not found")
+		return nil, storedToken, errors.New(errors.EUNAUTHORIZED, "user.not_found")
+	}
+
+	if u.Metadata.PasswordChangeRequired {
+		_ = s.AuthRepo.RevokeAllUserRefreshTokens(ctx, u.ID)
+		return nil, storedToken, errors.New(errors.EUNAUTHORIZED, "auth.password_change_required")
+	}
+
+	if err := s.SecurityService.EnsureNotBlocked(ctx, u.ID); err != nil {
+		return nil, storedToken, err
+	}
+
+	now := utils.Now()
+	if err := s.SessionPolicy.Check(storedToken, u.Metadata.PlanType, now); err != nil {
+		_ = s.AuthRepo.RevokeFamily(ctx, storedToken.FamilyID)
+		return nil, storedToken, err
 	}
 
 	if !u.Admin {
 		if !u.Active {
-			return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "Sua conta está inativa.")
+			return nil, storedToken, errors.New(errors.EUNAUTHORIZED, "auth.account_inactive")
 		}
 		if !u.Metadata.EmailVerified {
-			return "", "", nil, errors.Errorf(errors.EUNAUTHORIZED, "Email não verificado.")
+			return nil, storedToken, errors.New(errors.EUNAUTHORIZED, "auth.email_not_verified")
 		}
 	}
 
-	accessToken, refreshToken, refreshClaims, cookies, err := s.JwtService.GenerateCookies(u)
+	profile := s.JwtService.Profile(storedToken.Profile)
+	profile = profile.WithRefreshTTL(s.SessionPolicy.RefreshTTL(storedToken.ClientType, u.Metadata.PlanType, storedToken.FamilyCreatedAt, now))
+
+	orgID := to.orgID
+	if orgID == 0 && storedToken.OrganizationID != nil {
+		orgID = *storedToken.OrganizationID
+	}
+
+	opts, err := s.sessionOptions(ctx, u, orgID)
+	if err != nil {
+		return nil, storedToken, err
+	}
+	opts.Profile = profile.Name
+	opts.SessionID = storedToken.FamilyID.String()
+	opts.RefreshTokenTTL = profile.RefreshTokenTTL
+
+	accessToken, refreshToken, refreshClaims, cookies, err := s.JwtService.GenerateCookies(u, opts)
 	if err != nil {
-		return "", "", nil, err
+		return nil, storedToken, err
 	}
 
 	if err := s.AuthRepo.MarkAsUsed(ctx, hash); err != nil {
-		return "", "", nil, err
+		return nil, storedToken, err
 	}
 
 	newRt := &RefreshToken{
-		ID:          uuid.New(),
-		UserID:      u.ID,
-		UserEmail:   u.Email,
-		DeviceID:    deviceID,
-		Jti:         refreshClaims.Jti,
-		FamilyID:    storedToken.FamilyID,
-		TokenHash:   utils.HashToken(refreshToken),
-		ExpiresAt:   time.Unix(refreshClaims.ExpiresAt, 0),
-		CreatedAt:   utils.Now(),
-		RotatedFrom: &storedToken.ID,
-		UserAgent:   userAgent,
-		IpAddress:   ipAddress,
+		ID:              uuid.New(),
+		UserID:          u.ID,
+		UserEmail:       u.Email,
+		DeviceID:        deviceID,
+		Jti:             refreshClaims.Jti,
+		FamilyID:        storedToken.FamilyID,
+		ClientType:      storedToken.ClientType,
+		Profile:         profile.Name,
+		TokenHash:       utils.HashToken(refreshToken),
+		ExpiresAt:       time.Unix(refreshClaims.ExpiresAt, 0),
+		CreatedAt:       now,
+		FamilyCreatedAt: storedToken.FamilyCreatedAt,
+		OrganizationID:  &opts.OrganizationID,
+		RotatedFrom:     &storedToken.ID,
+		UserAgent:       userAgent,
+		IpAddress:       ipAddress,
 	}
 
 	if err := s.AuthRepo.CreateRefreshToken(ctx, newRt); err != nil {
-		return "", "", nil, err
+		return nil, storedToken, err
 	}
 
-	return accessToken, refreshToken, cookies, nil
+	return &Session{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Profile:      profile,
+		Cookies:      cookies,
+	}, storedToken, nil
+}
+
+// CreatePasswordChangeSession signs in an account flagged for a password
+// change with only the token that can change it. The sign-in is recorded in
+// the login history like the one of CreateSession.
+func (s *Service) CreatePasswordChangeSession(ctx context.Context, u *user.User, method loginhistory.Method, profileName, userAgent, ipAddress, deviceID string) (string, *http.Cookie, error) {
+	accessToken, cookie, err := s.JwtService.GeneratePasswordChangeToken(u, profileName)
+	s.LoginHistory.Record(ctx, loginhistory.Attempt{
+		UserID:    &u.ID,
+		Email:     u.Email,
+		Method:    method,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		DeviceID:  deviceID,
+	}, err)
+	return accessToken, cookie, err
+}
+
+func (s *Service) ChangeRequiredPassword(ctx context.Context, userID int64, currentPassword, newPassword, profileName, userAgent, ipAddress, deviceID string) (*user.User, *Session, error) {
+	u, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "user.not_found")
+	}
+
+	if !u.Metadata.PasswordChangeRequired {
+		return nil, nil, errors.New(errors.EINVALID, "password.change_not_required")
+	}
+
+	if u.Password == nil {
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "auth.invalid_credentials")
+	}
+
+	if err := encrypt.VerifyPassword(currentPassword, *u.Password); err != nil {
+		return nil, nil, errors.New(errors.EUNAUTHORIZED, "password.current_incorrect")
+	}
+
+	if currentPassword == newPassword {
+		return nil, nil, errors.New(errors.EINVALID, "password.must_differ")
+	}
+
+	if err := PasswordRequirements(newPassword); err != nil {
+		return nil, nil, err
+	}
+
+	hashedPassword, err := encrypt.HashPassword(newPassword)
+	if err != nil {
+		return nil, nil, errors.New(errors.EINTERNAL, "password.hash_failed")
+	}
+
+	u.Password = &hashedPassword
+	u.Metadata.PasswordChangeRequired = false
+	if err := s.UserRepo.Update(ctx, u); err != nil {
+		return nil, nil, errors.New(errors.EINTERNAL, "password.update_failed")
+	}
+
+	if err := s.AuthRepo.RevokeAllUserRefreshTokens(ctx, u.ID); err != nil {
+		return nil, nil, err
+	}
+
+	session, err := s.CreateSession(ctx, u, loginhistory.MethodPassword, profileName, userAgent, ipAddress, deviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return u, session, nil
+}
+
+func (s *Service) RequirePasswordChange(ctx context.Context, userID, adminID int64) error {
+	if userID == adminID {
+		return errors.New(errors.EINVALID, "password.cannot_force_self")
+	}
+
+	u, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if u.Password == nil {
+		return errors.New(errors.EINVALID, "password.not_set")
+	}
+	if err := user.CheckVersion(ctx, u); err != nil {
+		return err
+	}
+
+	_, err = s.RequirePasswordChangeForUsers(ctx, []int64{userID}, adminID)
+	return err
+}
+
+func (s *Service) RequirePasswordChangeForUsers(ctx context.Context, userIDs []int64, adminID int64) (int, error) {
+	affected, err := s.UserRepo.RequirePasswordChange(ctx, userIDs, adminID)
+	if err != nil {
+		return 0, errors.New(errors.EINTERNAL, "password.force_change_failed")
+	}
+
+	if len(affected) == 0 {
+		return 0, nil
+	}
+
+	if err := s.AuthRepo.RevokeRefreshTokensByUserIDs(ctx, affected); err != nil {
+		return 0, errors.New(errors.EINTERNAL, "session.revoke_failed")
+	}
+
+	return len(affected), nil
 }
 
 func (s *Service) Register(ctx context.Context, u *user.User) error {
 	exists, _ := s.UserRepo.GetByEmail(ctx, u.Email)
 	if exists != nil {
-		return errors.Errorf(errors.EDUPLICATION, "user already exists")
+		return errors.New(errors.EDUPLICATION, "user.already_exists")
+	}
+
+	if deleted, _ := s.UserRepo.GetDeletedByEmail(ctx, u.Email); deleted != nil {
+		return errors.New(errors.EDUPLICATION, "user.already_exists")
 	}
 
 	if u.Password == nil || *u.Password == "" {
-		return errors.Errorf(errors.EINVALID, "password is required")
+		return errors.New(errors.EINVALID, "password.required")
 	}
 
 	if err := PasswordRequirements(*u.Password); err != nil {
@@ -187,12 +533,12 @@ func (s *Service) Register(ctx context.Context, u *user.User) error {
 
 	hashedPassword, err := encrypt.HashPassword(*u.Password)
 	if err != nil {
-		return errors.Errorf(errors.EINTERNAL, "failed to hash password")
+		return errors.New(errors.EINTERNAL, "password.hash_failed")
 	}
 	u.Password = &hashedPassword
 
 	if err := s.UserRepo.Create(ctx, u); err != nil {
-		return errors.Errorf(errors.EINTERNAL, "failed to create user")
+		return errors.New(errors.EINTERNAL, "user.create_failed")
 	}
 
 	if _, err := s.EmailVerificationService.CreateAndSendVerificationToken(ctx, u); err != nil {
@@ -222,7 +568,7 @@ func (s *Service) RevokeAllRefreshTokens(ctx context.Context, userID int64, curr
 
 func PasswordRequirements(password string) error {
 	if len(password) < 8 {
-		return errors.Errorf(errors.EINVALID, "password must be at least 8 characters long")
+		return errors.New(errors.EINVALID, "password.too_short")
 	}
 
 	var hasUpper, hasSpecial bool
@@ -236,10 +582,10 @@ func PasswordRequirements(password string) error {
 	}
 
 	if !hasUpper {
-		return errors.Errorf(errors.EINVALID, "password must contain at least one uppercase letter")
+		return errors.New(errors.EINVALID, "password.uppercase_required")
 	}
 	if !hasSpecial {
-		return errors.Errorf(errors.EINVALID, "password must contain at least one special character")
+		return errors.New(errors.EINVALID, "password.special_required")
 	}
 
 	return nil
@@ -248,33 +594,54 @@ func PasswordRequirements(password string) error {
 func (s *Service) AuthenticateWithGoogleMobile(ctx context.Context, idToken, deviceID, userAgent, ipAddress string) (*MobileAuthResult, error) {
 	googleUser, err := s.GoogleTokenGateway.VerifyAndExtract(ctx, idToken)
 	if err != nil {
-		return nil, errors.Errorf(errors.EUNAUTHORIZED, "falha ao verificar token do Google: %v", err)
+		return nil, errors.New(errors.EUNAUTHORIZED, "auth.google_token_invalid", err)
 	}
 
 	userEntity, isNewUser, err := s.findOrCreateGoogleUser(ctx, googleUser)
 	if err != nil {
+		s.LoginHistory.Record(ctx, loginhistory.Attempt{
+			Email:     googleUser.Email,
+			Method:    loginhistory.MethodGoogle,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			DeviceID:  deviceID,
+		}, err)
 		return nil, err
 	}
 
-	now := time.Now()
-	userEntity.LastAccess = &now
-	if err := s.UserRepo.Update(ctx, userEntity); err != nil {
-		return nil, errors.Errorf(errors.EINTERNAL, "falha ao atualizar último acesso")
+	// Accounts flagged for a password change only get the token that can
+	// change it, as in the password login
+	if userEntity.Metadata.PasswordChangeRequired {
+		accessToken, _, err := s.CreatePasswordChangeSession(ctx, userEntity, loginhistory.MethodGoogle, jwt.ProfileMobile, userAgent, ipAddress, deviceID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &MobileAuthResult{
+			UserID:                 userEntity.ID,
+			Email:                  userEntity.Email,
+			Name:                   userEntity.Name,
+			AccessToken:            accessToken,
+			ExpiresIn:              s.JwtService.GetAccessTokenExpirationSeconds(jwt.ProfileMobile),
+			IsNewUser:              isNewUser,
+			PasswordChangeRequired: true,
+		}, nil
 	}
 
-	accessToken, refreshToken, _, err := s.CreateSession(ctx, userEntity, userAgent, ipAddress, deviceID)
+	session, err := s.CreateSession(ctx, userEntity, loginhistory.MethodGoogle, jwt.ProfileMobile, userAgent, ipAddress, deviceID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &MobileAuthResult{
-		UserID:       userEntity.ID,
-		Email:        userEntity.Email,
-		Name:         userEntity.Name,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    s.JwtService.GetAccessTokenExpirationSeconds(),
-		IsNewUser:    isNewUser,
+		UserID:           userEntity.ID,
+		Email:            userEntity.Email,
+		Name:             userEntity.Name,
+		AccessToken:      session.AccessToken,
+		RefreshToken:     session.RefreshToken,
+		ExpiresIn:        session.Profile.AccessTokenTTL,
+		RefreshExpiresIn: session.Profile.RefreshTokenTTL,
+		IsNewUser:        isNewUser,
 	}, nil
 }
 
@@ -289,12 +656,25 @@ func (s *Service) findOrCreateGoogleUser(ctx context.Context, googleUser *Google
 
 			existingUser.Metadata.EmailVerified = true
 			if err := s.UserRepo.Update(ctx, existingUser); err != nil {
-				return nil, false, errors.Errorf(errors.EINTERNAL, "falha ao atualizar usuário")
+				return nil, false, errors.New(errors.EINTERNAL, "user.update_failed")
 			}
 		}
 		return existingUser, false, nil
 	}
 
+	if deletedUser, err := s.UserRepo.GetDeletedByEmail(ctx, googleUser.Email); err == nil && deletedUser != nil {
+		if !isRestorable(deletedUser) {
+			return nil, false, errors.New(errors.EUNAUTHORIZED, "account.deleted")
+		}
+		if err := s.SecurityService.EnsureNotBlocked(ctx, deletedUser.ID); err != nil {
+			return nil, false, err
+		}
+		if err := s.restoreAccount(ctx, deletedUser); err != nil {
+			return nil, false, err
+		}
+		return deletedUser, false, nil
+	}
+
 	newUser := &user.User{
 		Name:     googleUser.Name,
 		Email:    googleUser.Email,
@@ -302,26 +682,27 @@ func (s *Service) findOrCreateGoogleUser(ctx context.Context, googleUser *Google
 		ImgURL:   &googleUser.PictureURL,
 		Active:   true,
 		Admin:    false,
-		Metadata: user.NewDefaultMetadata(),
+		Metadata: s.PlanCatalog.DefaultMetadata(),
 	}
 	newUser.Metadata.EmailVerified = true
 
 	if createErr := s.UserRepo.Create(ctx, newUser); createErr != nil {
-		return nil, false, errors.Errorf(errors.EINTERNAL, "falha ao criar usuário")
+		return nil, false, errors.New(errors.EINTERNAL, "user.create_failed")
 	}
 
 	return newUser, true, nil
 }
 
 func (s *Service) RefreshMobileToken(ctx context.Context, refreshToken, userAgent, ipAddress, deviceID string) (*MobileRefreshResult, error) {
-	accessToken, newRefreshToken, _, err := s.RefreshToken(ctx, refreshToken, userAgent, ipAddress, deviceID)
+	session, err := s.RefreshToken(ctx, refreshToken, userAgent, ipAddress, deviceID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &MobileRefreshResult{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
-		ExpiresIn:    s.JwtService.GetAccessTokenExpirationSeconds(),
+		AccessToken:      session.AccessToken,
+		RefreshToken:     session.RefreshToken,
+		ExpiresIn:        session.Profile.AccessTokenTTL,
+		RefreshExpiresIn: session.Profile.RefreshTokenTTL,
 	}, nil
 }
@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
+)
+
+type Session struct {
+	AccessToken  string
+	RefreshToken string
+	Profile      jwt.TokenProfile
+	Cookies      []*http.Cookie
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+const (
+	ClientTypeWeb    = "web"
+	ClientTypeMobile = "mobile"
+	ClientTypeCLI    = "cli"
+)
+
+type SessionLimits struct {
+	IdleTimeout      time.Duration
+	AbsoluteLifetime time.Duration
+}
+
+type SessionPolicy struct {
+	defaults    SessionLimits
+	clientTypes map[string]SessionLimits
+	plans       map[string]SessionLimits
+}
+
+func NewSessionPolicy(cfg config.SessionConfig) *SessionPolicy {
+	policy := &SessionPolicy{
+		defaults:    toSessionLimits(cfg.Default),
+		clientTypes: make(map[string]SessionLimits, len(cfg.ClientTypes)),
+		plans:       make(map[string]SessionLimits, len(cfg.Plans)),
+	}
+
+	for clientType, limits := range cfg.ClientTypes {
+		policy.clientTypes[strings.ToLower(clientType)] = toSessionLimits(limits)
+	}
+	for plan, limits := range cfg.Plans {
+		policy.plans[strings.ToUpper(plan)] = toSessionLimits(limits)
+	}
+
+	return policy
+}
+
+func (p *SessionPolicy) Limits(clientType string, plan user.PlanType) SessionLimits {
+	limits := p.defaults
+	limits = limits.override(p.clientTypes[strings.ToLower(clientType)])
+	limits = limits.override(p.plans[strings.ToUpper(string(plan))])
+	return limits
+}
+
+func (p *SessionPolicy) Check(token *RefreshToken, plan user.PlanType, now time.Time) error {
+	limits := p.Limits(token.ClientType, plan)
+
+	if limits.AbsoluteLifetime > 0 && now.Sub(token.FamilyCreatedAt) > limits.AbsoluteLifetime {
+		return errors.New(errors.EUNAUTHORIZED, "session.expired")
+	}
+
+	if limits.IdleTimeout > 0 && now.Sub(token.CreatedAt) > limits.IdleTimeout {
+		return errors.New(errors.EUNAUTHORIZED, "session.idle_expired")
+	}
+
+	return nil
+}
+
+// RefreshTTL returns the seconds left before a session started at
+// familyCreatedAt reaches its absolute lifetime, so refresh tokens are never
+// issued past it. Zero means no absolute limit.
+func (p *SessionPolicy) RefreshTTL(clientType string, plan user.PlanType, familyCreatedAt, now time.Time) int64 {
+	limits := p.Limits(clientType, plan)
+	if limits.AbsoluteLifetime <= 0 {
+		return 0
+	}
+	return max(int64(familyCreatedAt.Add(limits.AbsoluteLifetime).Sub(now).Seconds()), 1)
+}
+
+func (l SessionLimits) override(other SessionLimits) SessionLimits {
+	if other.IdleTimeout > 0 {
+		l.IdleTimeout = other.IdleTimeout
+	}
+	if other.AbsoluteLifetime > 0 {
+		l.AbsoluteLifetime = other.AbsoluteLifetime
+	}
+	return l
+}
+
+func toSessionLimits(cfg config.SessionLimitsConfig) SessionLimits {
+	return SessionLimits{
+		IdleTimeout:      time.Duration(cfg.IdleTimeoutHours) * time.Hour,
+		AbsoluteLifetime: time.Duration(cfg.AbsoluteLifetimeDays) * 24 * time.Hour,
+	}
+}
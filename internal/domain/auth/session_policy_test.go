@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+func TestSessionPolicyLimits(t *testing.T) {
+	policy := NewSessionPolicy(config.SessionConfig{
+		Default: config.SessionLimitsConfig{IdleTimeoutHours: 168, AbsoluteLifetimeDays: 30},
+		ClientTypes: map[string]config.SessionLimitsConfig{
+			"Mobile": {IdleTimeoutHours: 720, AbsoluteLifetimeDays: 90},
+			"cli":    {IdleTimeoutHours: 24},
+		},
+		Plans: map[string]config.SessionLimitsConfig{
+			"enterprise": {AbsoluteLifetimeDays: 7},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		clientType string
+		plan       user.PlanType
+		want       SessionLimits
+	}{
+		{"defaults", ClientTypeWeb, user.PlanTypeFree, SessionLimits{168 * time.Hour, 30 * 24 * time.Hour}},
+		{"client type", "MOBILE", user.PlanTypeFree, SessionLimits{720 * time.Hour, 90 * 24 * time.Hour}},
+		{"partial client type keeps defaults", ClientTypeCLI, user.PlanTypeFree, SessionLimits{24 * time.Hour, 30 * 24 * time.Hour}},
+		{"plan overrides client type", ClientTypeMobile, user.PlanTypeEnterprise, SessionLimits{720 * time.Hour, 7 * 24 * time.Hour}},
+		{"unknown client type", "tv", user.PlanTypeFree, SessionLimits{168 * time.Hour, 30 * 24 * time.Hour}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Limits(tt.clientType, tt.plan); got != tt.want {
+				t.Errorf("Limits(%q, %q) = %+v, want %+v", tt.clientType, tt.plan, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionPolicyCheck(t *testing.T) {
+	policy := NewSessionPolicy(config.SessionConfig{
+		Default: config.SessionLimitsConfig{IdleTimeoutHours: 24, AbsoluteLifetimeDays: 7},
+	})
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		familyAge  time.Duration
+		tokenAge   time.Duration
+		wantErrKey string
+	}{
+		{"fresh session", time.Hour, time.Hour, ""},
+		{"idle just within", 3 * 24 * time.Hour, 24 * time.Hour, ""},
+		{"idle expired", 3 * 24 * time.Hour, 25 * time.Hour, "session.idle_expired"},
+		{"absolute expired", 8 * 24 * time.Hour, time.Hour, "session.expired"},
+		{"absolute checked first", 8 * 24 * time.Hour, 48 * time.Hour, "session.expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &RefreshToken{
+				ClientType:      ClientTypeWeb,
+				FamilyCreatedAt: now.Add(-tt.familyAge),
+				CreatedAt:       now.Add(-tt.tokenAge),
+			}
+
+			err := policy.Check(token, user.PlanTypeFree, now)
+			if tt.wantErrKey == "" {
+				if err != nil {
+					t.Fatalf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || errors.ErrorKey(err) != tt.wantErrKey {
+				t.Fatalf("Check() = %v, want %s", err, tt.wantErrKey)
+			}
+		})
+	}
+}
+
+func TestSessionPolicyRefreshTTL(t *testing.T) {
+	policy := NewSessionPolicy(config.SessionConfig{
+		Default: config.SessionLimitsConfig{AbsoluteLifetimeDays: 30},
+		Plans: map[string]config.SessionLimitsConfig{
+			"enterprise": {AbsoluteLifetimeDays: 7},
+		},
+	})
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	day := int64(24 * 60 * 60)
+
+	tests := []struct {
+		name      string
+		plan      user.PlanType
+		familyAge time.Duration
+		want      int64
+	}{
+		{"new session", user.PlanTypeFree, 0, 30 * day},
+		{"rotated session", user.PlanTypeFree, 10 * 24 * time.Hour, 20 * day},
+		{"plan limit", user.PlanTypeEnterprise, 0, 7 * day},
+		{"past the limit", user.PlanTypeFree, 31 * 24 * time.Hour, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.RefreshTTL(ClientTypeMobile, tt.plan, now.Add(-tt.familyAge), now); got != tt.want {
+				t.Errorf("RefreshTTL() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	unlimited := NewSessionPolicy(config.SessionConfig{})
+	if got := unlimited.RefreshTTL(ClientTypeMobile, user.PlanTypeFree, now, now); got != 0 {
+		t.Errorf("RefreshTTL() without absolute lifetime = %d, want 0", got)
+	}
+}
@@ -0,0 +1,46 @@
+package billing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+)
+
+type EventType string
+
+const (
+	EventSubscriptionCreated   EventType = "SUBSCRIPTION_CREATED"
+	EventSubscriptionRenewed   EventType = "SUBSCRIPTION_RENEWED"
+	EventSubscriptionPastDue   EventType = "SUBSCRIPTION_PAST_DUE"
+	EventSubscriptionCancelled EventType = "SUBSCRIPTION_CANCELLED"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Event is a provider webhook normalized into the billing model. Type is
+// empty for events that do not affect subscriptions; they are stored but
+// ignored.
+type Event struct {
+	ID             string
+	Type           EventType
+	RawType        string
+	CustomerID     string
+	SubscriptionID string
+
+	// UserID comes from the metadata attached to the subscription at
+	// checkout. When absent, the user is resolved from a known subscription.
+	UserID *int64
+
+	// PlanType is empty when the provider plan is not mapped.
+	PlanType  user.PlanType
+	PeriodEnd *time.Time
+}
+
+// Provider adapts the webhooks of a payment provider.
+type Provider interface {
+	Name() string
+	SignatureHeader() string
+	Verify(payload []byte, signature string) error
+	Parse(payload []byte) (*Event, error)
+}
@@ -0,0 +1,85 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	ClaimEvent(ctx context.Context, event *WebhookEvent, staleBefore time.Time) (bool, error)
+	SaveEvent(ctx context.Context, event *WebhookEvent) error
+	FindSubscription(ctx context.Context, provider, subscriptionID string) (*Subscription, error)
+	SaveSubscription(ctx context.Context, subscription *Subscription) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// ClaimEvent inserts the event as PROCESSING on the unique (provider,
+// event_id) key. When the event already exists it is taken over only if it
+// failed or was left processing before staleBefore by a delivery that never
+// finished. It returns false when another delivery owns the event.
+func (r *GormRepository) ClaimEvent(ctx context.Context, event *WebhookEvent, staleBefore time.Time) (bool, error) {
+	now := utils.Now()
+	event.Status = WebhookProcessing
+	event.ClaimedAt = &now
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	result = r.db.WithContext(ctx).Model(&WebhookEvent{}).
+		Where("provider = ? AND event_id = ?", event.Provider, event.EventID).
+		Where("status = ? OR (status = ? AND claimed_at < ?)", WebhookFailed, WebhookProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     WebhookProcessing,
+			"claimed_at": now,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	if err := r.db.WithContext(ctx).
+		Select("id", "received_at").
+		Where("provider = ? AND event_id = ?", event.Provider, event.EventID).
+		First(event).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *GormRepository) SaveEvent(ctx context.Context, event *WebhookEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
+
+func (r *GormRepository) FindSubscription(ctx context.Context, provider, subscriptionID string) (*Subscription, error) {
+	var subscription Subscription
+	if err := r.db.WithContext(ctx).
+		Where("provider = ? AND subscription_id = ?", provider, subscriptionID).
+		First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *GormRepository) SaveSubscription(ctx context.Context, subscription *Subscription) error {
+	return r.db.WithContext(ctx).Save(subscription).Error
+}
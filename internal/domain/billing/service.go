@@ -0,0 +1,240 @@
+package billing
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/planlifecycle"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// staleClaimAfter is how long a claimed event may stay PROCESSING before a
+// redelivery takes it over, in case the instance that claimed it died.
+const staleClaimAfter = 10 * time.Minute
+
+// Lifecycle applies subscription changes to the user's plan. It is
+// implemented by planlifecycle.Service.
+type Lifecycle interface {
+	ApplySubscription(ctx context.Context, userID int64, change planlifecycle.SubscriptionChange) (*user.User, error)
+}
+
+// PlanCatalog resolves plan codes. It is implemented by plancatalog.Service.
+type PlanCatalog interface {
+	Rank(code user.PlanType) (int, bool)
+}
+
+type Service struct {
+	repo      Repository
+	lifecycle Lifecycle
+	plans     PlanCatalog
+	providers map[string]Provider
+	logger    logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	lifecycle Lifecycle,
+	plans PlanCatalog,
+	providers []Provider,
+	logger logger.Logger,
+) *Service {
+	byName := make(map[string]Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &Service{
+		repo:      repo,
+		lifecycle: lifecycle,
+		plans:     plans,
+		providers: byName,
+		logger:    logger,
+	}
+}
+
+func (s *Service) Provider(name string) (Provider, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, errors.New(errors.ENOTFOUND, "billing.provider_not_configured")
+	}
+	return provider, nil
+}
+
+// HandleWebhook verifies, stores and applies a provider webhook. Deliveries
+// of an event already processed, ignored or being processed are acknowledged
+// without effect; failed ones are retried. An error tells the provider to
+// deliver again.
+func (s *Service) HandleWebhook(ctx context.Context, providerName string, payload []byte, signature string) error {
+	provider, err := s.Provider(providerName)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.Verify(payload, signature); err != nil {
+		s.logger.Warn("Rejected billing webhook", zap.String("provider", providerName), zap.Error(err))
+		return errors.New(errors.EUNAUTHORIZED, "billing.invalid_signature")
+	}
+
+	event, err := provider.Parse(payload)
+	if err != nil {
+		return errors.New(errors.EINVALID, "billing.invalid_payload")
+	}
+
+	stored := &WebhookEvent{
+		Provider:   providerName,
+		EventID:    event.ID,
+		Type:       event.RawType,
+		Payload:    string(payload),
+		ReceivedAt: utils.Now(),
+	}
+	claimed, err := s.repo.ClaimEvent(ctx, stored, utils.Now().Add(-staleClaimAfter))
+	if err != nil {
+		s.logger.Error("Failed to claim billing event", zap.String("eventId", event.ID), zap.Error(err))
+		return errors.New(errors.EINTERNAL, "billing.processing_failed")
+	}
+	if !claimed {
+		s.logger.Info("Duplicate billing webhook acknowledged",
+			zap.String("provider", providerName), zap.String("eventId", event.ID))
+		return nil
+	}
+
+	status, userID, applyErr := s.apply(ctx, providerName, event)
+
+	now := utils.Now()
+	stored.Status = status
+	stored.UserID = userID
+	stored.ProcessedAt = &now
+	stored.Error = nil
+	if applyErr != nil {
+		message := applyErr.Error()
+		stored.Error = &message
+	}
+	if err := s.repo.SaveEvent(ctx, stored); err != nil {
+		s.logger.Error("Failed to store billing event", zap.String("eventId", event.ID), zap.Error(err))
+		return errors.New(errors.EINTERNAL, "billing.processing_failed")
+	}
+
+	if applyErr != nil {
+		s.logger.Error("Failed to apply billing event",
+			zap.String("provider", providerName),
+			zap.String("eventId", event.ID),
+			zap.String("type", event.RawType),
+			zap.Error(applyErr),
+		)
+		return errors.New(errors.EINTERNAL, "billing.processing_failed")
+	}
+
+	s.logger.Info("Billing webhook handled",
+		zap.String("provider", providerName),
+		zap.String("eventId", event.ID),
+		zap.String("type", event.RawType),
+		zap.String("status", string(status)),
+	)
+	return nil
+}
+
+func (s *Service) apply(ctx context.Context, providerName string, event *Event) (WebhookStatus, *int64, error) {
+	if event.Type == "" || event.SubscriptionID == "" {
+		return WebhookIgnored, nil, nil
+	}
+
+	sub, err := s.repo.FindSubscription(ctx, providerName, event.SubscriptionID)
+	if err != nil && !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return WebhookFailed, nil, err
+	}
+
+	if sub == nil {
+		if event.UserID == nil {
+			s.logger.Warn("Billing event for an unknown subscription without user",
+				zap.String("provider", providerName), zap.String("subscriptionId", event.SubscriptionID))
+			return WebhookIgnored, nil, nil
+		}
+		sub = &Subscription{
+			UserID:         *event.UserID,
+			Provider:       providerName,
+			SubscriptionID: event.SubscriptionID,
+			CreatedAt:      utils.Now(),
+		}
+	}
+	userID := sub.UserID
+
+	// Providers do not guarantee ordering: a late renewal must not revive a
+	// cancelled subscription.
+	if sub.Status == SubscriptionCancelled && event.Type != EventSubscriptionCancelled {
+		return WebhookIgnored, &userID, nil
+	}
+
+	change := planlifecycle.SubscriptionChange{
+		PlanType:  event.PlanType,
+		ExpiresAt: latest(sub.CurrentPeriodEnd, event.PeriodEnd),
+		Reference: providerName + ":" + event.SubscriptionID,
+	}
+	if change.PlanType == "" {
+		change.PlanType = sub.PlanType
+	}
+
+	switch event.Type {
+	case EventSubscriptionCreated:
+		change.Event = planlifecycle.EventSubscriptionStarted
+		sub.Status = SubscriptionActive
+	case EventSubscriptionRenewed:
+		change.Event = planlifecycle.EventSubscriptionRenewed
+		sub.Status = SubscriptionActive
+	case EventSubscriptionPastDue:
+		change.Event = planlifecycle.EventSubscriptionPastDue
+		sub.Status = SubscriptionPastDue
+	case EventSubscriptionCancelled:
+		change.Event = planlifecycle.EventSubscriptionCancelled
+		sub.Status = SubscriptionCancelled
+	}
+
+	if sub.Status == SubscriptionActive && !s.isPaidPlan(change.PlanType) {
+		s.logger.Warn("Billing event with an unmapped plan",
+			zap.String("provider", providerName), zap.String("subscriptionId", event.SubscriptionID))
+		return WebhookIgnored, &userID, nil
+	}
+
+	if _, err := s.lifecycle.ApplySubscription(ctx, userID, change); err != nil {
+		if errors.ErrorCode(err) == errors.ENOTFOUND {
+			return WebhookIgnored, &userID, nil
+		}
+		return WebhookFailed, &userID, err
+	}
+
+	if event.CustomerID != "" {
+		sub.CustomerID = event.CustomerID
+	}
+	sub.PlanType = change.PlanType
+	sub.CurrentPeriodEnd = change.ExpiresAt
+	sub.UpdatedAt = utils.Now()
+	if err := s.repo.SaveSubscription(ctx, sub); err != nil {
+		return WebhookFailed, &userID, err
+	}
+	return WebhookProcessed, &userID, nil
+}
+
+// isPaidPlan reports whether the catalog has the plan ranked above FREE.
+func (s *Service) isPaidPlan(plan user.PlanType) bool {
+	rank, ok := s.plans.Rank(plan)
+	if !ok {
+		return false
+	}
+	freeRank, _ := s.plans.Rank(user.PlanTypeFree)
+	return rank > freeRank
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.After(*b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,192 @@
+package billing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/planlifecycle"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type fakeRepository struct {
+	mu            sync.Mutex
+	events        map[string]*WebhookEvent
+	subscriptions map[string]*Subscription
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		events:        map[string]*WebhookEvent{},
+		subscriptions: map[string]*Subscription{},
+	}
+}
+
+func (r *fakeRepository) ClaimEvent(_ context.Context, event *WebhookEvent, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := event.Provider + ":" + event.EventID
+	now := time.Now()
+	stored, ok := r.events[key]
+	if ok {
+		stale := stored.Status == WebhookProcessing && stored.ClaimedAt.Before(staleBefore)
+		if stored.Status != WebhookFailed && !stale {
+			return false, nil
+		}
+		event.ID = stored.ID
+	} else {
+		event.ID = int64(len(r.events) + 1)
+	}
+
+	event.Status = WebhookProcessing
+	event.ClaimedAt = &now
+	copied := *event
+	r.events[key] = &copied
+	return true, nil
+}
+
+func (r *fakeRepository) SaveEvent(_ context.Context, event *WebhookEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *event
+	r.events[event.Provider+":"+event.EventID] = &copied
+	return nil
+}
+
+func (r *fakeRepository) FindSubscription(_ context.Context, provider, subscriptionID string) (*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub, ok := r.subscriptions[provider+":"+subscriptionID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (r *fakeRepository) SaveSubscription(_ context.Context, subscription *Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *subscription
+	r.subscriptions[subscription.Provider+":"+subscription.SubscriptionID] = &copied
+	return nil
+}
+
+type fakeLifecycle struct {
+	mu      sync.Mutex
+	changes []planlifecycle.SubscriptionChange
+}
+
+func (l *fakeLifecycle) ApplySubscription(_ context.Context, userID int64, change planlifecycle.SubscriptionChange) (*user.User, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.changes = append(l.changes, change)
+	return &user.User{ID: userID}, nil
+}
+
+type fakeCatalog map[user.PlanType]int
+
+func (c fakeCatalog) Rank(code user.PlanType) (int, bool) {
+	rank, ok := c[code]
+	return rank, ok
+}
+
+func newTestService(t *testing.T, plans fakeCatalog) (*Service, *StripeProvider, *fakeRepository, *fakeLifecycle) {
+	t.Helper()
+	log, _ := logger.NewLogger("test", "none")
+	provider := NewStripeProvider("whsec_test", 5*time.Minute, nil)
+	repo := newFakeRepository()
+	lifecycle := &fakeLifecycle{}
+	return NewService(repo, lifecycle, plans, []Provider{provider}, log), provider, repo, lifecycle
+}
+
+func TestHandleWebhookIsIdempotent(t *testing.T) {
+	service, provider, repo, lifecycle := newTestService(t, fakeCatalog{user.PlanTypeFree: 0, user.PlanTypePro: 1})
+	payload := readFixture(t, "customer.subscription.created")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := service.HandleWebhook(ctx, "stripe", payload, provider.Sign(payload, time.Now())); err != nil {
+			t.Fatalf("delivery %d: HandleWebhook() = %v", i+1, err)
+		}
+	}
+
+	if len(lifecycle.changes) != 1 {
+		t.Fatalf("applied %d times, want 1", len(lifecycle.changes))
+	}
+	if got := lifecycle.changes[0].Event; got != planlifecycle.EventSubscriptionStarted {
+		t.Errorf("Event = %q, want %q", got, planlifecycle.EventSubscriptionStarted)
+	}
+	if got := repo.events["stripe:evt_1QfixtureSubCreated"].Status; got != WebhookProcessed {
+		t.Errorf("stored status = %q, want %q", got, WebhookProcessed)
+	}
+}
+
+func TestHandleWebhookConcurrentDeliveries(t *testing.T) {
+	service, provider, _, lifecycle := newTestService(t, fakeCatalog{user.PlanTypeFree: 0, user.PlanTypePro: 1})
+	payload := readFixture(t, "customer.subscription.created")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := service.HandleWebhook(ctx, "stripe", payload, provider.Sign(payload, time.Now())); err != nil {
+				t.Errorf("HandleWebhook() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(lifecycle.changes) != 1 {
+		t.Fatalf("applied %d times, want 1", len(lifecycle.changes))
+	}
+}
+
+func TestHandleWebhookPlanCatalog(t *testing.T) {
+	tests := []struct {
+		name       string
+		plans      fakeCatalog
+		wantStatus WebhookStatus
+	}{
+		{"paid plan", fakeCatalog{user.PlanTypeFree: 0, user.PlanTypePro: 1}, WebhookProcessed},
+		{"plan not in catalog", fakeCatalog{user.PlanTypeFree: 0}, WebhookIgnored},
+		{"plan not above free", fakeCatalog{user.PlanTypeFree: 1, user.PlanTypePro: 1}, WebhookIgnored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, provider, repo, _ := newTestService(t, tt.plans)
+			payload := readFixture(t, "customer.subscription.created")
+
+			if err := service.HandleWebhook(context.Background(), "stripe", payload, provider.Sign(payload, time.Now())); err != nil {
+				t.Fatalf("HandleWebhook() = %v", err)
+			}
+			if got := repo.events["stripe:evt_1QfixtureSubCreated"].Status; got != tt.wantStatus {
+				t.Errorf("stored status = %q, want %q", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandleWebhookRejectsExpiredSignature(t *testing.T) {
+	service, provider, repo, _ := newTestService(t, fakeCatalog{user.PlanTypeFree: 0, user.PlanTypePro: 1})
+	payload := readFixture(t, "invoice.paid")
+
+	err := service.HandleWebhook(context.Background(), "stripe", payload, provider.Sign(payload, time.Now().Add(-time.Hour)))
+	if err == nil {
+		t.Fatal("HandleWebhook() = nil, want an error")
+	}
+	if len(repo.events) != 0 {
+		t.Errorf("stored %d events, want 0", len(repo.events))
+	}
+}
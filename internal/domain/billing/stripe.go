@@ -0,0 +1,305 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+// StripeProvider verifies and parses Stripe-compatible webhooks
+// (Stripe-Signature header, customer.subscription.* and invoice.* events).
+type StripeProvider struct {
+	secret     []byte
+	tolerance  time.Duration
+	pricePlans map[string]user.PlanType
+	now        func() time.Time
+}
+
+// NewStripeProvider maps price IDs to plans through pricePlans; prices not in
+// the map fall back to a "plan_type" metadata entry on the price or the
+// subscription.
+func NewStripeProvider(secret string, tolerance time.Duration, pricePlans map[string]string) *StripeProvider {
+	plans := make(map[string]user.PlanType, len(pricePlans))
+	for price, plan := range pricePlans {
+		plans[price] = user.PlanType(strings.ToUpper(plan))
+	}
+
+	return &StripeProvider{
+		secret:     []byte(secret),
+		tolerance:  tolerance,
+		pricePlans: plans,
+		now:        utils.Now,
+	}
+}
+
+func (p *StripeProvider) Name() string {
+	return "stripe"
+}
+
+func (p *StripeProvider) SignatureHeader() string {
+	return "Stripe-Signature"
+}
+
+// Sign builds a Stripe-Signature header for the payload, used to replay
+// recorded fixtures.
+func (p *StripeProvider) Sign(payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + p.signature(timestamp, payload)
+}
+
+// Verify checks the header "t=<unix>,v1=<hex hmac-sha256>" against the
+// payload and rejects timestamps outside the tolerance to prevent replays.
+func (p *StripeProvider) Verify(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := p.now().Sub(time.Unix(unix, 0)); age > p.tolerance || age < -p.tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := []byte(p.signature(timestamp, payload))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func (p *StripeProvider) signature(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripePrice struct {
+	ID       string            `json:"id"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type stripeSubscription struct {
+	ID               string            `json:"id"`
+	Customer         stripeID          `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			CurrentPeriodEnd int64       `json:"current_period_end"`
+			Price            stripePrice `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+type stripeSubscriptionDetails struct {
+	Subscription stripeID          `json:"subscription"`
+	Metadata     map[string]string `json:"metadata"`
+}
+
+type stripeInvoice struct {
+	ID                  string                    `json:"id"`
+	Customer            stripeID                  `json:"customer"`
+	Subscription        stripeID                  `json:"subscription"`
+	SubscriptionDetails stripeSubscriptionDetails `json:"subscription_details"`
+	Parent              struct {
+		SubscriptionDetails stripeSubscriptionDetails `json:"subscription_details"`
+	} `json:"parent"`
+	Lines struct {
+		Data []struct {
+			Period struct {
+				End int64 `json:"end"`
+			} `json:"period"`
+			Price stripePrice `json:"price"`
+		} `json:"data"`
+	} `json:"lines"`
+}
+
+// stripeID accepts both an ID and an expanded object.
+type stripeID string
+
+func (id *stripeID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		var object struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		*id = stripeID(object.ID)
+		return nil
+	}
+
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value != nil {
+		*id = stripeID(*value)
+	}
+	return nil
+}
+
+func (p *StripeProvider) Parse(payload []byte) (*Event, error) {
+	var raw stripeEvent
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	if raw.ID == "" || raw.Type == "" {
+		return nil, fmt.Errorf("invalid event: missing id or type")
+	}
+
+	event := &Event{ID: raw.ID, RawType: raw.Type}
+
+	switch {
+	case strings.HasPrefix(raw.Type, "customer.subscription."):
+		var sub stripeSubscription
+		if err := json.Unmarshal(raw.Data.Object, &sub); err != nil {
+			return nil, fmt.Errorf("invalid subscription: %w", err)
+		}
+		p.parseSubscription(event, raw.Type, &sub)
+
+	case strings.HasPrefix(raw.Type, "invoice."):
+		var invoice stripeInvoice
+		if err := json.Unmarshal(raw.Data.Object, &invoice); err != nil {
+			return nil, fmt.Errorf("invalid invoice: %w", err)
+		}
+		p.parseInvoice(event, raw.Type, &invoice)
+	}
+
+	return event, nil
+}
+
+func (p *StripeProvider) parseSubscription(event *Event, eventType string, sub *stripeSubscription) {
+	event.SubscriptionID = sub.ID
+	event.CustomerID = string(sub.Customer)
+	event.UserID = parseUserID(sub.Metadata)
+
+	periodEnd := sub.CurrentPeriodEnd
+	var price stripePrice
+	if len(sub.Items.Data) > 0 {
+		price = sub.Items.Data[0].Price
+		if periodEnd == 0 {
+			periodEnd = sub.Items.Data[0].CurrentPeriodEnd
+		}
+	}
+	event.PlanType = p.planFor(price, sub.Metadata)
+	event.PeriodEnd = unixTime(periodEnd)
+
+	switch eventType {
+	case "customer.subscription.created":
+		if isActiveStripeStatus(sub.Status) {
+			event.Type = EventSubscriptionCreated
+		}
+	case "customer.subscription.updated":
+		switch {
+		case isActiveStripeStatus(sub.Status):
+			event.Type = EventSubscriptionRenewed
+		case sub.Status == "past_due" || sub.Status == "unpaid":
+			event.Type = EventSubscriptionPastDue
+		case sub.Status == "canceled" || sub.Status == "incomplete_expired":
+			event.Type = EventSubscriptionCancelled
+		}
+	case "customer.subscription.deleted":
+		event.Type = EventSubscriptionCancelled
+	}
+}
+
+func (p *StripeProvider) parseInvoice(event *Event, eventType string, invoice *stripeInvoice) {
+	details := invoice.Parent.SubscriptionDetails
+	if details.Subscription == "" {
+		details = invoice.SubscriptionDetails
+	}
+
+	event.SubscriptionID = string(invoice.Subscription)
+	if event.SubscriptionID == "" {
+		event.SubscriptionID = string(details.Subscription)
+	}
+	if event.SubscriptionID == "" {
+		// One-off invoices are not related to subscriptions.
+		return
+	}
+
+	event.CustomerID = string(invoice.Customer)
+	event.UserID = parseUserID(details.Metadata)
+
+	if len(invoice.Lines.Data) > 0 {
+		line := invoice.Lines.Data[0]
+		event.PlanType = p.planFor(line.Price, details.Metadata)
+		event.PeriodEnd = unixTime(line.Period.End)
+	}
+
+	switch eventType {
+	case "invoice.paid", "invoice.payment_succeeded":
+		event.Type = EventSubscriptionRenewed
+	case "invoice.payment_failed":
+		event.Type = EventSubscriptionPastDue
+	}
+}
+
+func (p *StripeProvider) planFor(price stripePrice, metadata map[string]string) user.PlanType {
+	if plan, ok := p.pricePlans[price.ID]; ok {
+		return plan
+	}
+	if plan := price.Metadata["plan_type"]; plan != "" {
+		return user.PlanType(strings.ToUpper(plan))
+	}
+	return user.PlanType(strings.ToUpper(metadata["plan_type"]))
+}
+
+func isActiveStripeStatus(status string) bool {
+	return status == "active" || status == "trialing"
+}
+
+func parseUserID(metadata map[string]string) *int64 {
+	id, err := strconv.ParseInt(metadata["user_id"], 10, 64)
+	if err != nil || id <= 0 {
+		return nil
+	}
+	return &id
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
@@ -0,0 +1,127 @@
+package billing
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "stripe", name+".json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return payload
+}
+
+func newTestStripeProvider(now time.Time) *StripeProvider {
+	provider := NewStripeProvider("whsec_test", 5*time.Minute, nil)
+	provider.now = func() time.Time { return now }
+	return provider
+}
+
+func TestStripeProviderFixtures(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	provider := newTestStripeProvider(now)
+
+	tests := []struct {
+		fixture   string
+		eventID   string
+		eventType EventType
+		periodEnd time.Time
+	}{
+		{"customer.subscription.created", "evt_1QfixtureSubCreated", EventSubscriptionCreated, time.Unix(1762678400, 0).UTC()},
+		{"customer.subscription.updated.past_due", "evt_1QfixtureSubPastDue", EventSubscriptionPastDue, time.Unix(1767948800, 0).UTC()},
+		{"customer.subscription.deleted", "evt_1QfixtureSubDeleted", EventSubscriptionCancelled, time.Unix(1767948800, 0).UTC()},
+		{"invoice.paid", "evt_1QfixtureInvoicePaid", EventSubscriptionRenewed, time.Unix(1765270400, 0).UTC()},
+		{"invoice.payment_failed", "evt_1QfixtureInvoiceFailed", EventSubscriptionPastDue, time.Unix(1767948800, 0).UTC()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			payload := readFixture(t, tt.fixture)
+
+			if err := provider.Verify(payload, provider.Sign(payload, now)); err != nil {
+				t.Fatalf("Verify() = %v, want nil", err)
+			}
+
+			event, err := provider.Parse(payload)
+			if err != nil {
+				t.Fatalf("Parse() = %v", err)
+			}
+			if event.ID != tt.eventID {
+				t.Errorf("ID = %q, want %q", event.ID, tt.eventID)
+			}
+			if event.Type != tt.eventType {
+				t.Errorf("Type = %q, want %q", event.Type, tt.eventType)
+			}
+			if event.SubscriptionID != "sub_1QfixtureSubscription" {
+				t.Errorf("SubscriptionID = %q, want sub_1QfixtureSubscription", event.SubscriptionID)
+			}
+			if event.PlanType != user.PlanTypePro {
+				t.Errorf("PlanType = %q, want %q", event.PlanType, user.PlanTypePro)
+			}
+			if event.PeriodEnd == nil || !event.PeriodEnd.Equal(tt.periodEnd) {
+				t.Errorf("PeriodEnd = %v, want %v", event.PeriodEnd, tt.periodEnd)
+			}
+			if event.UserID == nil || *event.UserID != 1 {
+				t.Errorf("UserID = %v, want 1", event.UserID)
+			}
+		})
+	}
+}
+
+func TestStripeProviderVerify(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	provider := newTestStripeProvider(now)
+	payload := readFixture(t, "customer.subscription.created")
+	other := NewStripeProvider("whsec_other", 5*time.Minute, nil)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  string
+		wantErr bool
+	}{
+		{"valid", payload, provider.Sign(payload, now), false},
+		{"within tolerance", payload, provider.Sign(payload, now.Add(-4*time.Minute)), false},
+		{"expired timestamp", payload, provider.Sign(payload, now.Add(-6*time.Minute)), true},
+		{"future timestamp", payload, provider.Sign(payload, now.Add(6*time.Minute)), true},
+		{"other secret", payload, other.Sign(payload, now), true},
+		{"tampered payload", append([]byte(" "), payload...), provider.Sign(payload, now), true},
+		{"missing signature", payload, "t=1768046400", true},
+		{"empty header", payload, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.Verify(tt.payload, tt.header)
+			if tt.wantErr {
+				if !stderrors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("Verify() = %v, want ErrInvalidSignature", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestStripeProviderPricePlans(t *testing.T) {
+	provider := NewStripeProvider("whsec_test", 5*time.Minute, map[string]string{"price_pro_monthly": "enterprise"})
+
+	event, err := provider.Parse(readFixture(t, "customer.subscription.created"))
+	if err != nil {
+		t.Fatalf("Parse() = %v", err)
+	}
+	if event.PlanType != user.PlanTypeEnterprise {
+		t.Errorf("PlanType = %q, want %q", event.PlanType, user.PlanTypeEnterprise)
+	}
+}
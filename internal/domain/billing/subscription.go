@@ -0,0 +1,63 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive    SubscriptionStatus = "ACTIVE"
+	SubscriptionPastDue   SubscriptionStatus = "PAST_DUE"
+	SubscriptionCancelled SubscriptionStatus = "CANCELLED"
+)
+
+type Subscription struct {
+	ID               int64              `gorm:"primaryKey;autoIncrement"`
+	UserID           int64              `gorm:"not null;index"`
+	Provider         string             `gorm:"size:30;not null"`
+	CustomerID       string             `gorm:"size:255;not null"`
+	SubscriptionID   string             `gorm:"size:255;not null"`
+	Status           SubscriptionStatus `gorm:"size:20;not null"`
+	PlanType         user.PlanType      `gorm:"size:20;not null"`
+	CurrentPeriodEnd *time.Time         `gorm:"column:current_period_end"`
+	CreatedAt        time.Time          `gorm:"not null"`
+	UpdatedAt        time.Time
+}
+
+func (Subscription) TableName() string {
+	return "billing_subscriptions"
+}
+
+type WebhookStatus string
+
+const (
+	WebhookProcessing WebhookStatus = "PROCESSING"
+	WebhookProcessed  WebhookStatus = "PROCESSED"
+	WebhookIgnored    WebhookStatus = "IGNORED"
+	WebhookFailed     WebhookStatus = "FAILED"
+)
+
+// WebhookEvent stores every verified webhook. The provider event ID makes
+// deliveries idempotent: the event is claimed on that key before it is
+// applied, so a retry or a concurrent delivery is acknowledged without being
+// applied again.
+type WebhookEvent struct {
+	ID          int64         `gorm:"primaryKey;autoIncrement"`
+	Provider    string        `gorm:"size:30;not null"`
+	EventID     string        `gorm:"size:255;not null"`
+	Type        string        `gorm:"size:100;not null"`
+	Payload     string        `gorm:"type:jsonb;not null"`
+	Status      WebhookStatus `gorm:"size:20;not null"`
+	UserID      *int64        `gorm:"column:user_id"`
+	Error       *string       `gorm:"column:error"`
+	ReceivedAt  time.Time     `gorm:"not null"`
+	ClaimedAt   *time.Time    `gorm:"column:claimed_at"`
+	ProcessedAt *time.Time    `gorm:"column:processed_at"`
+}
+
+func (WebhookEvent) TableName() string {
+	return "billing_events"
+}
@@ -0,0 +1,208 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/passwordRecovery"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+)
+
+// Archive records only carry data about the user; secrets such as password
+// hashes, token values and token hashes are never exported.
+
+type profileRecord struct {
+	ID         int64             `json:"id"`
+	Name       string            `json:"name"`
+	Email      string            `json:"email"`
+	ImgURL     *string           `json:"imgUrl,omitempty"`
+	Admin      bool              `json:"admin"`
+	Active     bool              `json:"active"`
+	Source     string            `json:"source"`
+	Metadata   user.UserMetadata `json:"metadata"`
+	LastAccess *time.Time        `json:"lastAccess,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+type sessionRecord struct {
+	ID             uuid.UUID  `json:"id"`
+	DeviceID       string     `json:"deviceId"`
+	ClientType     string     `json:"clientType"`
+	Profile        string     `json:"profile"`
+	UserAgent      string     `json:"userAgent,omitempty"`
+	IpAddress      string     `json:"ipAddress,omitempty"`
+	OrganizationID *int64     `json:"organizationId,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	UsedAt         *time.Time `json:"usedAt,omitempty"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+}
+
+type emailVerificationRecord struct {
+	Email      string     `json:"email"`
+	Used       bool       `json:"used"`
+	VerifiedAt *time.Time `json:"verifiedAt,omitempty"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type passwordResetRecord struct {
+	Email     string     `json:"email"`
+	Used      bool       `json:"used"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type fileRecord struct {
+	ID               int64     `json:"id"`
+	OriginalFilename string    `json:"originalFilename"`
+	ContentType      string    `json:"contentType"`
+	FileSize         int64     `json:"fileSize"`
+	FileType         string    `json:"fileType"`
+	StorageProvider  string    `json:"storageProvider"`
+	ArchivePath      string    `json:"archivePath,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type archiveData struct {
+	user               *user.User
+	sessions           []auth.RefreshToken
+	emailVerifications []emailverification.EmailVerificationToken
+	passwordResets     []passwordRecovery.PasswordResetToken
+	files              []storage.FileReference
+}
+
+type fileOpener func(ctx context.Context, key string) (io.ReadCloser, error)
+
+func writeArchive(ctx context.Context, w io.Writer, data archiveData, open fileOpener) error {
+	zw := zip.NewWriter(w)
+
+	u := data.user
+	if err := writeJSON(zw, "profile.json", profileRecord{
+		ID:         u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		ImgURL:     u.ImgURL,
+		Admin:      u.Admin,
+		Active:     u.Active,
+		Source:     u.Source,
+		Metadata:   u.Metadata,
+		LastAccess: u.LastAccess,
+		CreatedAt:  u.CreatedAt,
+		UpdatedAt:  u.UpdatedAt,
+	}); err != nil {
+		return err
+	}
+
+	sessions := make([]sessionRecord, 0, len(data.sessions))
+	for _, s := range data.sessions {
+		sessions = append(sessions, sessionRecord{
+			ID:             s.ID,
+			DeviceID:       s.DeviceID,
+			ClientType:     s.ClientType,
+			Profile:        s.Profile,
+			UserAgent:      s.UserAgent,
+			IpAddress:      s.IpAddress,
+			OrganizationID: s.OrganizationID,
+			CreatedAt:      s.CreatedAt,
+			ExpiresAt:      s.ExpiresAt,
+			UsedAt:         s.UsedAt,
+			RevokedAt:      s.RevokedAt,
+		})
+	}
+	if err := writeJSON(zw, "sessions.json", sessions); err != nil {
+		return err
+	}
+
+	verifications := make([]emailVerificationRecord, 0, len(data.emailVerifications))
+	for _, t := range data.emailVerifications {
+		verifications = append(verifications, emailVerificationRecord{
+			Email:      t.Email,
+			Used:       t.Used,
+			VerifiedAt: t.VerifiedAt,
+			ExpiresAt:  t.ExpiresAt,
+			CreatedAt:  t.CreatedAt,
+		})
+	}
+	if err := writeJSON(zw, "email_verifications.json", verifications); err != nil {
+		return err
+	}
+
+	resets := make([]passwordResetRecord, 0, len(data.passwordResets))
+	for _, t := range data.passwordResets {
+		resets = append(resets, passwordResetRecord{
+			Email:     t.Email,
+			Used:      t.Used,
+			UsedAt:    t.UsedAt,
+			ExpiresAt: t.ExpiresAt,
+			CreatedAt: t.CreatedAt,
+		})
+	}
+	if err := writeJSON(zw, "password_resets.json", resets); err != nil {
+		return err
+	}
+
+	files := make([]fileRecord, 0, len(data.files))
+	for _, f := range data.files {
+		record := fileRecord{
+			ID:               f.ID,
+			OriginalFilename: f.OriginalFilename,
+			ContentType:      f.ContentType,
+			FileSize:         f.FileSize,
+			FileType:         f.FileType,
+			StorageProvider:  f.StorageProvider,
+			CreatedAt:        f.CreatedAt,
+		}
+
+		archivePath := fmt.Sprintf("files/%d_%s", f.ID, path.Base(f.OriginalFilename))
+		if err := copyFile(ctx, zw, archivePath, f.StorageKey, open); err != nil {
+			return fmt.Errorf("failed to add file %d to archive: %w", f.ID, err)
+		}
+		record.ArchivePath = archivePath
+
+		files = append(files, record)
+	}
+	if err := writeJSON(zw, "files.json", files); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func copyFile(ctx context.Context, zw *zip.Writer, name, key string, open fileOpener) error {
+	reader, err := open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, reader)
+	return err
+}
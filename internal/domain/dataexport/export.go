@@ -0,0 +1,35 @@
+package dataexport
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending    Status = "PENDING"
+	StatusProcessing Status = "PROCESSING"
+	StatusCompleted  Status = "COMPLETED"
+	StatusFailed     Status = "FAILED"
+	StatusExpired    Status = "EXPIRED"
+)
+
+type DataExport struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement"`
+	UserID      int64      `gorm:"not null;index"`
+	Status      Status     `gorm:"size:20;not null;default:PENDING"`
+	StorageKey  *string    `gorm:"column:storage_key;size:500"`
+	FileSize    int64      `gorm:"not null;default:0"`
+	Error       *string    `gorm:"column:error"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at"`
+	CreatedAt   time.Time  `gorm:"not null"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+func (e *DataExport) IsRunning() bool {
+	return e.Status == StatusPending || e.Status == StatusProcessing
+}
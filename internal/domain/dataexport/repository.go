@@ -0,0 +1,72 @@
+package dataexport
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, export *DataExport) error
+	Save(ctx context.Context, export *DataExport) error
+	FindLatestByUserID(ctx context.Context, userID int64) (*DataExport, error)
+	FindByUserID(ctx context.Context, userID int64) ([]DataExport, error)
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]DataExport, error)
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, export *DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *GormRepository) Save(ctx context.Context, export *DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
+
+func (r *GormRepository) FindLatestByUserID(ctx context.Context, userID int64) (*DataExport, error) {
+	var export DataExport
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *GormRepository) FindByUserID(ctx context.Context, userID int64) ([]DataExport, error) {
+	var exports []DataExport
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *GormRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]DataExport, error) {
+	var exports []DataExport
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", StatusCompleted, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *GormRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&DataExport{}).Error
+}
@@ -0,0 +1,277 @@
+package dataexport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/auth"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/emailverification"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/passwordRecovery"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// An export that stays pending or processing for longer than this is
+// considered abandoned (e.g. the instance restarted) and no longer blocks
+// new requests.
+const staleAfter = time.Hour
+
+const cleanupBatchSize = 100
+
+// SessionStore is the subset of the auth repository needed to export the
+// sessions of a user.
+type SessionStore interface {
+	FindRefreshTokensByUserID(ctx context.Context, userID int64) ([]auth.RefreshToken, error)
+}
+
+type Service struct {
+	repo             Repository
+	userRepo         user.UserService
+	sessions         SessionStore
+	verificationRepo emailverification.Repository
+	resetRepo        passwordRecovery.Repository
+	storageService   *storage.Service
+	emailSender      email.EmailSender
+	cooldown         time.Duration
+	linkExpiration   time.Duration
+	logger           logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	sessions SessionStore,
+	verificationRepo emailverification.Repository,
+	resetRepo passwordRecovery.Repository,
+	storageService *storage.Service,
+	emailSender email.EmailSender,
+	cooldownHours int,
+	linkExpirationHours int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:             repo,
+		userRepo:         userRepo,
+		sessions:         sessions,
+		verificationRepo: verificationRepo,
+		resetRepo:        resetRepo,
+		storageService:   storageService,
+		emailSender:      emailSender,
+		cooldown:         time.Duration(cooldownHours) * time.Hour,
+		linkExpiration:   time.Duration(linkExpirationHours) * time.Hour,
+		logger:           logger,
+	}
+}
+
+// RequestExport registers a personal data export and assembles it in the
+// background. The download link is delivered by email once it is ready.
+func (s *Service) RequestExport(ctx context.Context, userID int64) (*DataExport, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	now := utils.Now()
+	if latest, err := s.repo.FindLatestByUserID(ctx, userID); err == nil {
+		if latest.IsRunning() && now.Sub(latest.CreatedAt) < staleAfter {
+			return nil, errors.New(errors.ECONFLICT, "data_export.in_progress")
+		}
+		delivered := latest.Status == StatusCompleted || latest.Status == StatusExpired
+		if next := latest.CreatedAt.Add(s.cooldown); delivered && now.Before(next) {
+			return nil, errors.New(errors.ERATELIMIT, "data_export.too_soon", next.Format(time.RFC3339))
+		}
+	}
+
+	export := &DataExport{
+		UserID:    userID,
+		Status:    StatusPending,
+		CreatedAt: now,
+	}
+	if err := s.repo.Create(ctx, export); err != nil {
+		s.logger.Error("Failed to create data export", zap.Int64("userId", userID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "data_export.request_failed")
+	}
+
+	go s.process(context.Background(), *export, u)
+
+	s.logger.Info("Data export requested", zap.Int64("userId", userID), zap.Int64("exportId", export.ID))
+	return export, nil
+}
+
+func (s *Service) GetLatest(ctx context.Context, userID int64) (*DataExport, error) {
+	export, err := s.repo.FindLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "data_export.not_found")
+	}
+	return export, nil
+}
+
+func (s *Service) process(ctx context.Context, export DataExport, u *user.User) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.fail(ctx, &export, u, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	export.Status = StatusProcessing
+	if err := s.repo.Save(ctx, &export); err != nil {
+		s.logger.Error("Failed to update data export", zap.Int64("exportId", export.ID), zap.Error(err))
+	}
+
+	downloadURL, err := s.build(ctx, &export, u)
+	if err != nil {
+		s.fail(ctx, &export, u, err)
+		return
+	}
+
+	if err := s.sendExportEmail(ctx, u, downloadURL); err != nil {
+		s.logger.Error("Failed to send data export email", zap.Int64("exportId", export.ID), zap.Int64("userId", u.ID), zap.Error(err))
+	}
+
+	s.logger.Info("Data export completed", zap.Int64("exportId", export.ID), zap.Int64("userId", u.ID), zap.Int64("size", export.FileSize))
+}
+
+func (s *Service) fail(ctx context.Context, export *DataExport, u *user.User, err error) {
+	s.logger.Error("Failed to build data export", zap.Int64("exportId", export.ID), zap.Int64("userId", u.ID), zap.Error(err))
+
+	message := err.Error()
+	export.Status = StatusFailed
+	export.Error = &message
+	if err := s.repo.Save(ctx, export); err != nil {
+		s.logger.Error("Failed to update data export", zap.Int64("exportId", export.ID), zap.Error(err))
+	}
+}
+
+func (s *Service) build(ctx context.Context, export *DataExport, u *user.User) (string, error) {
+	data := archiveData{user: u}
+
+	var err error
+	if data.sessions, err = s.sessions.FindRefreshTokensByUserID(ctx, u.ID); err != nil {
+		return "", fmt.Errorf("failed to load sessions: %w", err)
+	}
+	if data.emailVerifications, err = s.verificationRepo.FindByUserID(ctx, u.ID); err != nil {
+		return "", fmt.Errorf("failed to load email verifications: %w", err)
+	}
+	if data.passwordResets, err = s.resetRepo.FindByUserID(ctx, u.ID); err != nil {
+		return "", fmt.Errorf("failed to load password resets: %w", err)
+	}
+	if data.files, err = s.storageService.FindUserFiles(ctx, u.ID); err != nil {
+		return "", fmt.Errorf("failed to load files: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := writeArchive(ctx, tmp, data, s.storageService.Open); err != nil {
+		return "", err
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("exports/%d/%s.zip", u.ID, uuid.New().String())
+	if err := s.storageService.Store(ctx, key, tmp, "application/zip", info.Size()); err != nil {
+		return "", err
+	}
+
+	downloadURL, err := s.storageService.GetPresignedDownloadUrl(ctx, key, s.linkExpiration)
+	if err != nil {
+		return "", err
+	}
+
+	now := utils.Now()
+	expiresAt := now.Add(s.linkExpiration)
+	export.Status = StatusCompleted
+	export.StorageKey = &key
+	export.FileSize = info.Size()
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := s.repo.Save(ctx, export); err != nil {
+		return "", err
+	}
+
+	return downloadURL, nil
+}
+
+// CleanupExpired removes the archives whose download link has expired.
+func (s *Service) CleanupExpired(ctx context.Context) (int, error) {
+	exports, err := s.repo.FindExpired(ctx, utils.Now(), cleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i := range exports {
+		if err := s.expire(ctx, &exports[i]); err != nil {
+			s.logger.Error("Failed to remove expired data export", zap.Int64("exportId", exports[i].ID), zap.Error(err))
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.logger.Info("Expired data exports removed", zap.Int("count", removed))
+	}
+	return removed, nil
+}
+
+// DeleteUserExports removes every archive of a user, used when the account
+// is purged.
+func (s *Service) DeleteUserExports(ctx context.Context, userID int64) error {
+	exports, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if export.StorageKey == nil {
+			continue
+		}
+		if err := s.storageService.DeleteObject(ctx, *export.StorageKey); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.DeleteByUserID(ctx, userID)
+}
+
+func (s *Service) expire(ctx context.Context, export *DataExport) error {
+	if export.StorageKey != nil {
+		if err := s.storageService.DeleteObject(ctx, *export.StorageKey); err != nil {
+			return err
+		}
+	}
+
+	export.Status = StatusExpired
+	export.StorageKey = nil
+	return s.repo.Save(ctx, export)
+}
+
+func (s *Service) sendExportEmail(ctx context.Context, u *user.User, downloadURL string) error {
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "data_export", map[string]any{
+		"URL":             downloadURL,
+		"ExpirationHours": int(s.linkExpiration.Hours()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, u.Email, subject, body)
+}
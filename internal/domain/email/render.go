@@ -0,0 +1,81 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+// templates holds one set per file. Each file defines a "subject" and a
+// "body" template; texts come from the i18n catalogs through t.
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]*template.Template {
+	files, err := templateFiles.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+
+	parsed := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".html")
+		parsed[name] = template.Must(template.New(name).
+			Funcs(localeFuncs(i18n.DefaultLocale, time.UTC)).
+			ParseFS(templateFiles, "templates/"+file.Name()))
+	}
+	return parsed
+}
+
+func localeFuncs(locale string, loc *time.Location) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, params ...any) string {
+			return i18n.T(locale, key, params...)
+		},
+		"date": func(t time.Time) string {
+			return t.In(loc).Format(i18n.T(locale, "format.date"))
+		},
+	}
+}
+
+// Render builds the subject and HTML body of the named template in locale,
+// which may be any tag accepted by i18n.Resolve. Dates are shown in timezone,
+// or in UTC when it is empty or unknown.
+func Render(locale, timezone, name string, data map[string]any) (subject, body string, err error) {
+	locale = i18n.Resolve(locale)
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	base, ok := templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("email template %q not found", name)
+	}
+
+	tmpl, err := base.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	tmpl.Funcs(localeFuncs(locale, loc))
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = html.UnescapeString(strings.TrimSpace(buf.String()))
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
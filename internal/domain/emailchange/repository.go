@@ -0,0 +1,120 @@
+package emailchange
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyResolved = errors.New("email change already confirmed or cancelled")
+	ErrEmailChanged    = errors.New("user email changed since the request")
+)
+
+type Repository interface {
+	Create(ctx context.Context, request *EmailChangeRequest) error
+
+	FindByToken(ctx context.Context, token string) (*EmailChangeRequest, error)
+
+	FindByCancelToken(ctx context.Context, cancelToken string) (*EmailChangeRequest, error)
+
+	FindPendingByUserID(ctx context.Context, userID int64) (*EmailChangeRequest, error)
+
+	CancelPendingByUserID(ctx context.Context, userID int64) error
+
+	Save(ctx context.Context, request *EmailChangeRequest) error
+
+	Apply(ctx context.Context, request *EmailChangeRequest) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, request *EmailChangeRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+func (r *GormRepository) FindByToken(ctx context.Context, token string) (*EmailChangeRequest, error) {
+	var req EmailChangeRequest
+	if err := r.db.WithContext(ctx).
+		Where("token = ?", utils.HashToken(token)).
+		First(&req).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *GormRepository) FindByCancelToken(ctx context.Context, cancelToken string) (*EmailChangeRequest, error) {
+	var req EmailChangeRequest
+	if err := r.db.WithContext(ctx).
+		Where("cancel_token = ?", utils.HashToken(cancelToken)).
+		First(&req).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *GormRepository) FindPendingByUserID(ctx context.Context, userID int64) (*EmailChangeRequest, error) {
+	var req EmailChangeRequest
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL AND expires_at > ?", userID, utils.Now()).
+		Order("created_at DESC").
+		First(&req).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *GormRepository) CancelPendingByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).
+		Model(&EmailChangeRequest{}).
+		Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", userID).
+		Update("cancelled_at", utils.Now()).Error
+}
+
+func (r *GormRepository) Save(ctx context.Context, request *EmailChangeRequest) error {
+	return r.db.WithContext(ctx).Save(request).Error
+}
+
+// Apply marks the request as confirmed and moves the user to the new address
+// in one transaction, so a failure leaves both untouched.
+func (r *GormRepository) Apply(ctx context.Context, request *EmailChangeRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := utils.Now()
+
+		result := tx.Model(&EmailChangeRequest{}).
+			Where("id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", request.ID).
+			Update("confirmed_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyResolved
+		}
+
+		result = tx.Table("users").
+			Where("id = ? AND email = ? AND deleted_at IS NULL", request.UserID, request.OldEmail).
+			Updates(map[string]interface{}{
+				"email":      request.NewEmail,
+				"metadata":   gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{email_verified}', 'true'::jsonb)"),
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEmailChanged
+		}
+
+		request.ConfirmedAt = &now
+		return nil
+	})
+}
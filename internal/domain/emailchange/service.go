@@ -0,0 +1,265 @@
+package emailchange
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// SessionStore is the subset of the auth repository needed to sign the user
+// out after the email changes or an unrequested change is cancelled.
+type SessionStore interface {
+	RevokeAllUserRefreshTokens(ctx context.Context, userID int64) error
+}
+
+type Service struct {
+	repo        Repository
+	userRepo    user.UserService
+	sessions    SessionStore
+	emailSender email.EmailSender
+	frontendURL string
+	expiration  int
+	logger      logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	sessions SessionStore,
+	emailSender email.EmailSender,
+	frontendURL string,
+	expiration int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:        repo,
+		userRepo:    userRepo,
+		sessions:    sessions,
+		emailSender: emailSender,
+		frontendURL: frontendURL,
+		expiration:  expiration,
+		logger:      logger,
+	}
+}
+
+// RequestChange registers a pending email change. The new address only
+// replaces the current one after it is confirmed through the link sent to
+// it; the current address receives a notice with a cancel link.
+func (s *Service) RequestChange(ctx context.Context, userID int64, newEmail string) (*EmailChangeRequest, error) {
+	newEmail = strings.TrimSpace(newEmail)
+	if _, err := mail.ParseAddress(newEmail); err != nil {
+		return nil, errors.New(errors.EINVALID, "validation.invalid_email")
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if strings.EqualFold(u.Email, newEmail) {
+		return nil, errors.New(errors.EINVALID, "email_change.same_email")
+	}
+
+	if err := s.ensureAvailable(ctx, newEmail); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CancelPendingByUserID(ctx, u.ID); err != nil {
+		s.logger.Error("Failed to cancel pending email changes", zap.Int64("userId", u.ID), zap.Error(err))
+	}
+
+	token, err := generateSecureToken()
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "common.token_generation_failed")
+	}
+	cancelToken, err := generateSecureToken()
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "common.token_generation_failed")
+	}
+
+	// Only hashes are stored; the links carry the tokens themselves
+	request := &EmailChangeRequest{
+		UserID:      u.ID,
+		OldEmail:    u.Email,
+		NewEmail:    newEmail,
+		Token:       utils.HashToken(token),
+		CancelToken: utils.HashToken(cancelToken),
+		ExpiresAt:   utils.Now().Add(time.Duration(s.expiration) * time.Hour),
+	}
+
+	if err := s.repo.Create(ctx, request); err != nil {
+		s.logger.Error("Failed to save email change request", zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "email_change.create_failed")
+	}
+
+	go func() {
+		sendCtx := context.Background()
+		if err := s.sendConfirmationEmail(sendCtx, request, u, token); err != nil {
+			s.logger.Error("Failed to send email change confirmation", zap.Int64("userId", u.ID), zap.Error(err))
+		}
+		if err := s.sendNoticeEmail(sendCtx, request, u, cancelToken); err != nil {
+			s.logger.Error("Failed to send email change notice", zap.Int64("userId", u.ID), zap.Error(err))
+		}
+	}()
+
+	s.logger.Info("Email change requested", zap.Int64("userId", u.ID))
+	return request, nil
+}
+
+func (s *Service) GetPending(ctx context.Context, userID int64) (*EmailChangeRequest, error) {
+	request, err := s.repo.FindPendingByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "email_change.none_pending")
+	}
+	return request, nil
+}
+
+// Confirm applies a pending change. Every session is revoked afterwards
+// because access tokens carry the previous address.
+func (s *Service) Confirm(ctx context.Context, token string) (*user.User, error) {
+	request, err := s.repo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "common.token_invalid")
+	}
+
+	if !request.IsPending() {
+		return nil, errors.New(errors.EINVALID, "email_change.already_resolved")
+	}
+
+	if request.IsExpired() {
+		return nil, errors.New(errors.EINVALID, "email_change.link_expired")
+	}
+
+	u, err := s.userRepo.GetByID(ctx, request.UserID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if u.Email != request.OldEmail {
+		return nil, errors.New(errors.EINVALID, "email_change.email_changed")
+	}
+
+	if err := s.ensureAvailable(ctx, request.NewEmail); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Apply(ctx, request); err != nil {
+		switch {
+		case stderrors.Is(err, ErrAlreadyResolved):
+			return nil, errors.New(errors.EINVALID, "email_change.already_resolved")
+		case stderrors.Is(err, ErrEmailChanged):
+			return nil, errors.New(errors.EINVALID, "email_change.email_changed")
+		}
+		s.logger.Error("Failed to apply email change", zap.Int64("userId", u.ID), zap.Error(err))
+		return nil, errors.New(errors.ECONFLICT, "email_change.apply_failed")
+	}
+
+	u, err = s.userRepo.GetByID(ctx, u.ID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if err := s.sessions.RevokeAllUserRefreshTokens(ctx, u.ID); err != nil {
+		s.logger.Warn("Failed to revoke sessions after email change", zap.Int64("userId", u.ID), zap.Error(err))
+	}
+
+	s.logger.Info("Email changed", zap.Int64("userId", u.ID))
+	return u, nil
+}
+
+// Cancel is used from the link sent to the previous address. An
+// unrequested change suggests a compromised session, so every session of
+// the user is revoked as well.
+func (s *Service) Cancel(ctx context.Context, cancelToken string) error {
+	request, err := s.repo.FindByCancelToken(ctx, cancelToken)
+	if err != nil {
+		return errors.New(errors.ENOTFOUND, "common.token_invalid")
+	}
+
+	if !request.IsPending() {
+		return errors.New(errors.EINVALID, "email_change.already_resolved")
+	}
+
+	now := utils.Now()
+	request.CancelledAt = &now
+	if err := s.repo.Save(ctx, request); err != nil {
+		s.logger.Error("Failed to cancel email change", zap.Int64("requestId", request.ID), zap.Error(err))
+		return errors.New(errors.EINTERNAL, "email_change.cancel_failed")
+	}
+
+	if err := s.sessions.RevokeAllUserRefreshTokens(ctx, request.UserID); err != nil {
+		s.logger.Warn("Failed to revoke sessions after email change cancellation", zap.Int64("userId", request.UserID), zap.Error(err))
+	}
+
+	s.logger.Info("Email change cancelled", zap.Int64("userId", request.UserID))
+	return nil
+}
+
+func (s *Service) CancelPending(ctx context.Context, userID int64) error {
+	if _, err := s.repo.FindPendingByUserID(ctx, userID); err != nil {
+		return errors.New(errors.ENOTFOUND, "email_change.none_pending")
+	}
+
+	if err := s.repo.CancelPendingByUserID(ctx, userID); err != nil {
+		return errors.New(errors.EINTERNAL, "email_change.cancel_failed")
+	}
+	return nil
+}
+
+func (s *Service) ensureAvailable(ctx context.Context, emailAddr string) error {
+	if existing, err := s.userRepo.GetByEmail(ctx, emailAddr); err == nil && existing != nil {
+		return errors.New(errors.ECONFLICT, "email_change.email_in_use")
+	}
+	if deleted, err := s.userRepo.GetDeletedByEmail(ctx, emailAddr); err == nil && deleted != nil {
+		return errors.New(errors.ECONFLICT, "email_change.email_in_use")
+	}
+	return nil
+}
+
+func (s *Service) sendConfirmationEmail(ctx context.Context, request *EmailChangeRequest, u *user.User, token string) error {
+	confirmURL := fmt.Sprintf("%s/confirm-email-change?token=%s", s.frontendURL, token)
+
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "email_change_confirm", map[string]any{
+		"URL":             confirmURL,
+		"ExpirationHours": s.expiration,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, request.NewEmail, subject, body)
+}
+
+func (s *Service) sendNoticeEmail(ctx context.Context, request *EmailChangeRequest, u *user.User, cancelToken string) error {
+	cancelURL := fmt.Sprintf("%s/cancel-email-change?token=%s", s.frontendURL, cancelToken)
+
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "email_change_notice", map[string]any{
+		"NewEmail": request.NewEmail,
+		"URL":      cancelURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, request.OldEmail, subject, body)
+}
+
+func generateSecureToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(bytes), nil
+}
@@ -0,0 +1,32 @@
+package emailchange
+
+import (
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+type EmailChangeRequest struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement"`
+	UserID      int64      `gorm:"not null;index"`
+	OldEmail    string     `gorm:"not null;size:255"`
+	NewEmail    string     `gorm:"not null;size:255"`
+	Token       string     `gorm:"uniqueIndex;not null;size:255"`
+	CancelToken string     `gorm:"uniqueIndex;not null;size:255"`
+	ExpiresAt   time.Time  `gorm:"not null"`
+	ConfirmedAt *time.Time `gorm:"column:confirmed_at"`
+	CancelledAt *time.Time `gorm:"column:cancelled_at"`
+	CreatedAt   time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+func (EmailChangeRequest) TableName() string {
+	return "email_change_requests"
+}
+
+func (r *EmailChangeRequest) IsExpired() bool {
+	return utils.Now().Unix() > r.ExpiresAt.UTC().Unix()
+}
+
+func (r *EmailChangeRequest) IsPending() bool {
+	return r.ConfirmedAt == nil && r.CancelledAt == nil
+}
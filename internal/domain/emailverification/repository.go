@@ -16,6 +16,8 @@ type Repository interface {
 
 	MarkAllAsUsedByUserID(ctx context.Context, userID int64) error
 
+	FindByUserID(ctx context.Context, userID int64) ([]EmailVerificationToken, error)
+
 	Save(ctx context.Context, token *EmailVerificationToken) error
 
 	DeleteExpired(ctx context.Context) error
@@ -64,6 +66,17 @@ func (r *GormRepository) MarkAllAsUsedByUserID(ctx context.Context, userID int64
 		}).Error
 }
 
+func (r *GormRepository) FindByUserID(ctx context.Context, userID int64) ([]EmailVerificationToken, error) {
+	var tokens []EmailVerificationToken
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
 func (r *GormRepository) Save(ctx context.Context, token *EmailVerificationToken) error {
 	return r.db.WithContext(ctx).Save(token).Error
 }
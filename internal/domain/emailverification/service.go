@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"fmt"
 
 	"time"
 
@@ -56,7 +55,7 @@ func (s *Service) CreateAndSendVerificationToken(ctx context.Context, u *user.Us
 
 	tokenCode, err := generateSecureToken()
 	if err != nil {
-		return nil, errors.Errorf(errors.EINTERNAL, "failed to generate token")
+		return nil, errors.New(errors.EINTERNAL, "common.token_generation_failed")
 	}
 
 	token := &EmailVerificationToken{
@@ -69,12 +68,12 @@ func (s *Service) CreateAndSendVerificationToken(ctx context.Context, u *user.Us
 
 	if err := s.tokenRepo.Create(ctx, token); err != nil {
 		s.logger.Error("Failed to save verification token", zap.Error(err))
-		return nil, errors.Errorf(errors.EINTERNAL, "failed to create verification token")
+		return nil, errors.New(errors.EINTERNAL, "email_verification.token_create_failed")
 	}
 
 	go func() {
 		sendCtx := context.Background()
-		if err := s.sendVerificationEmail(sendCtx, u.Email, tokenCode); err != nil {
+		if err := s.sendVerificationEmail(sendCtx, u, tokenCode); err != nil {
 			s.logger.Error("Failed to send verification email",
 				zap.Int64("userId", u.ID),
 				zap.Error(err),
@@ -92,50 +91,50 @@ func (s *Service) VerifyToken(ctx context.Context, tokenCode string) VerifyEmail
 	token, err := s.tokenRepo.FindByTokenIncludingUsed(ctx, tokenCode)
 	if err != nil {
 		s.logger.Warn("Token not found", zap.String("token", truncateToken(tokenCode)))
-		return NewFailureResult("Token inválido ou não encontrado")
+		return NewFailureResult("common.token_invalid")
 	}
 
 	if token.Used {
 		s.logger.Warn("Token already used", zap.Int64("tokenId", token.ID))
 
 		if token.VerifiedAt != nil && token.VerifiedAt.Before(utils.Now().Add(-1*time.Hour)) {
-			return NewFailureResult("Token expirado. Solicite um novo código")
+			return NewFailureResult("email_verification.token_expired")
 		}
 
 		u, errUser := s.userRepo.GetByID(ctx, token.UserID)
 		if errUser == nil && u.Metadata.EmailVerified {
-			return NewSuccessResult(token.UserID, token.Email, "Email já foi verificado anteriormente")
+			return NewSuccessResult(token.UserID, token.Email, "email_verification.previously_verified")
 		}
 
-		return NewFailureResult("Token já foi utilizado")
+		return NewFailureResult("email_verification.token_used")
 	}
 
 	if token.IsExpired() {
 		s.logger.Warn("Token expired", zap.Int64("tokenId", token.ID))
-		return NewFailureResult("Token expirado. Solicite um novo código")
+		return NewFailureResult("email_verification.token_expired")
 	}
 
 	token.MarkAsUsed()
 	if err := s.tokenRepo.Save(ctx, token); err != nil {
 		s.logger.Error("Failed to mark token as used", zap.Error(err))
-		return NewFailureResult("Erro interno ao verificar email")
+		return NewFailureResult("email_verification.internal_error")
 	}
 
 	u, errGet := s.userRepo.GetByID(ctx, token.UserID)
 	if errGet != nil {
 		s.logger.Error("Failed to find user", zap.Error(errGet))
-		return NewFailureResult("Usuário não encontrado")
+		return NewFailureResult("user.not_found")
 	}
 
 	u.Active = true
 	u.Metadata.EmailVerified = true
 	if err := s.userRepo.Update(ctx, u); err != nil {
 		s.logger.Error("Failed to update user verification status", zap.Error(err))
-		return NewFailureResult("Erro ao atualizar status de verificação")
+		return NewFailureResult("email_verification.status_update_failed")
 	}
 
 	s.logger.Info("Email verified successfully", zap.Int64("userId", token.UserID))
-	return NewSuccessResult(token.UserID, token.Email, "Email verificado com sucesso!")
+	return NewSuccessResult(token.UserID, token.Email, "email_verification.verified")
 }
 
 func (s *Service) ResendVerification(ctx context.Context, emailAddr string) error {
@@ -143,11 +142,11 @@ func (s *Service) ResendVerification(ctx context.Context, emailAddr string) erro
 
 	u, err := s.userRepo.GetByEmail(ctx, emailAddr)
 	if err != nil {
-		return errors.Errorf(errors.ENOTFOUND, "Usuário não encontrado")
+		return errors.New(errors.ENOTFOUND, "user.not_found")
 	}
 
 	if u.Metadata.EmailVerified {
-		return errors.Errorf(errors.EINVALID, "Email já verificado")
+		return errors.New(errors.EINVALID, "email_verification.already_verified")
 	}
 
 	_, err = s.CreateAndSendVerificationToken(ctx, u)
@@ -159,24 +158,18 @@ func (s *Service) ResendVerification(ctx context.Context, emailAddr string) erro
 	return nil
 }
 
-func (s *Service) sendVerificationEmail(ctx context.Context, toEmail, tokenCode string) error {
+func (s *Service) sendVerificationEmail(ctx context.Context, u *user.User, tokenCode string) error {
 	verificationURL := s.frontendURL + "/v1/email-verification/verify?token=" + tokenCode
 
-	subject := "Verificação de Email"
-	body := fmt.Sprintf(`
-		<html>
-		<body>
-			<h1>Verificação de Email</h1>
-			<p>Clique no link abaixo para verificar seu email:</p>
-			<a href="%s">Verificar Email</a>
-			<p>Ou copie e cole este link no seu navegador:</p>
-			<p>%s</p>
-			<p>Este link expira em %d horas.</p>
-		</body>
-		</html>
-	`, verificationURL, verificationURL, s.expiration)
-
-	return s.emailSender.SendEmail(ctx, toEmail, subject, body)
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "verification", map[string]any{
+		"URL":             verificationURL,
+		"ExpirationHours": s.expiration,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, u.Email, subject, body)
 }
 
 func generateSecureToken() (string, error) {
@@ -3,6 +3,7 @@ package emailverification
 import (
 	"time"
 
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
 	"github.com/lkgiovani/go-boilerplate/pkg/utils"
 )
 
@@ -32,24 +33,27 @@ func (e *EmailVerificationToken) MarkAsUsed() {
 }
 
 type VerifyEmailResult struct {
-	Success bool   `json:"success"`
-	UserID  int64  `json:"userId,omitempty"`
-	Email   string `json:"email,omitempty"`
-	Message string `json:"message"`
+	Success    bool   `json:"success"`
+	UserID     int64  `json:"userId,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Message    string `json:"message"`
+	MessageKey string `json:"-"`
 }
 
-func NewSuccessResult(userID int64, email, message string) VerifyEmailResult {
+func NewSuccessResult(userID int64, email, messageKey string) VerifyEmailResult {
 	return VerifyEmailResult{
-		Success: true,
-		UserID:  userID,
-		Email:   email,
-		Message: message,
+		Success:    true,
+		UserID:     userID,
+		Email:      email,
+		Message:    i18n.T(i18n.DefaultLocale, messageKey),
+		MessageKey: messageKey,
 	}
 }
 
-func NewFailureResult(message string) VerifyEmailResult {
+func NewFailureResult(messageKey string) VerifyEmailResult {
 	return VerifyEmailResult{
-		Success: false,
-		Message: message,
+		Success:    false,
+		Message:    i18n.T(i18n.DefaultLocale, messageKey),
+		MessageKey: messageKey,
 	}
 }
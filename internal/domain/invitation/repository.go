@@ -0,0 +1,72 @@
+package invitation
+
+import (
+	"context"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, invitation *UserInvitation) error
+
+	FindByToken(ctx context.Context, token string) (*UserInvitation, error)
+
+	FindPendingByUserID(ctx context.Context, userID int64) (*UserInvitation, error)
+
+	RevokeAllByUserID(ctx context.Context, userID int64) error
+
+	Save(ctx context.Context, invitation *UserInvitation) error
+
+	DeleteExpired(ctx context.Context) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, invitation *UserInvitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+func (r *GormRepository) FindByToken(ctx context.Context, token string) (*UserInvitation, error) {
+	var i UserInvitation
+	if err := r.db.WithContext(ctx).
+		Where("token = ? AND used = false AND revoked_at IS NULL", token).
+		First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *GormRepository) FindPendingByUserID(ctx context.Context, userID int64) (*UserInvitation, error) {
+	var i UserInvitation
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used = false AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *GormRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).
+		Model(&UserInvitation{}).
+		Where("user_id = ? AND used = false AND revoked_at IS NULL", userID).
+		Update("revoked_at", utils.Now()).Error
+}
+
+func (r *GormRepository) Save(ctx context.Context, invitation *UserInvitation) error {
+	return r.db.WithContext(ctx).Save(invitation).Error
+}
+
+func (r *GormRepository) DeleteExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at < ? AND (used = true OR revoked_at IS NOT NULL)", utils.Now()).
+		Delete(&UserInvitation{}).Error
+}
@@ -0,0 +1,208 @@
+package invitation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/encrypt"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	invitationRepo Repository
+	userRepo       user.UserService
+	emailSender    email.EmailSender
+	frontendURL    string
+	expiration     int
+	logger         logger.Logger
+}
+
+func NewService(
+	invitationRepo Repository,
+	userRepo user.UserService,
+	emailSender email.EmailSender,
+	frontendURL string,
+	expiration int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		emailSender:    emailSender,
+		frontendURL:    frontendURL,
+		expiration:     expiration,
+		logger:         logger,
+	}
+}
+
+func (s *Service) CreateAndSendInvitation(ctx context.Context, u *user.User, invitedBy *int64) (*UserInvitation, error) {
+	s.logger.Debug("Creating invitation for user", zap.Int64("userId", u.ID))
+
+	if err := s.invitationRepo.RevokeAllByUserID(ctx, u.ID); err != nil {
+		s.logger.Warn("Failed to revoke existing invitations", zap.Error(err))
+	}
+
+	tokenCode, err := generateSecureToken()
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "common.token_generation_failed")
+	}
+
+	invitation := &UserInvitation{
+		UserID:    u.ID,
+		Email:     u.Email,
+		Token:     tokenCode,
+		InvitedBy: invitedBy,
+		ExpiresAt: utils.Now().Add(time.Duration(s.expiration) * time.Hour),
+		Used:      false,
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		s.logger.Error("Failed to save invitation", zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "invitation.create_failed")
+	}
+
+	go func() {
+		sendCtx := context.Background()
+		if err := s.sendInvitationEmail(sendCtx, u, tokenCode); err != nil {
+			s.logger.Error("Failed to send invitation email",
+				zap.Int64("userId", u.ID),
+				zap.Error(err),
+			)
+		}
+	}()
+
+	s.logger.Info("Invitation created and email queued", zap.Int64("userId", u.ID))
+	return invitation, nil
+}
+
+func (s *Service) ResendInvitation(ctx context.Context, userID int64, invitedBy *int64) (*UserInvitation, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if err := user.CheckVersion(ctx, u); err != nil {
+		return nil, err
+	}
+
+	if !u.Metadata.MustSetPassword {
+		return nil, errors.New(errors.EINVALID, "invitation.password_already_set")
+	}
+
+	return s.CreateAndSendInvitation(ctx, u, invitedBy)
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, userID int64) error {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+	if err := user.CheckVersion(ctx, u); err != nil {
+		return err
+	}
+
+	if _, err := s.invitationRepo.FindPendingByUserID(ctx, userID); err != nil {
+		return errors.New(errors.ENOTFOUND, "invitation.none_pending")
+	}
+
+	if err := s.invitationRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		s.logger.Error("Failed to revoke invitations", zap.Int64("userId", userID), zap.Error(err))
+		return errors.New(errors.EINTERNAL, "invitation.revoke_failed")
+	}
+
+	s.logger.Info("Invitation revoked", zap.Int64("userId", userID))
+	return nil
+}
+
+func (s *Service) VerifyToken(ctx context.Context, tokenCode string) (*UserInvitation, error) {
+	s.logger.Debug("Verifying invitation token")
+
+	invitation, err := s.invitationRepo.FindByToken(ctx, tokenCode)
+	if err != nil {
+		s.logger.Warn("Invitation not found, used or revoked", zap.String("token", truncateToken(tokenCode)))
+		return nil, errors.New(errors.ENOTFOUND, "invitation.invalid")
+	}
+
+	if invitation.IsExpired() {
+		s.logger.Warn("Invitation expired", zap.Int64("invitationId", invitation.ID))
+		return nil, errors.New(errors.EINVALID, "invitation.expired")
+	}
+
+	return invitation, nil
+}
+
+func (s *Service) AcceptInvitation(ctx context.Context, tokenCode, password string) (*user.User, error) {
+	invitation, err := s.VerifyToken(ctx, tokenCode)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, invitation.UserID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if !u.Metadata.MustSetPassword {
+		return nil, errors.New(errors.EINVALID, "invitation.password_already_set")
+	}
+
+	hashedPassword, err := encrypt.HashPassword(password)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "password.hash_failed")
+	}
+
+	u.Password = &hashedPassword
+	u.Metadata.MustSetPassword = false
+	u.Metadata.EmailVerified = true
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, errors.New(errors.EINTERNAL, "invitation.set_password_failed")
+	}
+
+	invitation.MarkAsUsed()
+	if err := s.invitationRepo.Save(ctx, invitation); err != nil {
+		s.logger.Error("Failed to mark invitation as used", zap.Error(err))
+	}
+
+	_ = s.invitationRepo.RevokeAllByUserID(ctx, u.ID)
+
+	s.logger.Info("Invitation accepted", zap.Int64("userId", u.ID))
+	return u, nil
+}
+
+func (s *Service) sendInvitationEmail(ctx context.Context, u *user.User, tokenCode string) error {
+	acceptLink := fmt.Sprintf("%s/accept-invitation?token=%s", s.frontendURL, tokenCode)
+
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "invitation", map[string]any{
+		"Name":            u.Name,
+		"URL":             acceptLink,
+		"ExpirationHours": s.expiration,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, u.Email, subject, body)
+}
+
+func generateSecureToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(bytes), nil
+}
+
+func truncateToken(token string) string {
+	if len(token) <= 8 {
+		return "***"
+	}
+	return token[:8] + "..."
+}
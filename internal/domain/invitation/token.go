@@ -0,0 +1,34 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+type UserInvitation struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `gorm:"not null;index"`
+	Email     string     `gorm:"not null;size:255"`
+	Token     string     `gorm:"uniqueIndex;not null;size:255"`
+	InvitedBy *int64     `gorm:"column:invited_by"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	Used      bool       `gorm:"not null;default:false"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+func (UserInvitation) TableName() string {
+	return "user_invitations"
+}
+
+func (i *UserInvitation) IsExpired() bool {
+	return utils.Now().Unix() > i.ExpiresAt.UTC().Unix()
+}
+
+func (i *UserInvitation) MarkAsUsed() {
+	now := utils.Now()
+	i.Used = true
+	i.UsedAt = &now
+}
@@ -0,0 +1,31 @@
+package loginhistory
+
+import "time"
+
+type Method string
+
+const (
+	MethodPassword Method = "PASSWORD"
+	MethodGoogle   Method = "GOOGLE"
+	MethodRefresh  Method = "REFRESH"
+)
+
+// LoginEvent records a sign-in attempt, successful or not. UserID is nil
+// when the submitted email does not belong to any user. FailureReason holds
+// the message key of the error returned to the client.
+type LoginEvent struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	UserID        *int64    `gorm:"index"`
+	Email         string    `gorm:"size:255"`
+	Method        Method    `gorm:"size:20;not null"`
+	Success       bool      `gorm:"not null"`
+	FailureReason *string   `gorm:"column:failure_reason;size:100"`
+	IPAddress     string    `gorm:"column:ip_address;size:45"`
+	UserAgent     string    `gorm:"column:user_agent;size:500"`
+	DeviceID      string    `gorm:"column:device_id;size:255"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
@@ -0,0 +1,49 @@
+package loginhistory
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, event *LoginEvent) error
+	FindByUserID(ctx context.Context, userID int64, page, size int) ([]LoginEvent, int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, event *LoginEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *GormRepository) FindByUserID(ctx context.Context, userID int64, page, size int) ([]LoginEvent, int64, error) {
+	var events []LoginEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&LoginEvent{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *GormRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&LoginEvent{})
+	return result.RowsAffected, result.Error
+}
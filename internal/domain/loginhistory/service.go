@@ -0,0 +1,95 @@
+package loginhistory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Attempt is a sign-in attempt as seen by the auth flows. UserID is nil
+// when the email is unknown.
+type Attempt struct {
+	UserID    *int64
+	Email     string
+	Method    Method
+	IPAddress string
+	UserAgent string
+	DeviceID  string
+}
+
+type Service struct {
+	repo      Repository
+	userRepo  user.UserService
+	retention time.Duration
+	logger    logger.Logger
+}
+
+func NewService(repo Repository, userRepo user.UserService, retentionDays int, logger logger.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		userRepo:  userRepo,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+		logger:    logger,
+	}
+}
+
+// Record stores the attempt, failed when err is not nil, and moves the last
+// access of the user on success. Errors are only logged: the history never
+// fails a sign-in.
+func (s *Service) Record(ctx context.Context, attempt Attempt, err error) {
+	now := utils.Now()
+	event := &LoginEvent{
+		UserID:    attempt.UserID,
+		Email:     attempt.Email,
+		Method:    attempt.Method,
+		Success:   err == nil,
+		IPAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		DeviceID:  attempt.DeviceID,
+		CreatedAt: now,
+	}
+	if err != nil {
+		reason := errors.ErrorKey(err)
+		event.FailureReason = &reason
+	}
+
+	if err := s.repo.Create(ctx, event); err != nil {
+		s.logger.Error("Failed to record login event", zap.Int64p("userId", attempt.UserID), zap.Error(err))
+	}
+
+	if event.Success && attempt.UserID != nil {
+		if err := s.userRepo.UpdateLastAccess(ctx, *attempt.UserID, now); err != nil {
+			s.logger.Error("Failed to update last access", zap.Int64p("userId", attempt.UserID), zap.Error(err))
+		}
+	}
+}
+
+func (s *Service) FindByUserID(ctx context.Context, userID int64, page, size int) ([]LoginEvent, int64, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, 0, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	events, total, err := s.repo.FindByUserID(ctx, userID, page, size)
+	if err != nil {
+		return nil, 0, errors.New(errors.EINTERNAL, "login_history.find_failed")
+	}
+	return events, total, nil
+}
+
+// CleanupExpired removes the events older than the retention period.
+func (s *Service) CleanupExpired(ctx context.Context) (int64, error) {
+	removed, err := s.repo.DeleteBefore(ctx, utils.Now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if removed > 0 {
+		s.logger.Info("Expired login events removed", zap.Int64("count", removed))
+	}
+	return removed, nil
+}
@@ -0,0 +1,83 @@
+package loginhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+)
+
+type eventRepo struct {
+	Repository
+	events []*LoginEvent
+}
+
+func (r *eventRepo) Create(_ context.Context, event *LoginEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+type lastAccessRepo struct {
+	user.UserService
+	updated []int64
+}
+
+func (r *lastAccessRepo) UpdateLastAccess(_ context.Context, id int64, _ time.Time) error {
+	r.updated = append(r.updated, id)
+	return nil
+}
+
+func TestRecord(t *testing.T) {
+	userID := int64(7)
+	failed := errors.New(errors.EUNAUTHORIZED, "auth.invalid_credentials")
+
+	tests := []struct {
+		name           string
+		attempt        Attempt
+		err            error
+		wantSuccess    bool
+		wantReason     string
+		wantLastAccess bool
+	}{
+		{"success", Attempt{UserID: &userID, Email: "user@example.com", Method: MethodPassword}, nil, true, "", true},
+		{"failure of a known user", Attempt{UserID: &userID, Email: "user@example.com", Method: MethodPassword}, failed, false, "auth.invalid_credentials", false},
+		{"unknown email", Attempt{Email: "nobody@example.com", Method: MethodPassword}, failed, false, "auth.invalid_credentials", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			repo := &eventRepo{}
+			users := &lastAccessRepo{}
+			s := NewService(repo, users, 90, log)
+
+			s.Record(context.Background(), tt.attempt, tt.err)
+
+			if len(repo.events) != 1 {
+				t.Fatalf("events = %d, want 1", len(repo.events))
+			}
+			event := repo.events[0]
+			if event.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", event.Success, tt.wantSuccess)
+			}
+			if event.Email != tt.attempt.Email {
+				t.Errorf("Email = %q, want %q", event.Email, tt.attempt.Email)
+			}
+			if (event.UserID == nil) != (tt.attempt.UserID == nil) {
+				t.Errorf("UserID = %v, want %v", event.UserID, tt.attempt.UserID)
+			}
+			if tt.wantReason == "" && event.FailureReason != nil {
+				t.Errorf("FailureReason = %q, want none", *event.FailureReason)
+			}
+			if tt.wantReason != "" && (event.FailureReason == nil || *event.FailureReason != tt.wantReason) {
+				t.Errorf("FailureReason = %v, want %s", event.FailureReason, tt.wantReason)
+			}
+			if got := len(users.updated) == 1; got != tt.wantLastAccess {
+				t.Errorf("last access updated = %v, want %v", got, tt.wantLastAccess)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package metering
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	// Increment adds delta to the counter and returns the new total.
+	Increment(ctx context.Context, userID int64, periodStart time.Time, metric Metric, delta int64) (int64, error)
+	Count(ctx context.Context, userID int64, periodStart time.Time, metric Metric) (int64, error)
+	FindReport(ctx context.Context, periodStart time.Time, page, size int) ([]ReportRow, int64, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Increment(ctx context.Context, userID int64, periodStart time.Time, metric Metric, delta int64) (int64, error) {
+	counter := UsageCounter{
+		UserID:      userID,
+		PeriodStart: periodStart,
+		Metric:      metric,
+		Count:       delta,
+		UpdatedAt:   utils.Now(),
+	}
+
+	err := r.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "period_start"}, {Name: "metric"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":      gorm.Expr("usage_counters.count + EXCLUDED.count"),
+				"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+			}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "count"}}},
+	).Create(&counter).Error
+	return counter.Count, err
+}
+
+func (r *GormRepository) Count(ctx context.Context, userID int64, periodStart time.Time, metric Metric) (int64, error) {
+	var counter UsageCounter
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND period_start = ? AND metric = ?", userID, periodStart, metric).
+		First(&counter).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	return counter.Count, err
+}
+
+// FindReport lists the users with usage in the period, heaviest first.
+func (r *GormRepository) FindReport(ctx context.Context, periodStart time.Time, page, size int) ([]ReportRow, int64, error) {
+	base := r.db.WithContext(ctx).
+		Table("usage_counters c").
+		Joins("JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL").
+		Where("c.period_start = ?", periodStart)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Distinct("c.user_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []ReportRow
+	err := base.Session(&gorm.Session{}).
+		Select(`c.user_id, u.name, u.email, u.metadata->>'plan_type' AS plan_type,
+			COALESCE(SUM(c.count) FILTER (WHERE c.metric = ?), 0) AS requests,
+			COALESCE(SUM(c.count) FILTER (WHERE c.metric = ?), 0) AS resources`, MetricRequests, MetricResources).
+		Group("c.user_id, u.id").
+		Order("requests DESC, c.user_id ASC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&rows).Error
+	return rows, total, err
+}
@@ -0,0 +1,240 @@
+package metering
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type counterKey struct {
+	userID int64
+	period time.Time
+	metric Metric
+}
+
+type cachedLimits struct {
+	requests  *int
+	resources *int
+	expiresAt time.Time
+}
+
+// Service counts usage in memory and flushes the deltas to Postgres
+// periodically, so metering does not add a write to every request. Totals
+// are refreshed from the database on each flush, which keeps instances
+// roughly in sync; quotas may overshoot by what other instances have not
+// flushed yet.
+type Service struct {
+	repo     Repository
+	userRepo user.UserService
+	limitTTL time.Duration
+	logger   logger.Logger
+
+	mu      sync.Mutex
+	pending map[counterKey]int64
+	totals  map[counterKey]int64
+	limits  map[int64]cachedLimits
+}
+
+func NewService(repo Repository, userRepo user.UserService, limitTTL time.Duration, logger logger.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		limitTTL: limitTTL,
+		logger:   logger,
+		pending:  make(map[counterKey]int64),
+		totals:   make(map[counterKey]int64),
+		limits:   make(map[int64]cachedLimits),
+	}
+}
+
+// Record adds n to the user's usage of the metric in the current period.
+func (s *Service) Record(userID int64, metric Metric, n int64) {
+	key := counterKey{userID: userID, period: PeriodStart(utils.Now()), metric: metric}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[key] += n
+	if total, ok := s.totals[key]; ok {
+		s.totals[key] = total + n
+	}
+}
+
+// Check returns the user's quota of the metric without recording usage.
+func (s *Service) Check(ctx context.Context, userID int64, metric Metric) Quota {
+	period := PeriodStart(utils.Now())
+	quota := Quota{Metric: metric, ResetAt: PeriodEnd(period)}
+
+	used, err := s.used(ctx, counterKey{userID: userID, period: period, metric: metric})
+	if err != nil {
+		// Metering must not take the API down: the quota is not enforced
+		// until the counters can be read again.
+		s.logger.Warn("Failed to load usage", zap.Int64("userId", userID), zap.String("metric", string(metric)), zap.Error(err))
+		return quota
+	}
+	quota.Used = used
+
+	limits, err := s.limitsOf(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load usage limits", zap.Int64("userId", userID), zap.Error(err))
+		return quota
+	}
+	switch metric {
+	case MetricRequests:
+		quota.Limit = limits.requests
+	case MetricResources:
+		quota.Limit = limits.resources
+	}
+	return quota
+}
+
+// Consume records one unit of the metric unless the quota is exhausted, in
+// which case it returns an ERATELIMIT error.
+func (s *Service) Consume(ctx context.Context, userID int64, metric Metric) (Quota, error) {
+	quota := s.Check(ctx, userID, metric)
+	if quota.Exceeded() {
+		return quota, quotaExceeded(metric)
+	}
+
+	s.Record(userID, metric, 1)
+	quota.Used++
+	return quota, nil
+}
+
+// Allow returns an ERATELIMIT error when the quota of the metric is
+// exhausted, without recording usage.
+func (s *Service) Allow(ctx context.Context, userID int64, metric Metric) error {
+	if s.Check(ctx, userID, metric).Exceeded() {
+		return quotaExceeded(metric)
+	}
+	return nil
+}
+
+// Usage returns the quotas of every metric in the current period.
+func (s *Service) Usage(ctx context.Context, userID int64) []Quota {
+	return []Quota{
+		s.Check(ctx, userID, MetricRequests),
+		s.Check(ctx, userID, MetricResources),
+	}
+}
+
+func (s *Service) Report(ctx context.Context, period time.Time, page, size int) ([]ReportRow, int64, error) {
+	rows, total, err := s.repo.FindReport(ctx, PeriodStart(period), page, size)
+	if err != nil {
+		s.logger.Error("Failed to load usage report", zap.Error(err))
+		return nil, 0, errors.New(errors.EINTERNAL, "usage.report_failed")
+	}
+	return rows, total, nil
+}
+
+// Flush writes the buffered usage to the database. Deltas that fail to be
+// written are kept for the next flush.
+func (s *Service) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[counterKey]int64)
+	s.mu.Unlock()
+
+	var lastErr error
+	for key, delta := range batch {
+		count, err := s.repo.Increment(ctx, key.userID, key.period, key.metric, delta)
+
+		s.mu.Lock()
+		if err != nil {
+			s.pending[key] += delta
+			lastErr = err
+		} else {
+			s.totals[key] = count + s.pending[key]
+		}
+		s.mu.Unlock()
+	}
+
+	s.evict()
+	return lastErr
+}
+
+// evict drops totals of past periods and expired limits.
+func (s *Service) evict() {
+	now := utils.Now()
+	period := PeriodStart(now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.totals {
+		if key.period.Before(period) {
+			delete(s.totals, key)
+		}
+	}
+	for userID, limits := range s.limits {
+		if now.After(limits.expiresAt) {
+			delete(s.limits, userID)
+		}
+	}
+}
+
+func (s *Service) used(ctx context.Context, key counterKey) (int64, error) {
+	s.mu.Lock()
+	total, ok := s.totals[key]
+	s.mu.Unlock()
+	if ok {
+		return total, nil
+	}
+
+	persisted, err := s.repo.Count(ctx, key.userID, key.period, key.metric)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if total, ok := s.totals[key]; ok {
+		return total, nil
+	}
+	total = persisted + s.pending[key]
+	s.totals[key] = total
+	return total, nil
+}
+
+// limitsOf reads the limits from the user metadata, cached for limitTTL so
+// plan changes take effect shortly without a lookup per request.
+func (s *Service) limitsOf(ctx context.Context, userID int64) (cachedLimits, error) {
+	now := utils.Now()
+
+	s.mu.Lock()
+	limits, ok := s.limits[userID]
+	s.mu.Unlock()
+	if ok && now.Before(limits.expiresAt) {
+		return limits, nil
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return cachedLimits{}, err
+	}
+
+	limits = cachedLimits{
+		requests:  u.Metadata.MaxRequestsPerMonth,
+		resources: u.Metadata.MaxResources,
+		expiresAt: now.Add(s.limitTTL),
+	}
+
+	s.mu.Lock()
+	s.limits[userID] = limits
+	s.mu.Unlock()
+	return limits, nil
+}
+
+func quotaExceeded(metric Metric) error {
+	if metric == MetricResources {
+		return errors.New(errors.ERATELIMIT, "usage.resources_exceeded")
+	}
+	return errors.New(errors.ERATELIMIT, "usage.requests_exceeded")
+}
@@ -0,0 +1,201 @@
+package metering
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+// counterRepo keeps the persisted counters in memory and fails writes while
+// err is set.
+type counterRepo struct {
+	Repository
+	counts map[counterKey]int64
+	err    error
+}
+
+func (r *counterRepo) Increment(_ context.Context, userID int64, periodStart time.Time, metric Metric, delta int64) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	key := counterKey{userID: userID, period: periodStart, metric: metric}
+	r.counts[key] += delta
+	return r.counts[key], nil
+}
+
+func (r *counterRepo) Count(_ context.Context, userID int64, periodStart time.Time, metric Metric) (int64, error) {
+	return r.counts[counterKey{userID: userID, period: periodStart, metric: metric}], nil
+}
+
+type limitsUserRepo struct {
+	user.UserService
+	plan user.PlanMetadata
+}
+
+func (r *limitsUserRepo) GetByID(_ context.Context, id int64) (*user.User, error) {
+	return &user.User{ID: id, Metadata: user.UserMetadata{PlanMetadata: r.plan}}, nil
+}
+
+func newTestService(t *testing.T, counts map[counterKey]int64, plan user.PlanMetadata) (*Service, *counterRepo) {
+	t.Helper()
+	log, _ := logger.NewLogger("test", "none")
+	if counts == nil {
+		counts = make(map[counterKey]int64)
+	}
+	repo := &counterRepo{counts: counts}
+	return NewService(repo, &limitsUserRepo{plan: plan}, time.Minute, log), repo
+}
+
+func TestConsume(t *testing.T) {
+	limit := 2
+	period := PeriodStart(utils.Now())
+
+	tests := []struct {
+		name      string
+		metric    Metric
+		plan      user.PlanMetadata
+		persisted int64
+		wantUsed  []int64
+		wantKey   string
+	}{
+		{"within quota", MetricRequests, user.PlanMetadata{MaxRequestsPerMonth: &limit}, 0, []int64{1, 2}, "usage.requests_exceeded"},
+		{"counts persisted usage", MetricRequests, user.PlanMetadata{MaxRequestsPerMonth: &limit}, 1, []int64{2}, "usage.requests_exceeded"},
+		{"resources", MetricResources, user.PlanMetadata{MaxResources: &limit}, 2, nil, "usage.resources_exceeded"},
+		{"unlimited", MetricRequests, user.PlanMetadata{}, 100, []int64{101, 102, 103}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestService(t, map[counterKey]int64{{userID: 1, period: period, metric: tt.metric}: tt.persisted}, tt.plan)
+
+			for _, want := range tt.wantUsed {
+				quota, err := s.Consume(context.Background(), 1, tt.metric)
+				if err != nil {
+					t.Fatalf("Consume() error = %v", err)
+				}
+				if quota.Used != want {
+					t.Errorf("Used = %d, want %d", quota.Used, want)
+				}
+				if !quota.ResetAt.Equal(PeriodEnd(period)) {
+					t.Errorf("ResetAt = %v, want %v", quota.ResetAt, PeriodEnd(period))
+				}
+			}
+			if tt.wantKey == "" {
+				return
+			}
+
+			quota, err := s.Consume(context.Background(), 1, tt.metric)
+			if errors.ErrorCode(err) != errors.ERATELIMIT || errors.ErrorKey(err) != tt.wantKey {
+				t.Fatalf("Consume() error = %v, want %s", err, tt.wantKey)
+			}
+			if quota.Remaining() != 0 {
+				t.Errorf("Remaining() = %d, want 0", quota.Remaining())
+			}
+		})
+	}
+}
+
+func TestFlush(t *testing.T) {
+	period := PeriodStart(utils.Now())
+	key := counterKey{userID: 1, period: period, metric: MetricRequests}
+	s, repo := newTestService(t, map[counterKey]int64{key: 5}, user.PlanMetadata{})
+
+	if _, err := s.Consume(context.Background(), 1, MetricRequests); err != nil {
+		t.Fatal(err)
+	}
+	s.Record(1, MetricRequests, 2)
+
+	// Failed writes are kept for the next flush
+	repo.err = stderrors.New("connection refused")
+	if err := s.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() error = nil, want the repository error")
+	}
+	if repo.counts[key] != 5 {
+		t.Fatalf("persisted = %d, want 5", repo.counts[key])
+	}
+
+	repo.err = nil
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if repo.counts[key] != 8 {
+		t.Errorf("persisted = %d, want 8", repo.counts[key])
+	}
+	if len(s.pending) != 0 {
+		t.Errorf("pending = %v, want empty", s.pending)
+	}
+
+	// Totals are refreshed from the database, including other instances
+	repo.counts[key] += 10
+	s.Record(1, MetricRequests, 1)
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if quota := s.Check(context.Background(), 1, MetricRequests); quota.Used != 19 {
+		t.Errorf("Used = %d, want 19", quota.Used)
+	}
+}
+
+func TestFlushRollsOverPeriods(t *testing.T) {
+	current := PeriodStart(utils.Now())
+	previous := PeriodStart(current.Add(-time.Hour))
+	limit := 10
+
+	oldKey := counterKey{userID: 1, period: previous, metric: MetricRequests}
+	newKey := counterKey{userID: 1, period: current, metric: MetricRequests}
+	s, repo := newTestService(t, map[counterKey]int64{oldKey: 10}, user.PlanMetadata{MaxRequestsPerMonth: &limit})
+
+	// Usage recorded before the rollover still lands in its own period
+	s.totals[oldKey] = 12
+	s.pending[oldKey] = 2
+
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if repo.counts[oldKey] != 12 {
+		t.Errorf("previous period = %d, want 12", repo.counts[oldKey])
+	}
+	if _, ok := s.totals[oldKey]; ok {
+		t.Error("total of the previous period was not evicted")
+	}
+
+	quota, err := s.Consume(context.Background(), 1, MetricRequests)
+	if err != nil {
+		t.Fatalf("Consume() error = %v, want a fresh quota", err)
+	}
+	if quota.Used != 1 || quota.Remaining() != 9 {
+		t.Errorf("Used = %d, Remaining = %d, want 1 and 9", quota.Used, quota.Remaining())
+	}
+	if s.pending[newKey] != 1 {
+		t.Errorf("pending = %d, want 1", s.pending[newKey])
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		in        time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 5, 1, 1, 0, 0, 0, time.FixedZone("BRT", -3*60*60)), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 5, 1, 1, 0, 0, 0, time.FixedZone("CET", 2*60*60)), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		start := PeriodStart(tt.in)
+		if !start.Equal(tt.wantStart) {
+			t.Errorf("PeriodStart(%v) = %v, want %v", tt.in, start, tt.wantStart)
+		}
+		if end := PeriodEnd(start); !end.Equal(tt.wantEnd) {
+			t.Errorf("PeriodEnd(%v) = %v, want %v", start, end, tt.wantEnd)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package metering
+
+import "time"
+
+type Metric string
+
+const (
+	MetricRequests  Metric = "REQUESTS"
+	MetricResources Metric = "RESOURCES"
+)
+
+// UsageCounter is the usage of a metric by a user in a billing period.
+type UsageCounter struct {
+	UserID      int64     `gorm:"primaryKey"`
+	PeriodStart time.Time `gorm:"primaryKey;type:date"`
+	Metric      Metric    `gorm:"primaryKey;size:20"`
+	Count       int64     `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (UsageCounter) TableName() string {
+	return "usage_counters"
+}
+
+// Quota is the usage of a metric against the user's limit in the current
+// period. A nil Limit means unlimited.
+type Quota struct {
+	Metric  Metric
+	Used    int64
+	Limit   *int
+	ResetAt time.Time
+}
+
+func (q Quota) Exceeded() bool {
+	return q.Limit != nil && q.Used >= int64(*q.Limit)
+}
+
+func (q Quota) Remaining() int64 {
+	if q.Limit == nil {
+		return -1
+	}
+	return max(int64(*q.Limit)-q.Used, 0)
+}
+
+// ReportRow is the usage of a user in the admin usage report.
+type ReportRow struct {
+	UserID    int64
+	Name      string
+	Email     string
+	PlanType  string
+	Requests  int64
+	Resources int64
+}
+
+// PeriodStart returns the billing period containing t. Usage is metered per
+// calendar month, in UTC.
+func PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func PeriodEnd(start time.Time) time.Time {
+	return start.AddDate(0, 1, 0)
+}
@@ -0,0 +1,88 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+type Organization struct {
+	ID        int64             `gorm:"primaryKey;autoIncrement"`
+	Name      string            `gorm:"not null;size:255"`
+	Slug      string            `gorm:"uniqueIndex;not null;size:255"`
+	Personal  bool              `gorm:"not null;default:false"`
+	OwnerID   int64             `gorm:"not null;index"`
+	Plan      user.PlanMetadata `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time         `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time         `gorm:"not null;autoUpdateTime"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+type Member struct {
+	OrganizationID int64         `gorm:"primaryKey"`
+	UserID         int64         `gorm:"primaryKey"`
+	Role           string        `gorm:"not null;size:20;default:member"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationID"`
+	User           *user.User    `gorm:"foreignKey:UserID"`
+	CreatedAt      time.Time     `gorm:"not null;autoCreateTime"`
+}
+
+func (Member) TableName() string {
+	return "organization_members"
+}
+
+func (Member) TenantScoped() {}
+
+func (m *Member) CanManage() bool {
+	return m.Role == RoleOwner || m.Role == RoleAdmin
+}
+
+type Invitation struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement"`
+	OrganizationID int64      `gorm:"not null;index"`
+	Email          string     `gorm:"not null;size:255"`
+	Role           string     `gorm:"not null;size:20;default:member"`
+	Token          string     `gorm:"uniqueIndex;not null;size:255"`
+	InvitedBy      *int64     `gorm:"column:invited_by"`
+	ExpiresAt      time.Time  `gorm:"not null"`
+	AcceptedAt     *time.Time `gorm:"column:accepted_at"`
+	RevokedAt      *time.Time `gorm:"column:revoked_at"`
+	CreatedAt      time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+func (Invitation) TableName() string {
+	return "organization_invitations"
+}
+
+func (Invitation) TenantScoped() {}
+
+func (i *Invitation) IsExpired() bool {
+	return utils.Now().Unix() > i.ExpiresAt.UTC().Unix()
+}
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleOwner, RoleAdmin, RoleMember:
+		return true
+	}
+	return false
+}
+
+// EffectivePlan returns the plan that applies inside the organization: personal
+// organizations follow the owner's own plan, shared ones carry their own.
+func EffectivePlan(org *Organization, u *user.User) user.PlanMetadata {
+	if org == nil || org.Personal {
+		return u.Metadata.PlanMetadata
+	}
+	return org.Plan
+}
@@ -0,0 +1,208 @@
+package organization
+
+import (
+	"context"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, org *Organization, owner *Member) error
+
+	FindByID(ctx context.Context, id int64) (*Organization, error)
+
+	FindPersonalByOwnerID(ctx context.Context, ownerID int64) (*Organization, error)
+
+	SlugExists(ctx context.Context, slug string) (bool, error)
+
+	Update(ctx context.Context, org *Organization) error
+
+	FindMember(ctx context.Context, orgID, userID int64) (*Member, error)
+
+	FindMembershipsByUserID(ctx context.Context, userID int64) ([]Member, error)
+
+	FindMembers(ctx context.Context, orgID int64) ([]Member, error)
+
+	CountOwners(ctx context.Context, orgID int64) (int64, error)
+
+	AddMember(ctx context.Context, member *Member) error
+
+	UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error
+
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+
+	CreateInvitation(ctx context.Context, invitation *Invitation) error
+
+	FindInvitationByToken(ctx context.Context, token string) (*Invitation, error)
+
+	FindPendingInvitations(ctx context.Context, orgID int64) ([]Invitation, error)
+
+	RevokeInvitation(ctx context.Context, orgID, invitationID int64) (bool, error)
+
+	RevokePendingInvitationsByEmail(ctx context.Context, orgID int64, email string) error
+
+	AcceptInvitation(ctx context.Context, invitation *Invitation, member *Member) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, org *Organization, owner *Member) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+
+		owner.OrganizationID = org.ID
+		return tx.WithContext(WithOrganizationID(ctx, org.ID)).Create(owner).Error
+	})
+}
+
+func (r *GormRepository) FindByID(ctx context.Context, id int64) (*Organization, error) {
+	var org Organization
+	if err := r.db.WithContext(ctx).First(&org, id).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *GormRepository) FindPersonalByOwnerID(ctx context.Context, ownerID int64) (*Organization, error) {
+	var org Organization
+	if err := r.db.WithContext(ctx).
+		Where("owner_id = ? AND personal = true", ownerID).
+		First(&org).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *GormRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&Organization{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *GormRepository) Update(ctx context.Context, org *Organization) error {
+	return r.db.WithContext(ctx).Save(org).Error
+}
+
+func (r *GormRepository) FindMember(ctx context.Context, orgID, userID int64) (*Member, error) {
+	var m Member
+	if err := r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Preload("Organization").
+		Where("user_id = ?", userID).
+		First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *GormRepository) FindMembershipsByUserID(ctx context.Context, userID int64) ([]Member, error) {
+	var members []Member
+	if err := r.db.WithContext(WithoutTenant(ctx)).
+		Preload("Organization").
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *GormRepository) FindMembers(ctx context.Context, orgID int64) ([]Member, error) {
+	var members []Member
+	if err := r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Preload("User").
+		Order("created_at").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *GormRepository) CountOwners(ctx context.Context, orgID int64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Model(&Member{}).
+		Where("role = ?", RoleOwner).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *GormRepository) AddMember(ctx context.Context, member *Member) error {
+	return r.db.WithContext(WithOrganizationID(ctx, member.OrganizationID)).Create(member).Error
+}
+
+func (r *GormRepository) UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error {
+	return r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Model(&Member{}).
+		Where("user_id = ?", userID).
+		Update("role", role).Error
+}
+
+func (r *GormRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	return r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Where("user_id = ?", userID).
+		Delete(&Member{}).Error
+}
+
+func (r *GormRepository) CreateInvitation(ctx context.Context, invitation *Invitation) error {
+	return r.db.WithContext(WithOrganizationID(ctx, invitation.OrganizationID)).Create(invitation).Error
+}
+
+func (r *GormRepository) FindInvitationByToken(ctx context.Context, token string) (*Invitation, error) {
+	var i Invitation
+	if err := r.db.WithContext(WithoutTenant(ctx)).
+		Where("token = ? AND accepted_at IS NULL AND revoked_at IS NULL", token).
+		First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *GormRepository) FindPendingInvitations(ctx context.Context, orgID int64) ([]Invitation, error) {
+	var invitations []Invitation
+	if err := r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", utils.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *GormRepository) RevokeInvitation(ctx context.Context, orgID, invitationID int64) (bool, error) {
+	result := r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Model(&Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitationID).
+		Update("revoked_at", utils.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *GormRepository) RevokePendingInvitationsByEmail(ctx context.Context, orgID int64, email string) error {
+	return r.db.WithContext(WithOrganizationID(ctx, orgID)).
+		Model(&Invitation{}).
+		Where("LOWER(email) = LOWER(?) AND accepted_at IS NULL AND revoked_at IS NULL", email).
+		Update("revoked_at", utils.Now()).Error
+}
+
+func (r *GormRepository) AcceptInvitation(ctx context.Context, invitation *Invitation, member *Member) error {
+	return r.db.WithContext(WithOrganizationID(ctx, invitation.OrganizationID)).Transaction(func(tx *gorm.DB) error {
+		now := utils.Now()
+		invitation.AcceptedAt = &now
+		if err := tx.Save(invitation).Error; err != nil {
+			return err
+		}
+		return tx.Create(member).Error
+	})
+}
@@ -0,0 +1,107 @@
+package organization
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// txConnPool lets dry-run sessions open transactions without a database.
+type txConnPool struct{}
+
+func (txConnPool) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, nil }
+
+func (txConnPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (txConnPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (txConnPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+
+func (p txConnPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) { return p, nil }
+
+func (txConnPool) Commit() error { return nil }
+
+func (txConnPool) Rollback() error { return nil }
+
+func newRecordingRepository(t *testing.T) (Repository, *[]string) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: txConnPool{}, WithoutReturning: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTenantCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var statements []string
+	record := func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	cb := db.Callback()
+	if err := cb.Query().After("gorm:query").Register("test:record_query", record); err != nil {
+		t.Fatal(err)
+	}
+	if err := cb.Update().After("gorm:update").Register("test:record_update", record); err != nil {
+		t.Fatal(err)
+	}
+	if err := cb.Create().After("gorm:create").Register("test:record_create", record); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGormRepository(db), &statements
+}
+
+func TestRepositoryCrossTenantLookupsIgnoreActiveOrganization(t *testing.T) {
+	repo, statements := newRecordingRepository(t)
+	ctx := WithOrganizationID(context.Background(), 42)
+
+	if _, err := repo.FindMembershipsByUserID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindInvitationByToken(ctx, "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*statements) != 2 {
+		t.Fatalf("statements = %q, want 2", *statements)
+	}
+	for _, stmt := range *statements {
+		if strings.Contains(stmt, TenantColumn) {
+			t.Fatalf("cross-tenant lookup filtered by organization: %s", stmt)
+		}
+	}
+}
+
+func TestRepositoryAcceptInvitationToAnotherOrganization(t *testing.T) {
+	repo, statements := newRecordingRepository(t)
+	ctx := WithOrganizationID(context.Background(), 42)
+
+	invitation := &Invitation{ID: 3, OrganizationID: 7, Email: "user@example.com", Token: "token", ExpiresAt: time.Now().Add(time.Hour)}
+	member := &Member{OrganizationID: 7, UserID: 1, Role: RoleMember}
+	if err := repo.AcceptInvitation(ctx, invitation, member); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*statements) != 2 {
+		t.Fatalf("statements = %q, want 2", *statements)
+	}
+	if stmt := (*statements)[0]; !strings.Contains(stmt, `"organization_id" = 7`) {
+		t.Fatalf("invitation update not scoped to its organization: %s", stmt)
+	}
+	if stmt := (*statements)[1]; !strings.Contains(stmt, "organization_members") || !strings.Contains(stmt, "(7,1,") {
+		t.Fatalf("member not created in the invited organization: %s", stmt)
+	}
+	if invitation.AcceptedAt == nil {
+		t.Fatal("AcceptedAt not set")
+	}
+}
@@ -0,0 +1,549 @@
+package organization
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	repo        Repository
+	userRepo    user.UserService
+	catalog     *plancatalog.Service
+	emailSender email.EmailSender
+	frontendURL string
+	expiration  int
+	logger      logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	catalog *plancatalog.Service,
+	emailSender email.EmailSender,
+	frontendURL string,
+	expiration int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:        repo,
+		userRepo:    userRepo,
+		catalog:     catalog,
+		emailSender: emailSender,
+		frontendURL: frontendURL,
+		expiration:  expiration,
+		logger:      logger,
+	}
+}
+
+func (s *Service) EnsurePersonalOrganization(ctx context.Context, u *user.User) (*Organization, error) {
+	if org, err := s.repo.FindPersonalByOwnerID(ctx, u.ID); err == nil {
+		return org, nil
+	}
+
+	org := &Organization{
+		Name:     u.Name,
+		Slug:     fmt.Sprintf("personal-%d", u.ID),
+		Personal: true,
+		OwnerID:  u.ID,
+		Plan:     u.Metadata.PlanMetadata,
+	}
+
+	if err := s.repo.Create(ctx, org, &Member{UserID: u.ID, Role: RoleOwner}); err != nil {
+		// Another request may have created it concurrently.
+		if existing, findErr := s.repo.FindPersonalByOwnerID(ctx, u.ID); findErr == nil {
+			return existing, nil
+		}
+		s.logger.Error("Failed to create personal organization", zap.Int64("userId", u.ID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "organization.personal_create_failed")
+	}
+
+	s.logger.Info("Personal organization created", zap.Int64("userId", u.ID), zap.Int64("organizationId", org.ID))
+	return org, nil
+}
+
+// ResolveActiveOrganization returns the organization a session should be bound to.
+// When requestedID is zero, or the user no longer belongs to it, the personal
+// organization is used.
+func (s *Service) ResolveActiveOrganization(ctx context.Context, u *user.User, requestedID int64) (*Organization, *Member, error) {
+	if requestedID > 0 {
+		if member, err := s.repo.FindMember(ctx, requestedID, u.ID); err == nil && member.Organization != nil {
+			return member.Organization, member, nil
+		}
+	}
+
+	org, err := s.EnsurePersonalOrganization(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, err := s.repo.FindMember(ctx, org.ID, u.ID)
+	if err != nil {
+		return nil, nil, errors.New(errors.EINTERNAL, "organization.active_failed")
+	}
+
+	return org, member, nil
+}
+
+func (s *Service) GetMembership(ctx context.Context, orgID, userID int64) (*Member, error) {
+	member, err := s.repo.FindMember(ctx, orgID, userID)
+	if err != nil || member.Organization == nil {
+		return nil, errors.New(errors.ENOTFOUND, "organization.not_found")
+	}
+	return member, nil
+}
+
+func (s *Service) ListForUser(ctx context.Context, userID int64) ([]Member, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if _, err := s.EnsurePersonalOrganization(ctx, u); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.FindMembershipsByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "organization.list_failed")
+	}
+
+	for _, m := range members {
+		if m.Organization != nil {
+			m.Organization.Plan = EffectivePlan(m.Organization, u)
+		}
+	}
+	return members, nil
+}
+
+func (s *Service) Get(ctx context.Context, orgID, userID int64) (*Member, error) {
+	member, err := s.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Organization.Personal {
+		u, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil || u == nil {
+			return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+		}
+		member.Organization.Plan = EffectivePlan(member.Organization, u)
+	}
+	return member, nil
+}
+
+func (s *Service) Create(ctx context.Context, ownerID int64, name string) (*Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New(errors.EINVALID, "organization.name_required")
+	}
+
+	slug, err := s.uniqueSlug(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.catalog.Entitlements(user.PlanTypeFree, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &Organization{
+		Name:    name,
+		Slug:    slug,
+		OwnerID: ownerID,
+		Plan:    plan,
+	}
+
+	if err := s.repo.Create(ctx, org, &Member{UserID: ownerID, Role: RoleOwner}); err != nil {
+		s.logger.Error("Failed to create organization", zap.Int64("userId", ownerID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "organization.create_failed")
+	}
+
+	s.logger.Info("Organization created", zap.Int64("organizationId", org.ID), zap.Int64("userId", ownerID))
+	return org, nil
+}
+
+func (s *Service) Rename(ctx context.Context, orgID, actorID int64, name string) (*Organization, error) {
+	member, err := s.requireManager(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New(errors.EINVALID, "organization.name_required")
+	}
+
+	org := member.Organization
+	org.Name = name
+	if err := s.repo.Update(ctx, org); err != nil {
+		return nil, errors.New(errors.EINTERNAL, "organization.update_failed")
+	}
+	return org, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, orgID int64) (*Organization, error) {
+	org, err := s.repo.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "organization.not_found")
+	}
+	return org, nil
+}
+
+func (s *Service) UpdatePlan(ctx context.Context, orgID int64, plan user.PlanMetadata) (*Organization, error) {
+	org, err := s.repo.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "organization.not_found")
+	}
+
+	if org.Personal {
+		return nil, errors.New(errors.EINVALID, "organization.personal_plan")
+	}
+
+	org.Plan = plan
+	if err := s.repo.Update(ctx, org); err != nil {
+		return nil, errors.New(errors.EINTERNAL, "organization.plan_update_failed")
+	}
+
+	s.logger.Info("Organization plan updated", zap.Int64("organizationId", org.ID), zap.String("plan", string(plan.PlanType)))
+	return org, nil
+}
+
+func (s *Service) ListMembers(ctx context.Context, orgID, actorID int64) ([]Member, error) {
+	if _, err := s.GetMembership(ctx, orgID, actorID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.FindMembers(ctx, orgID)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "organization.members_failed")
+	}
+	return members, nil
+}
+
+func (s *Service) UpdateMemberRole(ctx context.Context, orgID, actorID, userID int64, role string) error {
+	if !IsValidRole(role) {
+		return errors.New(errors.EINVALID, "role.invalid", role)
+	}
+
+	actor, err := s.requireManager(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.repo.FindMember(ctx, orgID, userID)
+	if err != nil {
+		return errors.New(errors.ENOTFOUND, "organization.member_not_found")
+	}
+
+	if (role == RoleOwner || target.Role == RoleOwner) && actor.Role != RoleOwner {
+		return errors.New(errors.EFORBIDDEN, "organization.owner_role_change")
+	}
+
+	if target.Role == RoleOwner && role != RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, orgID, userID, role); err != nil {
+		return errors.New(errors.EINTERNAL, "organization.member_update_failed")
+	}
+
+	s.logger.Info("Organization member role updated",
+		zap.Int64("organizationId", orgID),
+		zap.Int64("userId", userID),
+		zap.String("role", role),
+		zap.Int64("updatedBy", actorID),
+	)
+	return nil
+}
+
+func (s *Service) RemoveMember(ctx context.Context, orgID, actorID, userID int64) error {
+	actor, err := s.GetMembership(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.repo.FindMember(ctx, orgID, userID)
+	if err != nil {
+		return errors.New(errors.ENOTFOUND, "organization.member_not_found")
+	}
+
+	if actorID != userID {
+		if !actor.CanManage() {
+			return errors.New(errors.EFORBIDDEN, "auth.insufficient_permissions")
+		}
+		if target.Role == RoleOwner && actor.Role != RoleOwner {
+			return errors.New(errors.EFORBIDDEN, "organization.owner_remove")
+		}
+	}
+
+	if actor.Organization.Personal {
+		return errors.New(errors.EINVALID, "organization.leave_personal")
+	}
+
+	if target.Role == RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.RemoveMember(ctx, orgID, userID); err != nil {
+		return errors.New(errors.EINTERNAL, "organization.member_remove_failed")
+	}
+
+	s.logger.Info("Organization member removed",
+		zap.Int64("organizationId", orgID),
+		zap.Int64("userId", userID),
+		zap.Int64("removedBy", actorID),
+	)
+	return nil
+}
+
+func (s *Service) Invite(ctx context.Context, orgID, actorID int64, emailAddr, role string) (*Invitation, error) {
+	if role == "" {
+		role = RoleMember
+	}
+	if !IsValidRole(role) {
+		return nil, errors.New(errors.EINVALID, "role.invalid", role)
+	}
+
+	actor, err := s.requireManager(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == RoleOwner && actor.Role != RoleOwner {
+		return nil, errors.New(errors.EFORBIDDEN, "organization.owner_invite")
+	}
+
+	org := actor.Organization
+	if org.Personal {
+		return nil, errors.New(errors.EINVALID, "organization.personal_members")
+	}
+
+	emailAddr = strings.TrimSpace(emailAddr)
+	if emailAddr == "" {
+		return nil, errors.New(errors.EINVALID, "validation.email_required")
+	}
+
+	// The email goes out in the invitee's locale when they already have an
+	// account, and in the inviter's otherwise.
+	var locale, timezone string
+	if existing, err := s.userRepo.GetByEmail(ctx, emailAddr); err == nil && existing != nil {
+		if _, err := s.repo.FindMember(ctx, orgID, existing.ID); err == nil {
+			return nil, errors.New(errors.EDUPLICATION, "organization.already_member")
+		}
+		locale, timezone = existing.Metadata.Locale, existing.Metadata.Timezone
+	} else if inviter, err := s.userRepo.GetByID(ctx, actorID); err == nil {
+		locale, timezone = inviter.Metadata.Locale, inviter.Metadata.Timezone
+	}
+
+	if err := s.repo.RevokePendingInvitationsByEmail(ctx, orgID, emailAddr); err != nil {
+		s.logger.Warn("Failed to revoke previous organization invitations", zap.Error(err))
+	}
+
+	tokenCode, err := generateSecureToken()
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "common.token_generation_failed")
+	}
+
+	invitation := &Invitation{
+		OrganizationID: orgID,
+		Email:          emailAddr,
+		Role:           role,
+		Token:          tokenCode,
+		InvitedBy:      &actorID,
+		ExpiresAt:      utils.Now().Add(time.Duration(s.expiration) * time.Hour),
+	}
+
+	if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+		s.logger.Error("Failed to save organization invitation", zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "organization.invitation_create_failed")
+	}
+
+	go func() {
+		sendCtx := context.Background()
+		if err := s.sendInvitationEmail(sendCtx, org, emailAddr, tokenCode, locale, timezone); err != nil {
+			s.logger.Error("Failed to send organization invitation email",
+				zap.Int64("organizationId", orgID),
+				zap.Error(err),
+			)
+		}
+	}()
+
+	s.logger.Info("Organization invitation created", zap.Int64("organizationId", orgID), zap.Int64("invitedBy", actorID))
+	return invitation, nil
+}
+
+func (s *Service) ListInvitations(ctx context.Context, orgID, actorID int64) ([]Invitation, error) {
+	if _, err := s.requireManager(ctx, orgID, actorID); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.repo.FindPendingInvitations(ctx, orgID)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "organization.invitations_failed")
+	}
+	return invitations, nil
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, orgID, actorID, invitationID int64) error {
+	if _, err := s.requireManager(ctx, orgID, actorID); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeInvitation(ctx, orgID, invitationID)
+	if err != nil {
+		return errors.New(errors.EINTERNAL, "invitation.revoke_failed")
+	}
+	if !revoked {
+		return errors.New(errors.ENOTFOUND, "invitation.not_found")
+	}
+	return nil
+}
+
+func (s *Service) AcceptInvitation(ctx context.Context, tokenCode string, userID int64) (*Member, error) {
+	invitation, err := s.repo.FindInvitationByToken(ctx, tokenCode)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "invitation.invalid")
+	}
+
+	if invitation.IsExpired() {
+		return nil, errors.New(errors.EINVALID, "invitation.expired")
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if !strings.EqualFold(u.Email, invitation.Email) {
+		return nil, errors.New(errors.EFORBIDDEN, "organization.invitation_other_email")
+	}
+
+	if _, err := s.repo.FindMember(ctx, invitation.OrganizationID, userID); err == nil {
+		return nil, errors.New(errors.EDUPLICATION, "organization.already_member")
+	}
+
+	member := &Member{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         userID,
+		Role:           invitation.Role,
+	}
+
+	if err := s.repo.AcceptInvitation(ctx, invitation, member); err != nil {
+		s.logger.Error("Failed to accept organization invitation", zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "organization.invitation_accept_failed")
+	}
+
+	s.logger.Info("Organization invitation accepted",
+		zap.Int64("organizationId", invitation.OrganizationID),
+		zap.Int64("userId", userID),
+	)
+	return s.repo.FindMember(ctx, invitation.OrganizationID, userID)
+}
+
+func (s *Service) requireManager(ctx context.Context, orgID, userID int64) (*Member, error) {
+	member, err := s.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !member.CanManage() {
+		return nil, errors.New(errors.EFORBIDDEN, "auth.insufficient_permissions")
+	}
+	return member, nil
+}
+
+func (s *Service) ensureAnotherOwner(ctx context.Context, orgID int64) error {
+	owners, err := s.repo.CountOwners(ctx, orgID)
+	if err != nil {
+		return errors.New(errors.EINTERNAL, "organization.owners_check_failed")
+	}
+	if owners <= 1 {
+		return errors.New(errors.EINVALID, "organization.owner_required")
+	}
+	return nil
+}
+
+func (s *Service) uniqueSlug(ctx context.Context, name string) (string, error) {
+	base := slugify(name)
+	if base == "" || strings.HasPrefix(base, "personal-") {
+		base = "org"
+	}
+
+	slug := base
+	for i := 0; i < 5; i++ {
+		exists, err := s.repo.SlugExists(ctx, slug)
+		if err != nil {
+			return "", errors.New(errors.EINTERNAL, "organization.create_failed")
+		}
+		if !exists {
+			return slug, nil
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", errors.New(errors.EINTERNAL, "organization.create_failed")
+		}
+		slug = base + "-" + hex.EncodeToString(suffix)
+	}
+
+	return "", errors.New(errors.EINTERNAL, "organization.slug_failed")
+}
+
+func (s *Service) sendInvitationEmail(ctx context.Context, org *Organization, to, tokenCode, locale, timezone string) error {
+	acceptLink := fmt.Sprintf("%s/accept-organization-invitation?token=%s", s.frontendURL, tokenCode)
+
+	subject, body, err := email.Render(locale, timezone, "organization_invitation", map[string]any{
+		"Organization":    org.Name,
+		"URL":             acceptLink,
+		"ExpirationHours": s.expiration,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, to, subject, body)
+}
+
+func slugify(name string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			lastDash = false
+			continue
+		}
+		if !lastDash && b.Len() > 0 {
+			b.WriteByte('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func generateSecureToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(bytes), nil
+}
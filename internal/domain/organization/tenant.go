@@ -0,0 +1,143 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const TenantColumn = "organization_id"
+
+var (
+	ErrTenantRequired = errors.New("tenant: no organization in context")
+	ErrTenantMismatch = errors.New("tenant: row belongs to another organization")
+)
+
+type tenantContextKey struct{}
+
+type tenantBypassKey struct{}
+
+// TenantScoped is implemented by models whose rows belong to a single
+// organization. Queries on these models are filtered automatically by the
+// organization stored in the request context, and fail without one unless
+// the context comes from WithoutTenant.
+type TenantScoped interface {
+	TenantScoped()
+}
+
+func WithOrganizationID(ctx context.Context, orgID int64) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, orgID)
+}
+
+// WithoutTenant lets queries on tenant-scoped models run across all
+// organizations, for system jobs and lookups that precede the organization
+// (e.g. an invitation token). It clears any organization already in ctx, so
+// the bypass holds inside requests scoped to the active organization.
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(context.WithValue(ctx, tenantContextKey{}, int64(0)), tenantBypassKey{}, true)
+}
+
+func OrganizationIDFromContext(ctx context.Context) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	orgID, ok := ctx.Value(tenantContextKey{}).(int64)
+	return orgID, ok && orgID > 0
+}
+
+// TenantScope filters any query by the organization in ctx. Use it for models
+// that do not implement TenantScoped or for raw table queries.
+func TenantScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		orgID, ok := OrganizationIDFromContext(ctx)
+		if !ok {
+			return db.Where("1 = 0")
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: TenantColumn}, Value: orgID})
+	}
+}
+
+func RegisterTenantCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", tenantFilter); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", tenantFilter); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", tenantFilter); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", tenantFilter); err != nil {
+		return err
+	}
+	return cb.Create().Before("gorm:create").Register("tenant:create", tenantAssign)
+}
+
+func tenantFilter(db *gorm.DB) {
+	orgID, scoped := tenantOf(db)
+	if !scoped {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: TenantColumn}, Value: orgID},
+	}})
+}
+
+func tenantAssign(db *gorm.DB) {
+	orgID, scoped := tenantOf(db)
+	if !scoped {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(TenantColumn)
+	if field == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	assign := func(rv reflect.Value) {
+		value, zero := field.ValueOf(ctx, rv)
+		if zero {
+			_ = field.Set(ctx, rv, orgID)
+		} else if value != orgID {
+			_ = db.AddError(ErrTenantMismatch)
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			assign(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		assign(rv)
+	}
+}
+
+// tenantOf returns the organization a statement on a tenant-scoped model is
+// limited to. Without one the statement fails, unless it runs WithoutTenant.
+func tenantOf(db *gorm.DB) (int64, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return 0, false
+	}
+
+	if _, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(TenantScoped); !ok {
+		return 0, false
+	}
+
+	if orgID, ok := OrganizationIDFromContext(db.Statement.Context); ok {
+		return orgID, true
+	}
+
+	if bypass, _ := db.Statement.Context.Value(tenantBypassKey{}).(bool); !bypass {
+		_ = db.AddError(ErrTenantRequired)
+	}
+	return 0, false
+}
@@ -0,0 +1,89 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost", PreferSimpleProtocol: true, WithoutReturning: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTenantCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTenantCallbacks(t *testing.T) {
+	db := newDryRunDB(t)
+	scoped := WithOrganizationID(context.Background(), 42)
+
+	tests := []struct {
+		name      string
+		run       func(tx *gorm.DB) *gorm.DB
+		ctx       context.Context
+		wantErr   error
+		wantWhere bool
+	}{
+		{"query filtered by organization", func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]Member{}) }, scoped, nil, true},
+		{"update filtered by organization", func(tx *gorm.DB) *gorm.DB {
+			return tx.Model(&Member{}).Where("user_id = ?", 1).Update("role", RoleAdmin)
+		}, scoped, nil, true},
+		{"delete filtered by organization", func(tx *gorm.DB) *gorm.DB { return tx.Where("user_id = ?", 1).Delete(&Member{}) }, scoped, nil, true},
+		{"query without organization fails", func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]Invitation{}) }, context.Background(), ErrTenantRequired, false},
+		{"delete without organization fails", func(tx *gorm.DB) *gorm.DB { return tx.Where("user_id = ?", 1).Delete(&Member{}) }, context.Background(), ErrTenantRequired, false},
+		{"system context runs unscoped", func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]Member{}) }, WithoutTenant(context.Background()), nil, false},
+		{"system context wins over organization", func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]Member{}) }, WithoutTenant(scoped), nil, false},
+		{"organization rescopes system context", func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]Member{}) }, WithOrganizationID(WithoutTenant(scoped), 7), nil, true},
+		{"models without tenant are untouched", func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]Organization{}) }, context.Background(), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.run(db.WithContext(tt.ctx))
+
+			if !errors.Is(result.Error, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", result.Error, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			sql := result.Statement.SQL.String()
+			if got := strings.Contains(sql, `"organization_id" = $`); got != tt.wantWhere {
+				t.Fatalf("filtered = %v, want %v: %s", got, tt.wantWhere, sql)
+			}
+		})
+	}
+}
+
+func TestTenantAssign(t *testing.T) {
+	db := newDryRunDB(t)
+	ctx := WithOrganizationID(context.Background(), 42)
+
+	member := &Member{UserID: 1, Role: RoleMember}
+	if err := db.WithContext(ctx).Create(member).Error; err != nil {
+		t.Fatal(err)
+	}
+	if member.OrganizationID != 42 {
+		t.Fatalf("OrganizationID = %d, want 42", member.OrganizationID)
+	}
+
+	other := &Invitation{OrganizationID: 7, Email: "user@example.com", Token: "token"}
+	if err := db.WithContext(ctx).Create(other).Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("error = %v, want %v", err, ErrTenantMismatch)
+	}
+
+	if err := db.Create(&Member{UserID: 1}).Error; !errors.Is(err, ErrTenantRequired) {
+		t.Fatalf("error = %v, want %v", err, ErrTenantRequired)
+	}
+}
@@ -16,6 +16,8 @@ type Repository interface {
 
 	MarkAllAsUsedByUserID(ctx context.Context, userID int64) error
 
+	FindByUserID(ctx context.Context, userID int64) ([]PasswordResetToken, error)
+
 	Save(ctx context.Context, token *PasswordResetToken) error
 
 	DeleteExpired(ctx context.Context) error
@@ -63,6 +65,17 @@ func (r *GormRepository) MarkAllAsUsedByUserID(ctx context.Context, userID int64
 		}).Error
 }
 
+func (r *GormRepository) FindByUserID(ctx context.Context, userID int64) ([]PasswordResetToken, error) {
+	var tokens []PasswordResetToken
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
 func (r *GormRepository) Save(ctx context.Context, token *PasswordResetToken) error {
 	return r.db.WithContext(ctx).Save(token).Error
 }
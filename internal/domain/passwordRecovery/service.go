@@ -55,7 +55,7 @@ func (s *Service) CreateAndSendRecoveryToken(ctx context.Context, u *user.User)
 
 	tokenCode, err := generateSecureToken()
 	if err != nil {
-		return nil, errors.Errorf(errors.EINTERNAL, "failed to generate secure token")
+		return nil, errors.New(errors.EINTERNAL, "common.token_generation_failed")
 	}
 
 	token := &PasswordResetToken{
@@ -68,7 +68,7 @@ func (s *Service) CreateAndSendRecoveryToken(ctx context.Context, u *user.User)
 
 	if err := s.tokenRepo.Create(ctx, token); err != nil {
 		s.logger.Error("Failed to save recovery token", zap.Error(err))
-		return nil, errors.Errorf(errors.EINTERNAL, "failed to create recovery token")
+		return nil, errors.New(errors.EINTERNAL, "password_recovery.token_create_failed")
 	}
 
 	go func() {
@@ -92,12 +92,12 @@ func (s *Service) VerifyToken(ctx context.Context, tokenCode string) (*PasswordR
 	token, err := s.tokenRepo.FindByToken(ctx, tokenCode)
 	if err != nil {
 		s.logger.Warn("Token not found or already used", zap.String("token", truncateToken(tokenCode)))
-		return nil, errors.Errorf(errors.ENOTFOUND, "Token inválido ou já utilizado")
+		return nil, errors.New(errors.ENOTFOUND, "password_recovery.token_invalid")
 	}
 
 	if token.IsExpired() {
 		s.logger.Warn("Token expired", zap.Int64("tokenId", token.ID))
-		return nil, errors.Errorf(errors.EINVALID, "Token expirado")
+		return nil, errors.New(errors.EINVALID, "password_recovery.token_expired")
 	}
 
 	return token, nil
@@ -113,17 +113,19 @@ func (s *Service) ResetPassword(ctx context.Context, tokenCode, newPassword stri
 
 	hashedPassword, err := encrypt.HashPassword(newPassword)
 	if err != nil {
-		return errors.Errorf(errors.EINTERNAL, "Erro ao processar nova senha")
+		return errors.New(errors.EINTERNAL, "password.hash_failed")
 	}
 
 	u, err := s.userRepo.GetByID(ctx, token.UserID)
 	if err != nil {
-		return errors.Errorf(errors.ENOTFOUND, "Usuário não encontrado")
+		return errors.New(errors.ENOTFOUND, "user.not_found")
 	}
 
 	u.Password = &hashedPassword
+	u.Metadata.MustSetPassword = false
+	u.Metadata.PasswordChangeRequired = false
 	if err := s.userRepo.Update(ctx, u); err != nil {
-		return errors.Errorf(errors.EINTERNAL, "Erro ao atualizar senha")
+		return errors.New(errors.EINTERNAL, "password.update_failed")
 	}
 
 	token.MarkAsUsed()
@@ -142,26 +144,13 @@ func (s *Service) sendPasswordResetEmail(ctx context.Context, u *user.User, toke
 
 	resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, tokenCode)
 
-	subject := "Recuperação de Senha"
-
-	body := fmt.Sprintf(`
-		<div style="font-family: sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-			<h2>Olá, %s</h2>
-			<p>Recebemos uma solicitação para redefinir a sua senha.</p>
-			<p>Clique no link abaixo para prosseguir:</p>
-			<div style="margin: 30px 0;">
-				<a href="%s" style="background-color: #007bff; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; font-weight: bold;">
-					Redefinir Senha
-				</a>
-			</div>
-			<p>Se você não solicitou isso, pode ignorar este email.</p>
-			<hr style="border: none; border-top: 1px solid #eee; margin: 20px 0;">
-			<p style="font-size: 12px; color: #666;">
-				Ou copie e cole este link no seu navegador:<br>
-				%s
-			</p>
-		</div>
-	`, u.Name, resetLink, resetLink)
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "password_reset", map[string]any{
+		"Name": u.Name,
+		"URL":  resetLink,
+	})
+	if err != nil {
+		return err
+	}
 
 	return s.emailSender.SendEmail(ctx, u.Email, subject, body)
 }
@@ -0,0 +1,64 @@
+package plancatalog
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+)
+
+// Plan is a catalog entry: its rank orders plans for RequireMinPlan checks
+// and its limits and features are the entitlements granted to subscribers.
+type Plan struct {
+	Code                    user.PlanType `gorm:"primaryKey;size:20"`
+	Name                    string        `gorm:"size:100;not null"`
+	Rank                    int           `gorm:"not null"`
+	MaxAccounts             int           `gorm:"not null"`
+	MaxCategoriesPerAccount int           `gorm:"not null"`
+	MaxTransactionsPerMonth int           `gorm:"not null"`
+	MaxResources            *int          `gorm:"column:max_resources"`
+	MaxRequestsPerMonth     *int          `gorm:"column:max_requests_per_month"`
+	CreatedAt               time.Time     `gorm:"not null"`
+	UpdatedAt               time.Time
+
+	// Features are the enabled feature keys, filled from plan_features.
+	Features []string `gorm:"-"`
+}
+
+func (Plan) TableName() string {
+	return "plans"
+}
+
+func (p *Plan) Entitlements() user.PlanMetadata {
+	return user.PlanMetadata{
+		PlanType:                p.Code,
+		MaxResources:            p.MaxResources,
+		MaxRequestsPerMonth:     p.MaxRequestsPerMonth,
+		MaxAccounts:             p.MaxAccounts,
+		MaxCategoriesPerAccount: p.MaxCategoriesPerAccount,
+		MaxTransactionsPerMonth: p.MaxTransactionsPerMonth,
+		Features:                slices.Clone(p.Features),
+	}
+}
+
+// Feature is an entry of the feature registry. Plans enable features through
+// PlanFeature rows and users may be granted or denied a feature individually.
+type Feature struct {
+	Key         string    `gorm:"primaryKey;size:50"`
+	Description string    `gorm:"size:255;not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time
+}
+
+func (Feature) TableName() string {
+	return "features"
+}
+
+type PlanFeature struct {
+	PlanCode   user.PlanType `gorm:"primaryKey;size:20"`
+	FeatureKey string        `gorm:"primaryKey;size:50"`
+}
+
+func (PlanFeature) TableName() string {
+	return "plan_features"
+}
@@ -0,0 +1,79 @@
+package plancatalog
+
+import (
+	"context"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindAll(ctx context.Context) ([]Plan, error)
+	FindByCode(ctx context.Context, code user.PlanType) (*Plan, error)
+	Save(ctx context.Context, plan *Plan) error
+
+	FindFeatures(ctx context.Context) ([]Feature, error)
+	FindPlanFeatures(ctx context.Context) ([]PlanFeature, error)
+	SaveFeature(ctx context.Context, feature *Feature, plans []user.PlanType) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) FindAll(ctx context.Context) ([]Plan, error) {
+	var plans []Plan
+	err := r.db.WithContext(ctx).Order("rank ASC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *GormRepository) FindByCode(ctx context.Context, code user.PlanType) (*Plan, error) {
+	var plan Plan
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&plan).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *GormRepository) Save(ctx context.Context, plan *Plan) error {
+	return r.db.WithContext(ctx).Save(plan).Error
+}
+
+func (r *GormRepository) FindFeatures(ctx context.Context) ([]Feature, error) {
+	var features []Feature
+	err := r.db.WithContext(ctx).Order("key ASC").Find(&features).Error
+	return features, err
+}
+
+func (r *GormRepository) FindPlanFeatures(ctx context.Context) ([]PlanFeature, error) {
+	var planFeatures []PlanFeature
+	err := r.db.WithContext(ctx).Order("feature_key ASC").Find(&planFeatures).Error
+	return planFeatures, err
+}
+
+// SaveFeature upserts the feature and replaces the plans that enable it.
+func (r *GormRepository) SaveFeature(ctx context.Context, feature *Feature, plans []user.PlanType) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(feature).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_key = ?", feature.Key).Delete(&PlanFeature{}).Error; err != nil {
+			return err
+		}
+
+		planFeatures := make([]PlanFeature, 0, len(plans))
+		for _, plan := range plans {
+			planFeatures = append(planFeatures, PlanFeature{PlanCode: plan, FeatureKey: feature.Key})
+		}
+
+		if len(planFeatures) > 0 {
+			return tx.Create(&planFeatures).Error
+		}
+		return nil
+	})
+}
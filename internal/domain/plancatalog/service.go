@@ -0,0 +1,478 @@
+package plancatalog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// PlanUpdate holds the catalog fields to change. Nil fields are kept.
+type PlanUpdate struct {
+	Name                    *string
+	Rank                    *int
+	MaxAccounts             *int
+	MaxCategoriesPerAccount *int
+	MaxTransactionsPerMonth *int
+	MaxResources            *QuotaUpdate
+	MaxRequestsPerMonth     *QuotaUpdate
+}
+
+// QuotaUpdate sets a monthly quota of the plan. A nil Limit removes the
+// quota, making the plan unlimited.
+type QuotaUpdate struct {
+	Limit *int
+}
+
+// FeatureUpdate registers or changes a feature. Plans, when set, replaces
+// the plans that enable it.
+type FeatureUpdate struct {
+	Description *string
+	Plans       *[]string
+}
+
+// Service keeps the plan catalog in memory, so entitlement lookups do not hit
+// the database. Load is called at startup and periodically to pick up
+// changes made by other instances.
+type Service struct {
+	repo      Repository
+	userRepo  user.UserService
+	batchSize int
+	logger    logger.Logger
+
+	mu       sync.RWMutex
+	plans    []Plan
+	features []Feature
+}
+
+func NewService(repo Repository, userRepo user.UserService, batchSize int, logger logger.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		userRepo:  userRepo,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+func (s *Service) Load(ctx context.Context) error {
+	plans, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	features, err := s.repo.FindFeatures(ctx)
+	if err != nil {
+		return err
+	}
+	planFeatures, err := s.repo.FindPlanFeatures(ctx)
+	if err != nil {
+		return err
+	}
+
+	hasFree := false
+	for i := range plans {
+		if plans[i].Code == user.PlanTypeFree {
+			hasFree = true
+		}
+		for _, pf := range planFeatures {
+			if pf.PlanCode == plans[i].Code {
+				plans[i].Features = append(plans[i].Features, pf.FeatureKey)
+			}
+		}
+	}
+	if !hasFree {
+		return fmt.Errorf("plan catalog has no %s plan", user.PlanTypeFree)
+	}
+
+	s.mu.Lock()
+	s.plans = plans
+	s.features = features
+	s.mu.Unlock()
+	return nil
+}
+
+// List returns the plans ordered by rank.
+func (s *Service) List() []Plan {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plans := make([]Plan, len(s.plans))
+	copy(plans, s.plans)
+	return plans
+}
+
+func (s *Service) Get(code user.PlanType) (Plan, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, p := range s.plans {
+		if p.Code == code {
+			return p, true
+		}
+	}
+	return Plan{}, false
+}
+
+func (s *Service) Rank(code user.PlanType) (int, bool) {
+	p, ok := s.Get(code)
+	return p.Rank, ok
+}
+
+// ListFeatures returns the feature registry ordered by key.
+func (s *Service) ListFeatures() []Feature {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	features := make([]Feature, len(s.features))
+	copy(features, s.features)
+	return features
+}
+
+// ValidateFeature checks that the key is registered.
+func (s *Service) ValidateFeature(key string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, f := range s.features {
+		if f.Key == key {
+			return nil
+		}
+	}
+	return errors.New(errors.EINVALID, "feature.unknown", key)
+}
+
+// ParsePlanType validates a plan code against the catalog.
+func (s *Service) ParsePlanType(value string) (user.PlanType, error) {
+	code := user.PlanType(strings.ToUpper(strings.TrimSpace(value)))
+	if _, ok := s.Get(code); !ok {
+		return "", errors.New(errors.EINVALID, "plan.invalid", value)
+	}
+	return code, nil
+}
+
+// Entitlements returns the limits and features of a plan with the user's
+// overrides applied. A plan missing from the catalog is an error rather than
+// a fallback, so a user is never moved to another plan by accident.
+func (s *Service) Entitlements(planType user.PlanType, overrides *user.PlanOverrides) (user.PlanMetadata, error) {
+	p, ok := s.Get(planType)
+	if !ok {
+		return user.PlanMetadata{}, errors.New(errors.EINVALID, "plan.invalid", string(planType))
+	}
+
+	metadata := p.Entitlements()
+	overrides.Apply(&metadata)
+	return metadata, nil
+}
+
+// DefaultMetadata is the metadata of a new account, on the FREE plan. Load
+// refuses a catalog without FREE, so the lookup cannot fail once loaded.
+func (s *Service) DefaultMetadata() user.UserMetadata {
+	p, _ := s.Get(user.PlanTypeFree)
+	return user.NewDefaultMetadata(p.Entitlements())
+}
+
+// Update changes a catalog entry and, in the background, applies the new
+// entitlements to every user on that plan.
+func (s *Service) Update(ctx context.Context, code user.PlanType, update PlanUpdate) (*Plan, error) {
+	p, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "plan.not_found")
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, errors.New(errors.EINVALID, "plan.name_required")
+		}
+		p.Name = name
+	}
+	if update.Rank != nil {
+		p.Rank = *update.Rank
+	}
+	for _, limit := range []struct {
+		value  *int
+		target *int
+	}{
+		{update.MaxAccounts, &p.MaxAccounts},
+		{update.MaxCategoriesPerAccount, &p.MaxCategoriesPerAccount},
+		{update.MaxTransactionsPerMonth, &p.MaxTransactionsPerMonth},
+	} {
+		if limit.value == nil {
+			continue
+		}
+		if *limit.value < 0 {
+			return nil, errors.New(errors.EINVALID, "plan.negative_limits")
+		}
+		*limit.target = *limit.value
+	}
+	for _, quota := range []struct {
+		value  *QuotaUpdate
+		target **int
+	}{
+		{update.MaxResources, &p.MaxResources},
+		{update.MaxRequestsPerMonth, &p.MaxRequestsPerMonth},
+	} {
+		if quota.value == nil {
+			continue
+		}
+		if quota.value.Limit != nil && *quota.value.Limit < 0 {
+			return nil, errors.New(errors.EINVALID, "plan.negative_limits")
+		}
+		*quota.target = quota.value.Limit
+	}
+
+	p.UpdatedAt = utils.Now()
+	if err := s.repo.Save(ctx, p); err != nil {
+		s.logger.Error("Failed to update plan", zap.String("plan", string(code)), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "plan.update_failed")
+	}
+
+	if err := s.Load(ctx); err != nil {
+		s.logger.Error("Failed to reload plan catalog", zap.Error(err))
+	}
+	if cached, ok := s.Get(code); ok {
+		p.Features = cached.Features
+	}
+
+	go s.resync(context.Background(), code)
+
+	s.logger.Info("Plan updated", zap.String("plan", string(code)))
+	return p, nil
+}
+
+// SaveFeature registers a feature or updates its description and plans. Users
+// on the plans that gained or lost the feature are resynced in background.
+func (s *Service) SaveFeature(ctx context.Context, key string, update FeatureUpdate) (*Feature, error) {
+	if !isValidFeatureKey(key) {
+		return nil, errors.New(errors.EINVALID, "feature.invalid_key")
+	}
+
+	var feature *Feature
+	for _, f := range s.ListFeatures() {
+		if f.Key == key {
+			feature = &f
+			break
+		}
+	}
+
+	now := utils.Now()
+	if feature == nil {
+		if update.Description == nil {
+			return nil, errors.New(errors.EINVALID, "feature.description_required")
+		}
+		feature = &Feature{Key: key, CreatedAt: now}
+	}
+	if update.Description != nil {
+		description := strings.TrimSpace(*update.Description)
+		if description == "" {
+			return nil, errors.New(errors.EINVALID, "feature.description_required")
+		}
+		feature.Description = description
+	}
+	feature.UpdatedAt = now
+
+	var previous, plans []user.PlanType
+	for _, p := range s.List() {
+		if slices.Contains(p.Features, key) {
+			previous = append(previous, p.Code)
+		}
+	}
+	plans = previous
+	if update.Plans != nil {
+		plans = make([]user.PlanType, 0, len(*update.Plans))
+		for _, value := range *update.Plans {
+			code, err := s.ParsePlanType(value)
+			if err != nil {
+				return nil, err
+			}
+			if !slices.Contains(plans, code) {
+				plans = append(plans, code)
+			}
+		}
+	}
+
+	if err := s.repo.SaveFeature(ctx, feature, plans); err != nil {
+		s.logger.Error("Failed to save feature", zap.String("feature", key), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "feature.save_failed")
+	}
+
+	if err := s.Load(ctx); err != nil {
+		s.logger.Error("Failed to reload plan catalog", zap.Error(err))
+	}
+
+	for _, p := range s.List() {
+		if slices.Contains(previous, p.Code) != slices.Contains(plans, p.Code) {
+			go s.resync(context.Background(), p.Code)
+		}
+	}
+
+	s.logger.Info("Feature saved", zap.String("feature", key))
+	return feature, nil
+}
+
+// FeaturePlans returns the plans that enable the feature.
+func (s *Service) FeaturePlans(key string) []user.PlanType {
+	var plans []user.PlanType
+	for _, p := range s.List() {
+		if slices.Contains(p.Features, key) {
+			plans = append(plans, p.Code)
+		}
+	}
+	return plans
+}
+
+// SetFeatureOverrides grants (true) or denies (false) features to a user
+// regardless of the plan; nil removes the override.
+func (s *Service) SetFeatureOverrides(ctx context.Context, userID int64, features map[string]*bool) (*user.User, error) {
+	for key := range features {
+		if err := s.ValidateFeature(key); err != nil {
+			return nil, err
+		}
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	overrides := u.Metadata.PlanOverrides
+	if overrides == nil {
+		overrides = &user.PlanOverrides{}
+	}
+	if overrides.Features == nil {
+		overrides.Features = make(map[string]bool, len(features))
+	}
+	for key, enabled := range features {
+		if enabled == nil {
+			delete(overrides.Features, key)
+			continue
+		}
+		overrides.Features[key] = *enabled
+	}
+
+	u.Metadata.PlanOverrides = overrides
+	if overrides.IsEmpty() {
+		u.Metadata.PlanOverrides = nil
+	}
+	if err := s.reapply(&u.Metadata); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to update feature overrides", zap.Int64("userId", userID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "feature.update_failed")
+	}
+	return u, nil
+}
+
+// ResetOverrides drops the user's overrides, going back to the catalog
+// entitlements of the current plan.
+func (s *Service) ResetOverrides(ctx context.Context, userID int64) (*user.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	u.Metadata.PlanOverrides = nil
+	if err := s.reapply(&u.Metadata); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to reset plan overrides", zap.Int64("userId", userID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "plan.update_failed")
+	}
+	return u, nil
+}
+
+// reapply refreshes the entitlements of the current plan, keeping its
+// expiration and source.
+func (s *Service) reapply(m *user.UserMetadata) error {
+	plan, err := s.Entitlements(m.PlanType, m.PlanOverrides)
+	if err != nil {
+		return err
+	}
+
+	expiresAt, source := m.PlanExpirationDate, m.ProSource
+	m.PlanMetadata = plan
+	m.PlanExpirationDate, m.ProSource = expiresAt, source
+	return nil
+}
+
+// resyncAttempts bounds how often a user changed concurrently during a resync
+// is reloaded and tried again.
+const resyncAttempts = 3
+
+func (s *Service) resync(ctx context.Context, code user.PlanType) {
+	filter := user.ListFilter{PlanType: code, Sort: user.DefaultSortOrder}
+
+	var cursor *user.Cursor
+	var failed []int64
+	updated := 0
+	for {
+		users, next, err := s.userRepo.FindWithCursor(ctx, filter, cursor, s.batchSize)
+		if err != nil {
+			s.logger.Error("Failed to load users for plan resync", zap.String("plan", string(code)), zap.Error(err))
+			return
+		}
+
+		for i := range users {
+			if err := s.resyncUser(ctx, &users[i]); err != nil {
+				s.logger.Error("Failed to resync user plan", zap.Int64("userId", users[i].ID), zap.Error(err))
+				failed = append(failed, users[i].ID)
+				continue
+			}
+			updated++
+		}
+
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+
+	if len(failed) > 0 {
+		s.logger.Error("Plan resync left users on stale entitlements",
+			zap.String("plan", string(code)), zap.Int64s("userIds", failed))
+	}
+	s.logger.Info("Plan entitlements resynced", zap.String("plan", string(code)), zap.Int("users", updated))
+}
+
+// resyncUser reapplies the catalog to one user. On a version conflict the
+// user is reloaded, so the entitlements follow whatever plan they are on now.
+func (s *Service) resyncUser(ctx context.Context, u *user.User) error {
+	for attempt := 1; ; attempt++ {
+		if err := s.reapply(&u.Metadata); err != nil {
+			return err
+		}
+		err := s.userRepo.Update(ctx, u)
+		if err == nil || !user.IsVersionConflict(err) || attempt == resyncAttempts {
+			return err
+		}
+		if u, err = s.userRepo.GetByID(ctx, u.ID); err != nil {
+			return err
+		}
+	}
+}
+
+func isValidFeatureKey(key string) bool {
+	if key == "" || len(key) > 50 || key[0] < 'a' || key[0] > 'z' {
+		return false
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+package plancatalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+)
+
+func newTestService(userRepo user.UserService) *Service {
+	log, _ := logger.NewLogger("test", "none")
+	s := NewService(nil, userRepo, 10, log)
+	s.plans = []Plan{
+		{Code: user.PlanTypeFree, Rank: 0, MaxAccounts: 1, MaxCategoriesPerAccount: 10, MaxTransactionsPerMonth: 100},
+		{Code: user.PlanTypePro, Rank: 1, MaxAccounts: 5, MaxCategoriesPerAccount: 50, MaxTransactionsPerMonth: 1000, Features: []string{"export"}},
+	}
+	return s
+}
+
+func TestEntitlements(t *testing.T) {
+	s := newTestService(nil)
+	three := 3
+
+	tests := []struct {
+		name            string
+		plan            user.PlanType
+		overrides       *user.PlanOverrides
+		wantErrKey      string
+		wantMaxAccounts int
+	}{
+		{"catalog plan", user.PlanTypePro, nil, "", 5},
+		{"overrides applied", user.PlanTypePro, &user.PlanOverrides{MaxAccounts: &three}, "", 3},
+		{"unknown plan", user.PlanTypeEnterprise, nil, "plan.invalid", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Entitlements(tt.plan, tt.overrides)
+			if tt.wantErrKey != "" {
+				if err == nil || errors.ErrorKey(err) != tt.wantErrKey {
+					t.Fatalf("Entitlements() error = %v, want %s", err, tt.wantErrKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Entitlements() error = %v", err)
+			}
+			if got.PlanType != tt.plan {
+				t.Errorf("PlanType = %q, want %q", got.PlanType, tt.plan)
+			}
+			if got.MaxAccounts != tt.wantMaxAccounts {
+				t.Errorf("MaxAccounts = %d, want %d", got.MaxAccounts, tt.wantMaxAccounts)
+			}
+		})
+	}
+}
+
+// resyncUserRepo serves the users of a resync and fails the first updates of
+// each user with a version conflict.
+type resyncUserRepo struct {
+	user.UserService
+	users     map[int64]*user.User
+	conflicts map[int64]int
+	updates   map[int64]int
+}
+
+func (r *resyncUserRepo) FindWithCursor(_ context.Context, _ user.ListFilter, _ *user.Cursor, _ int) ([]user.User, *user.Cursor, error) {
+	var users []user.User
+	for _, u := range r.users {
+		users = append(users, *u)
+	}
+	return users, nil, nil
+}
+
+func (r *resyncUserRepo) GetByID(_ context.Context, id int64) (*user.User, error) {
+	u := *r.users[id]
+	return &u, nil
+}
+
+func (r *resyncUserRepo) Update(_ context.Context, u *user.User) error {
+	if r.conflicts[u.ID] > 0 {
+		r.conflicts[u.ID]--
+		return user.ErrVersionConflict
+	}
+	r.updates[u.ID]++
+	stored := *u
+	r.users[u.ID] = &stored
+	return nil
+}
+
+func TestResyncRetriesVersionConflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		conflicts   int
+		wantUpdated bool
+	}{
+		{"no conflict", 0, true},
+		{"conflicts within attempts", resyncAttempts - 1, true},
+		{"conflicts on every attempt", resyncAttempts, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stale := user.User{ID: 1}
+			stale.Metadata.PlanMetadata = user.PlanMetadata{PlanType: user.PlanTypePro, MaxAccounts: 2}
+			repo := &resyncUserRepo{
+				users:     map[int64]*user.User{1: &stale},
+				conflicts: map[int64]int{1: tt.conflicts},
+				updates:   map[int64]int{},
+			}
+
+			newTestService(repo).resync(context.Background(), user.PlanTypePro)
+
+			if got := repo.updates[1] == 1; got != tt.wantUpdated {
+				t.Fatalf("updated = %v, want %v", got, tt.wantUpdated)
+			}
+			want := 2
+			if tt.wantUpdated {
+				want = 5
+			}
+			if got := repo.users[1].Metadata.MaxAccounts; got != want {
+				t.Errorf("MaxAccounts = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// updateRepo stores a single plan in memory.
+type updateRepo struct {
+	Repository
+	plan Plan
+}
+
+func (r *updateRepo) FindAll(context.Context) ([]Plan, error) {
+	return []Plan{{Code: user.PlanTypeFree}, r.plan}, nil
+}
+
+func (r *updateRepo) FindByCode(_ context.Context, code user.PlanType) (*Plan, error) {
+	p := r.plan
+	return &p, nil
+}
+
+func (r *updateRepo) Save(_ context.Context, plan *Plan) error {
+	r.plan = *plan
+	return nil
+}
+
+func (r *updateRepo) FindFeatures(context.Context) ([]Feature, error) {
+	return nil, nil
+}
+
+func (r *updateRepo) FindPlanFeatures(context.Context) ([]PlanFeature, error) {
+	return nil, nil
+}
+
+func TestUpdateQuotas(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name         string
+		update       PlanUpdate
+		wantErrKey   string
+		wantResource *int
+		wantRequests *int
+	}{
+		{"quotas kept", PlanUpdate{}, "", intPtr(10), intPtr(1000)},
+		{"quota changed", PlanUpdate{MaxResources: &QuotaUpdate{Limit: intPtr(20)}}, "", intPtr(20), intPtr(1000)},
+		{"quota removed", PlanUpdate{MaxRequestsPerMonth: &QuotaUpdate{}}, "", intPtr(10), nil},
+		{"negative quota", PlanUpdate{MaxResources: &QuotaUpdate{Limit: intPtr(-1)}}, "plan.negative_limits", intPtr(10), intPtr(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &updateRepo{plan: Plan{
+				Code:                user.PlanTypePro,
+				Name:                "Pro",
+				MaxResources:        intPtr(10),
+				MaxRequestsPerMonth: intPtr(1000),
+			}}
+			s := newTestService(&resyncUserRepo{users: map[int64]*user.User{}})
+			s.repo = repo
+
+			_, err := s.Update(context.Background(), user.PlanTypePro, tt.update)
+			if tt.wantErrKey != "" {
+				if err == nil || errors.ErrorKey(err) != tt.wantErrKey {
+					t.Fatalf("Update() error = %v, want %s", err, tt.wantErrKey)
+				}
+			} else if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			if !equalLimit(repo.plan.MaxResources, tt.wantResource) {
+				t.Errorf("MaxResources = %v, want %v", repo.plan.MaxResources, tt.wantResource)
+			}
+			if !equalLimit(repo.plan.MaxRequestsPerMonth, tt.wantRequests) {
+				t.Errorf("MaxRequestsPerMonth = %v, want %v", repo.plan.MaxRequestsPerMonth, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func equalLimit(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,41 @@
+package planlifecycle
+
+import (
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+)
+
+type EventType string
+
+const (
+	EventTrialStarted EventType = "TRIAL_STARTED"
+	EventExpired      EventType = "EXPIRED"
+	EventReminderSent EventType = "REMINDER_SENT"
+	EventGranted      EventType = "GRANTED"
+	EventRevoked      EventType = "REVOKED"
+
+	EventSubscriptionStarted   EventType = "SUBSCRIPTION_STARTED"
+	EventSubscriptionRenewed   EventType = "SUBSCRIPTION_RENEWED"
+	EventSubscriptionPastDue   EventType = "SUBSCRIPTION_PAST_DUE"
+	EventSubscriptionCancelled EventType = "SUBSCRIPTION_CANCELLED"
+)
+
+// PlanEvent records every plan transition of a user, and the expiration
+// reminders sent, so the history can be audited.
+type PlanEvent struct {
+	ID        int64           `gorm:"primaryKey;autoIncrement"`
+	UserID    int64           `gorm:"not null;index"`
+	Type      EventType       `gorm:"size:30;not null"`
+	FromPlan  user.PlanType   `gorm:"size:20"`
+	ToPlan    user.PlanType   `gorm:"size:20"`
+	ProSource *user.ProSource `gorm:"column:pro_source;size:20"`
+	ExpiresAt *time.Time      `gorm:"column:expires_at"`
+	ActorID   *int64          `gorm:"column:actor_id"`
+	Reason    *string         `gorm:"column:reason"`
+	CreatedAt time.Time       `gorm:"not null"`
+}
+
+func (PlanEvent) TableName() string {
+	return "plan_events"
+}
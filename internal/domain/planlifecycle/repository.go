@@ -0,0 +1,87 @@
+package planlifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, event *PlanEvent) error
+	FindByUserID(ctx context.Context, userID int64, page, size int) ([]PlanEvent, int64, error)
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]user.User, error)
+	FindPendingReminders(ctx context.Context, from, to time.Time, limit int) ([]user.User, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// Paid plans with an expiration date. Lifetime plans have no date and never
+// expire.
+const expiringPlans = "metadata->>'plan_type' <> 'FREE' AND metadata->>'plan_expiration_date' IS NOT NULL"
+
+const planExpiration = "(metadata->>'plan_expiration_date')::timestamptz"
+
+func (r *GormRepository) Create(ctx context.Context, event *PlanEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *GormRepository) FindByUserID(ctx context.Context, userID int64, page, size int) ([]PlanEvent, int64, error) {
+	var events []PlanEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&PlanEvent{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *GormRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]user.User, error) {
+	var users []user.User
+	if err := r.db.WithContext(ctx).
+		Where(expiringPlans).
+		Where(planExpiration+" < ?", before).
+		Order(planExpiration + " ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindPendingReminders returns users whose plan expires in the window and who
+// have not been reminded about that expiration date yet. Users who turned
+// plan reminders off in their preferences are skipped.
+func (r *GormRepository) FindPendingReminders(ctx context.Context, from, to time.Time, limit int) ([]user.User, error) {
+	var users []user.User
+	if err := r.db.WithContext(ctx).
+		Where(expiringPlans).
+		Where(planExpiration+" >= ? AND "+planExpiration+" < ?", from, to).
+		Where("COALESCE((metadata->'notifications'->>'plan_reminders')::boolean, TRUE)").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM plan_events e
+			WHERE e.user_id = users.id AND e.type = ?
+			AND date_trunc('second', e.expires_at) = date_trunc('second', `+planExpiration+` AT TIME ZONE 'UTC')
+		)`, EventReminderSent).
+		Order(planExpiration + " ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
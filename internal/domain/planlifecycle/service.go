@@ -0,0 +1,418 @@
+package planlifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	repo         Repository
+	userRepo     user.UserService
+	catalog      *plancatalog.Service
+	emailSender  email.EmailSender
+	trialDays    int
+	reminderDays int
+	batchSize    int
+	logger       logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	catalog *plancatalog.Service,
+	emailSender email.EmailSender,
+	trialDays int,
+	reminderDays int,
+	batchSize int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:         repo,
+		userRepo:     userRepo,
+		catalog:      catalog,
+		emailSender:  emailSender,
+		trialDays:    trialDays,
+		reminderDays: reminderDays,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// StartTrial grants a time-boxed PRO trial. Each account can start a trial
+// only once, and only while on the FREE plan. The trial is marked as used in
+// the same version-checked update that grants it, so concurrent requests
+// cannot both succeed.
+func (s *Service) StartTrial(ctx context.Context, userID int64) (*user.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	if u.Metadata.PlanType != user.PlanTypeFree {
+		return nil, errors.New(errors.ECONFLICT, "plan.already_paid")
+	}
+
+	if u.Metadata.TrialUsedAt != nil {
+		return nil, errors.New(errors.ECONFLICT, "plan.trial_used")
+	}
+
+	now := utils.Now()
+	source := user.ProSourceTrial
+	expiresAt := now.AddDate(0, 0, s.trialDays)
+
+	plan, err := s.catalog.Entitlements(user.PlanTypePro, u.Metadata.PlanOverrides)
+	if err != nil {
+		return nil, err
+	}
+	u.Metadata.PlanMetadata = plan
+	u.Metadata.ProSource = &source
+	u.Metadata.PlanExpirationDate = &expiresAt
+	u.Metadata.TrialUsedAt = &now
+
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to start trial", zap.Int64("userId", userID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "plan.trial_failed")
+	}
+
+	s.record(ctx, &PlanEvent{
+		UserID:    u.ID,
+		Type:      EventTrialStarted,
+		FromPlan:  user.PlanTypeFree,
+		ToPlan:    user.PlanTypePro,
+		ProSource: &source,
+		ExpiresAt: &expiresAt,
+		ActorID:   &u.ID,
+	})
+
+	s.logger.Info("Trial started", zap.Int64("userId", u.ID), zap.Time("expiresAt", expiresAt))
+	return u, nil
+}
+
+// GrantLifetimePro moves the user to PRO with no expiration date, applying
+// the catalog entitlements of the plan.
+func (s *Service) GrantLifetimePro(ctx context.Context, actorID, userID int64, reason string) (*user.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+	from := u.Metadata.PlanType
+
+	source := user.ProSourceAdmin
+	plan, err := s.catalog.Entitlements(user.PlanTypePro, u.Metadata.PlanOverrides)
+	if err != nil {
+		return nil, err
+	}
+	u.Metadata.PlanMetadata = plan
+	u.Metadata.ProSource = &source
+	u.Metadata.Notes = &reason
+
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to grant lifetime pro", zap.Int64("userId", userID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "plan.update_failed")
+	}
+
+	s.record(ctx, &PlanEvent{
+		UserID:    u.ID,
+		Type:      EventGranted,
+		FromPlan:  from,
+		ToPlan:    u.Metadata.PlanType,
+		ProSource: u.Metadata.ProSource,
+		ActorID:   &actorID,
+		Reason:    &reason,
+	})
+	return u, nil
+}
+
+func (s *Service) RevokeLifetimePro(ctx context.Context, actorID, userID int64) (*user.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+	from, source := u.Metadata.PlanType, u.Metadata.ProSource
+
+	plan, err := s.catalog.Entitlements(user.PlanTypeFree, u.Metadata.PlanOverrides)
+	if err != nil {
+		return nil, err
+	}
+	u.Metadata.PlanMetadata = plan
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to revoke lifetime pro", zap.Int64("userId", userID), zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "plan.update_failed")
+	}
+
+	s.record(ctx, &PlanEvent{
+		UserID:    u.ID,
+		Type:      EventRevoked,
+		FromPlan:  from,
+		ToPlan:    u.Metadata.PlanType,
+		ProSource: source,
+		ActorID:   &actorID,
+	})
+	return u, nil
+}
+
+// SubscriptionChange is a billing event already mapped to the plan model.
+type SubscriptionChange struct {
+	Event     EventType
+	PlanType  user.PlanType
+	ExpiresAt *time.Time
+
+	// Reference identifies the subscription at the payment provider.
+	Reference string
+}
+
+// ApplySubscription reflects a subscription event on the user's plan. Active
+// subscriptions set the plan until the end of the paid period; past-due ones
+// keep it until then, leaving the downgrade to the lifecycle job; cancelled
+// ones return to FREE right away, unless the plan did not come from a
+// subscription (e.g. granted by an admin).
+func (s *Service) ApplySubscription(ctx context.Context, userID int64, change SubscriptionChange) (*user.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	from, fromSource := u.Metadata.PlanType, u.Metadata.ProSource
+	subscription := user.ProSourceSubscription
+	fromSubscription := fromSource != nil && *fromSource == subscription
+
+	switch change.Event {
+	case EventSubscriptionStarted, EventSubscriptionRenewed:
+		plan, err := s.catalog.Entitlements(change.PlanType, u.Metadata.PlanOverrides)
+		if err != nil {
+			return nil, err
+		}
+		u.Metadata.PlanMetadata = plan
+		u.Metadata.ProSource = &subscription
+		u.Metadata.PlanExpirationDate = change.ExpiresAt
+
+	case EventSubscriptionCancelled:
+		if !fromSubscription {
+			s.logger.Info("Subscription cancelled for a plan not managed by billing, keeping plan",
+				zap.Int64("userId", u.ID), zap.String("plan", string(from)))
+			return u, nil
+		}
+		plan, err := s.catalog.Entitlements(user.PlanTypeFree, u.Metadata.PlanOverrides)
+		if err != nil {
+			return nil, err
+		}
+		u.Metadata.PlanMetadata = plan
+
+	case EventSubscriptionPastDue:
+		// Nothing changes until the paid period ends.
+
+	default:
+		return nil, errors.New(errors.EINVALID, "plan.invalid_subscription_event", change.Event)
+	}
+
+	if change.Event != EventSubscriptionPastDue {
+		if err := s.userRepo.Update(ctx, u); err != nil {
+			s.logger.Error("Failed to apply subscription change", zap.Int64("userId", u.ID), zap.Error(err))
+			return nil, errors.New(errors.EINTERNAL, "plan.update_failed")
+		}
+	}
+
+	event := &PlanEvent{
+		UserID:    u.ID,
+		Type:      change.Event,
+		FromPlan:  from,
+		ToPlan:    u.Metadata.PlanType,
+		ProSource: u.Metadata.ProSource,
+		ExpiresAt: u.Metadata.PlanExpirationDate,
+	}
+	if change.Reference != "" {
+		event.Reason = &change.Reference
+	}
+	s.record(ctx, event)
+
+	s.logger.Info("Subscription change applied",
+		zap.Int64("userId", u.ID),
+		zap.String("event", string(change.Event)),
+		zap.String("from", string(from)),
+		zap.String("to", string(u.Metadata.PlanType)),
+	)
+	return u, nil
+}
+
+func (s *Service) FindEvents(ctx context.Context, userID int64, page, size int) ([]PlanEvent, int64, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, 0, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	events, total, err := s.repo.FindByUserID(ctx, userID, page, size)
+	if err != nil {
+		return nil, 0, errors.New(errors.EINTERNAL, "plan.events_failed")
+	}
+	return events, total, nil
+}
+
+// Run is the scheduled step: it downgrades expired plans and sends the
+// reminders for plans about to expire.
+func (s *Service) Run(ctx context.Context) error {
+	if _, err := s.DowngradeExpired(ctx); err != nil {
+		return err
+	}
+	_, err := s.SendReminders(ctx)
+	return err
+}
+
+// DowngradeExpired moves users whose plan expired back to FREE, resetting
+// limits and features to the FREE defaults.
+func (s *Service) DowngradeExpired(ctx context.Context) (int, error) {
+	now := utils.Now()
+	users, err := s.repo.FindExpired(ctx, now, s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	downgraded := 0
+	for i := range users {
+		done, err := s.downgrade(ctx, &users[i], now)
+		if err != nil {
+			s.logger.Error("Failed to downgrade expired plan", zap.Int64("userId", users[i].ID), zap.Error(err))
+			continue
+		}
+		if done {
+			downgraded++
+		}
+	}
+
+	if downgraded > 0 {
+		s.logger.Info("Expired plans downgraded", zap.Int("count", downgraded))
+	}
+	return downgraded, nil
+}
+
+// SendReminders notifies users whose plan expires within the reminder
+// window. Each expiration date is reminded once.
+func (s *Service) SendReminders(ctx context.Context) (int, error) {
+	now := utils.Now()
+	users, err := s.repo.FindPendingReminders(ctx, now, now.AddDate(0, 0, s.reminderDays), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range users {
+		u := &users[i]
+		if err := s.sendReminderEmail(ctx, u); err != nil {
+			s.logger.Error("Failed to send plan expiration reminder", zap.Int64("userId", u.ID), zap.Error(err))
+			continue
+		}
+
+		s.record(ctx, &PlanEvent{
+			UserID:    u.ID,
+			Type:      EventReminderSent,
+			FromPlan:  u.Metadata.PlanType,
+			ToPlan:    u.Metadata.PlanType,
+			ProSource: u.Metadata.ProSource,
+			ExpiresAt: u.Metadata.PlanExpirationDate,
+		})
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info("Plan expiration reminders sent", zap.Int("count", sent))
+	}
+	return sent, nil
+}
+
+// downgrade reports whether the plan was moved to FREE; users renewed since
+// the query are left as they are.
+func (s *Service) downgrade(ctx context.Context, u *user.User, now time.Time) (bool, error) {
+	// Reload so a renewal that happened after the query is not undone.
+	current, err := s.userRepo.GetByID(ctx, u.ID)
+	if err != nil {
+		return false, err
+	}
+	expiresAt := current.Metadata.PlanExpirationDate
+	if current.Metadata.PlanType == user.PlanTypeFree || expiresAt == nil || expiresAt.After(now) {
+		return false, nil
+	}
+
+	from, source := current.Metadata.PlanType, current.Metadata.ProSource
+
+	plan, err := s.catalog.Entitlements(user.PlanTypeFree, current.Metadata.PlanOverrides)
+	if err != nil {
+		return false, err
+	}
+	current.Metadata.PlanMetadata = plan
+	if err := s.userRepo.Update(ctx, current); err != nil {
+		return false, err
+	}
+
+	s.record(ctx, &PlanEvent{
+		UserID:    current.ID,
+		Type:      EventExpired,
+		FromPlan:  from,
+		ToPlan:    user.PlanTypeFree,
+		ProSource: source,
+		ExpiresAt: expiresAt,
+	})
+
+	if err := s.sendExpiredEmail(ctx, current, from, source); err != nil {
+		s.logger.Error("Failed to send plan expired email", zap.Int64("userId", current.ID), zap.Error(err))
+	}
+
+	s.logger.Info("Plan expired", zap.Int64("userId", current.ID), zap.String("from", string(from)))
+	return true, nil
+}
+
+func (s *Service) record(ctx context.Context, event *PlanEvent) {
+	event.CreatedAt = utils.Now()
+	if err := s.repo.Create(ctx, event); err != nil {
+		s.logger.Error("Failed to record plan event",
+			zap.Int64("userId", event.UserID),
+			zap.String("type", string(event.Type)),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Service) sendReminderEmail(ctx context.Context, u *user.User) error {
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "plan_reminder", map[string]any{
+		"Name":      u.Name,
+		"Plan":      u.Metadata.PlanType,
+		"ExpiresAt": *u.Metadata.PlanExpirationDate,
+		"Trial":     isTrial(u.Metadata.ProSource),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, u.Email, subject, body)
+}
+
+func (s *Service) sendExpiredEmail(ctx context.Context, u *user.User, from user.PlanType, source *user.ProSource) error {
+	subject, body, err := email.Render(u.Metadata.Locale, u.Metadata.Timezone, "plan_expired", map[string]any{
+		"Name":  u.Name,
+		"Plan":  from,
+		"Trial": isTrial(source),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, u.Email, subject, body)
+}
+
+func isTrial(source *user.ProSource) bool {
+	return source != nil && *source == user.ProSourceTrial
+}
@@ -0,0 +1,83 @@
+package preferences
+
+import (
+	"context"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Update holds the preferences to change; nil fields are left untouched.
+type Update struct {
+	Locale           *string
+	Currency         *string
+	Timezone         *string
+	PlanReminders    *bool
+	MarketingConsent *bool
+}
+
+type Service struct {
+	userRepo user.UserService
+	logger   logger.Logger
+}
+
+func NewService(userRepo user.UserService, logger logger.Logger) *Service {
+	return &Service{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+func (s *Service) Get(ctx context.Context, userID int64) (*user.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+	return u, nil
+}
+
+// Update validates and applies the given preferences. Nothing is saved when
+// any value is invalid.
+func (s *Service) Update(ctx context.Context, userID int64, update Update) (*user.User, error) {
+	u, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &u.Metadata
+	if update.Locale != nil {
+		if meta.Locale, err = NormalizeLocale(*update.Locale); err != nil {
+			return nil, err
+		}
+	}
+	if update.Currency != nil {
+		if meta.Currency, err = NormalizeCurrency(*update.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if update.Timezone != nil {
+		if meta.Timezone, err = NormalizeTimezone(*update.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	if update.PlanReminders != nil {
+		meta.Notifications.PlanReminders = *update.PlanReminders
+	}
+
+	// The consent date is kept as proof and only moves when the answer changes
+	if update.MarketingConsent != nil && *update.MarketingConsent != meta.MarketingConsent {
+		now := utils.Now()
+		meta.MarketingConsent = *update.MarketingConsent
+		meta.MarketingConsentAt = &now
+	}
+
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User preferences updated", zap.Int64("userId", u.ID))
+	return u, nil
+}
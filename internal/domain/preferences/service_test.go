@@ -0,0 +1,82 @@
+package preferences
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+)
+
+type fakeUserRepo struct {
+	user.UserService
+	user    user.User
+	updates int
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id int64) (*user.User, error) {
+	u := r.user
+	return &u, nil
+}
+
+func (r *fakeUserRepo) Update(_ context.Context, u *user.User) error {
+	r.updates++
+	r.user = *u
+	return nil
+}
+
+func TestUpdate(t *testing.T) {
+	str := func(v string) *string { return &v }
+	boolean := func(v bool) *bool { return &v }
+	consentAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		update        Update
+		wantErr       bool
+		wantLocale    string
+		wantCurrency  string
+		wantConsentAt bool // whether the consent date moved
+	}{
+		{"normalizes values", Update{Locale: str("en-US"), Currency: str("usd"), Timezone: str("UTC")}, false, "en", "USD", false},
+		{"invalid value saves nothing", Update{Locale: str("en"), Currency: str("nope")}, true, "pt-BR", "BRL", false},
+		{"consent change moves the date", Update{MarketingConsent: boolean(false)}, false, "pt-BR", "BRL", true},
+		{"same consent keeps the date", Update{MarketingConsent: boolean(true)}, false, "pt-BR", "BRL", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{}
+			repo.user.ID = 1
+			repo.user.Metadata.Locale = "pt-BR"
+			repo.user.Metadata.Currency = "BRL"
+			repo.user.Metadata.MarketingConsent = true
+			repo.user.Metadata.MarketingConsentAt = &consentAt
+
+			log, _ := logger.NewLogger("test", "none")
+			_, err := NewService(repo, log).Update(context.Background(), 1, tt.update)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Update() error = nil, want an error")
+				}
+				if repo.updates != 0 {
+					t.Fatalf("saved %d times, want 0", repo.updates)
+				}
+			} else if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			meta := repo.user.Metadata
+			if meta.Locale != tt.wantLocale {
+				t.Errorf("Locale = %q, want %q", meta.Locale, tt.wantLocale)
+			}
+			if meta.Currency != tt.wantCurrency {
+				t.Errorf("Currency = %q, want %q", meta.Currency, tt.wantCurrency)
+			}
+			if moved := !meta.MarketingConsentAt.Equal(consentAt); moved != tt.wantConsentAt {
+				t.Errorf("consent date moved = %v, want %v", moved, tt.wantConsentAt)
+			}
+		})
+	}
+}
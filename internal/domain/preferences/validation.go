@@ -0,0 +1,54 @@
+package preferences
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
+	"golang.org/x/text/currency"
+)
+
+// NormalizeLocale maps a BCP-47 tag to a supported locale ("en-US" becomes
+// "en"). Lists and weights, as sent in Accept-Language, are not accepted.
+func NormalizeLocale(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, ",;") {
+		return "", invalidLocale(value)
+	}
+
+	locale := i18n.Match(value)
+	if locale == "" {
+		return "", invalidLocale(value)
+	}
+	return locale, nil
+}
+
+func invalidLocale(value string) error {
+	return errors.New(errors.EINVALID, "preferences.invalid_locale", value, strings.Join(i18n.Supported(), ", "))
+}
+
+// NormalizeCurrency validates an ISO 4217 code and returns it upper-cased.
+func NormalizeCurrency(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	unit, err := currency.ParseISO(value)
+	if err != nil || unit == (currency.Unit{}) {
+		return "", errors.New(errors.EINVALID, "preferences.invalid_currency", value)
+	}
+	return unit.String(), nil
+}
+
+// NormalizeTimezone validates an IANA time zone name. "Local" is rejected
+// since it depends on the server configuration.
+func NormalizeTimezone(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "Local" {
+		return "", errors.New(errors.EINVALID, "preferences.invalid_timezone", value)
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return "", errors.New(errors.EINVALID, "preferences.invalid_timezone", value)
+	}
+	return loc.String(), nil
+}
@@ -0,0 +1,91 @@
+package preferences
+
+import (
+	"testing"
+
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"pt-BR", "pt-BR"},
+		{"en-US", "en"},
+		{" es ", "es"},
+		{"pt_br", "pt-BR"},
+		{"pt", "pt-BR"},
+		{"fr", ""},
+		{"", ""},
+		{"en,es", ""},
+		{"en;q=0.9", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeLocale(tt.value)
+			checkNormalized(t, got, err, tt.want, "preferences.invalid_locale")
+		})
+	}
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"BRL", "BRL"},
+		{"usd", "USD"},
+		{" eur ", "EUR"},
+		{"XXX", ""},
+		{"ABC", ""},
+		{"US", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeCurrency(tt.value)
+			checkNormalized(t, got, err, tt.want, "preferences.invalid_currency")
+		})
+	}
+}
+
+func TestNormalizeTimezone(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"America/Sao_Paulo", "America/Sao_Paulo"},
+		{" Europe/Madrid ", "Europe/Madrid"},
+		{"UTC", "UTC"},
+		{"Local", ""},
+		{"Mars/Olympus", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeTimezone(tt.value)
+			checkNormalized(t, got, err, tt.want, "preferences.invalid_timezone")
+		})
+	}
+}
+
+// checkNormalized expects want, or the errKey error when want is empty.
+func checkNormalized(t *testing.T, got string, err error, want, errKey string) {
+	t.Helper()
+	if want == "" {
+		if err == nil || errors.ErrorKey(err) != errKey {
+			t.Fatalf("got %q, %v; want %s", got, err, errKey)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindAllRoles(ctx context.Context) ([]Role, error)
+
+	FindRolesByNames(ctx context.Context, names []string) ([]Role, error)
+
+	FindRolesByUserID(ctx context.Context, userID int64) ([]Role, error)
+
+	SetUserRoles(ctx context.Context, userID int64, roleIDs []int64, admin bool) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) FindAllRoles(ctx context.Context) ([]Role, error) {
+	var roles []Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *GormRepository) FindRolesByNames(ctx context.Context, names []string) ([]Role, error) {
+	var roles []Role
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *GormRepository) FindRolesByUserID(ctx context.Context, userID int64) ([]Role, error) {
+	var roles []Role
+	if err := r.db.WithContext(ctx).
+		Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.id").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *GormRepository) SetUserRoles(ctx context.Context, userID int64, roleIDs []int64, admin bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&UserRole{}).Error; err != nil {
+			return err
+		}
+
+		now := utils.Now()
+		userRoles := make([]UserRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			userRoles = append(userRoles, UserRole{UserID: userID, RoleID: roleID, CreatedAt: now})
+		}
+
+		if len(userRoles) > 0 {
+			if err := tx.Create(&userRoles).Error; err != nil {
+				return err
+			}
+		}
+
+		// The roles are part of the user, so they follow its If-Match version
+		query := tx.Table("users").Where("id = ?", userID)
+		expected, checked := user.ExpectedVersionFromContext(ctx, userID)
+		if checked {
+			query = query.Where("version = ?", expected)
+		}
+
+		result := query.Updates(map[string]interface{}{
+			"admin":   admin,
+			"version": gorm.Expr("version + 1"),
+		})
+		if result.Error == nil && result.RowsAffected == 0 && checked {
+			return user.ErrVersionConflict
+		}
+		return result.Error
+	})
+}
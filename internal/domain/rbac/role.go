@@ -0,0 +1,61 @@
+package rbac
+
+import (
+	"time"
+)
+
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleBilling = "billing"
+	RoleAdmin   = "admin"
+)
+
+const (
+	PermUsersRead        = "users:read"
+	PermUsersCreate      = "users:create"
+	PermUsersUpdate      = "users:update"
+	PermUsersDelete      = "users:delete"
+	PermUsersStatus      = "users:status"
+	PermUsersCredentials = "users:credentials"
+	PermUsersPlan        = "users:plan"
+	PermUsersImport      = "users:import"
+	PermUsersExport      = "users:export"
+	PermRolesManage      = "roles:manage"
+	PermPlansManage      = "plans:manage"
+	PermSecurityRead     = "security:read"
+	PermSecurityManage   = "security:manage"
+)
+
+type Role struct {
+	ID          int64        `gorm:"primaryKey"`
+	Name        string       `gorm:"uniqueIndex;not null"`
+	Description string       `gorm:"not null;default:''"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time    `gorm:"not null"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+type Permission struct {
+	ID          int64     `gorm:"primaryKey"`
+	Name        string    `gorm:"uniqueIndex;not null"`
+	Description string    `gorm:"not null;default:''"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+type UserRole struct {
+	UserID    int64     `gorm:"primaryKey"`
+	RoleID    int64     `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,141 @@
+package rbac
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type Service struct {
+	repo     Repository
+	userRepo user.UserService
+}
+
+func NewService(repo Repository, userRepo user.UserService) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *Service) ListRoles(ctx context.Context) ([]Role, error) {
+	roles, err := s.repo.FindAllRoles(ctx)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "role.list_failed")
+	}
+	return roles, nil
+}
+
+func (s *Service) GetUserRoles(ctx context.Context, userID int64) ([]Role, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	return s.rolesFor(ctx, u)
+}
+
+func (s *Service) rolesFor(ctx context.Context, u *user.User) ([]Role, error) {
+	roles, err := s.repo.FindRolesByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "role.user_roles_failed")
+	}
+
+	if len(roles) > 0 {
+		return roles, nil
+	}
+
+	// Users without explicit assignments fall back to the legacy admin flag.
+	names := []string{RoleUser}
+	if u.Admin {
+		names = append(names, RoleAdmin)
+	}
+
+	roles, err = s.repo.FindRolesByNames(ctx, names)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "role.user_roles_failed")
+	}
+
+	return roles, nil
+}
+
+func (s *Service) ResolveAuthorization(ctx context.Context, u *user.User) ([]string, []string, error) {
+	roles, err := s.rolesFor(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roleNames := make([]string, 0, len(roles))
+	seen := make(map[string]bool)
+	permissions := make([]string, 0)
+
+	for _, role := range roles {
+		roleNames = append(roleNames, strings.ToUpper(role.Name))
+		for _, perm := range role.Permissions {
+			if !seen[perm.Name] {
+				seen[perm.Name] = true
+				permissions = append(permissions, perm.Name)
+			}
+		}
+	}
+
+	sort.Strings(permissions)
+
+	return roleNames, permissions, nil
+}
+
+func (s *Service) AssignRoles(ctx context.Context, userID, currentUserID int64, names []string) ([]Role, error) {
+	if len(names) == 0 {
+		return nil, errors.New(errors.EINVALID, "role.required")
+	}
+
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(name)))
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+	if err := user.CheckVersion(ctx, u); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.repo.FindRolesByNames(ctx, normalized)
+	if err != nil {
+		return nil, errors.New(errors.EINTERNAL, "role.load_failed")
+	}
+
+	found := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		found[role.Name] = true
+	}
+	for _, name := range normalized {
+		if !found[name] {
+			return nil, errors.New(errors.EINVALID, "role.invalid", name)
+		}
+	}
+
+	admin := found[RoleAdmin]
+	if userID == currentUserID && u.Admin && !admin {
+		return nil, errors.New(errors.EINVALID, "role.cannot_remove_own_admin")
+	}
+
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	if err := s.repo.SetUserRoles(ctx, userID, roleIDs, admin); err != nil {
+		if user.IsVersionConflict(err) {
+			return nil, err
+		}
+		return nil, errors.New(errors.EINTERNAL, "role.assign_failed")
+	}
+
+	return s.rolesFor(ctx, u)
+}
@@ -0,0 +1,127 @@
+package security
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type ActivityType string
+
+const (
+	ActivityRateLimitExceeded   ActivityType = "RATE_LIMIT_EXCEEDED"
+	ActivityMassCreation        ActivityType = "MASS_CREATION"
+	ActivityPatternAbuse        ActivityType = "PATTERN_ABUSE"
+	ActivityInvalidDataAttempts ActivityType = "INVALID_DATA_ATTEMPTS"
+	ActivityUnauthorizedAccess  ActivityType = "UNAUTHORIZED_ACCESS"
+	ActivitySuspiciousPattern   ActivityType = "SUSPICIOUS_PATTERN"
+	ActivityAutomatedBehavior   ActivityType = "AUTOMATED_BEHAVIOR"
+)
+
+func (t ActivityType) IsValid() bool {
+	_, ok := DefaultSeverity[t]
+	return ok
+}
+
+type Severity string
+
+const (
+	SeverityLow      Severity = "LOW"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityHigh     Severity = "HIGH"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+func (s Severity) IsValid() bool {
+	switch s {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+// DefaultSeverity is used when an activity is reported without one.
+var DefaultSeverity = map[ActivityType]Severity{
+	ActivityRateLimitExceeded:   SeverityHigh,
+	ActivityMassCreation:        SeverityHigh,
+	ActivityPatternAbuse:        SeverityCritical,
+	ActivityInvalidDataAttempts: SeverityLow,
+	ActivityUnauthorizedAccess:  SeverityMedium,
+	ActivitySuspiciousPattern:   SeverityMedium,
+	ActivityAutomatedBehavior:   SeverityHigh,
+}
+
+type Details map[string]any
+
+func (d *Details) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+func (d Details) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+// SuspiciousActivity is one occurrence of a suspicious behaviour. Repeated
+// occurrences of the same type on the same endpoint within the detection
+// window are folded into RequestCount.
+type SuspiciousActivity struct {
+	ID           int64        `gorm:"primaryKey;autoIncrement"`
+	UserID       int64        `gorm:"not null;index"`
+	ActivityType ActivityType `gorm:"column:activity_type;size:50;not null"`
+	Endpoint     string       `gorm:"size:500;not null"`
+	IPAddress    *string      `gorm:"column:ip_address;size:45"`
+	UserAgent    *string      `gorm:"column:user_agent"`
+	RequestCount int          `gorm:"not null;default:1"`
+	Details      Details      `gorm:"type:jsonb"`
+	Severity     Severity     `gorm:"size:20;not null"`
+	CreatedAt    time.Time    `gorm:"not null"`
+}
+
+func (SuspiciousActivity) TableName() string {
+	return "suspicious_activities"
+}
+
+// Block keeps a user out of the API until BlockedUntil, or until an admin
+// lifts it when BlockedUntil is nil. A user has at most one block with
+// UnblockedAt unset. BlockedBy is nil for automatic blocks.
+type Block struct {
+	ID                      int64      `gorm:"primaryKey;autoIncrement"`
+	UserID                  int64      `gorm:"not null;index"`
+	Reason                  string     `gorm:"size:500;not null"`
+	SuspiciousActivityCount int        `gorm:"not null;default:0"`
+	BlockedAt               time.Time  `gorm:"not null"`
+	BlockedUntil            *time.Time `gorm:"column:blocked_until"`
+	BlockedBy               *int64     `gorm:"column:blocked_by"`
+	UnblockedAt             *time.Time `gorm:"column:unblocked_at"`
+	UnblockedBy             *int64     `gorm:"column:unblocked_by"`
+	Notes                   *string    `gorm:"column:notes"`
+}
+
+func (Block) TableName() string {
+	return "user_security_blocks"
+}
+
+// ActiveAt reports whether the block still applies at now.
+func (b *Block) ActiveAt(now time.Time) bool {
+	return b.UnblockedAt == nil && (b.BlockedUntil == nil || b.BlockedUntil.After(now))
+}
+
+// ActivityCounts summarizes activities per severity.
+type ActivityCounts struct {
+	Low      int64
+	Medium   int64
+	High     int64
+	Critical int64
+	Total    int64
+}
@@ -0,0 +1,100 @@
+package security
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const maxReasonLength = 500
+
+func (s *Service) FindActivities(ctx context.Context, filter ActivityFilter, page, size int) ([]SuspiciousActivity, int64, error) {
+	return s.repo.FindActivities(ctx, filter, page, size)
+}
+
+// CountActivities counts the activities matching the filter per severity.
+func (s *Service) CountActivities(ctx context.Context, filter ActivityFilter) (ActivityCounts, error) {
+	return s.repo.CountActivities(ctx, filter)
+}
+
+func (s *Service) FindBlocks(ctx context.Context, userID int64, page, size int) ([]Block, int64, error) {
+	return s.repo.FindBlocks(ctx, userID, page, size)
+}
+
+// BlockUser blocks the user on behalf of an admin, permanently when until is
+// nil. Unlike the automatic rules, admins can be blocked this way.
+func (s *Service) BlockUser(ctx context.Context, userID, actorID int64, reason string, until *time.Time) (*Block, error) {
+	if userID == actorID {
+		return nil, errors.New(errors.EINVALID, "security.cannot_block_self")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New(errors.EINVALID, "security.reason_required")
+	}
+	if len(reason) > maxReasonLength {
+		return nil, errors.New(errors.EINVALID, "security.reason_too_long", maxReasonLength)
+	}
+
+	now := utils.Now()
+	if until != nil {
+		if !until.After(now) {
+			return nil, errors.New(errors.EINVALID, "security.invalid_blocked_until")
+		}
+		utc := until.UTC()
+		until = &utc
+	}
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "user.not_found")
+	}
+
+	counts, err := s.recentCounts(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &Block{
+		Reason:                  reason,
+		SuspiciousActivityCount: int(counts.Total),
+		BlockedAt:               now,
+		BlockedUntil:            until,
+		BlockedBy:               &actorID,
+	}
+	created, err := s.block(ctx, u, block)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New(errors.ECONFLICT, "security.already_blocked")
+	}
+
+	s.logger.Info("User blocked by admin", zap.Int64("userId", userID), zap.Int64("actorId", actorID))
+	return block, nil
+}
+
+// UnblockUser lifts the active block of the user. Other instances stop
+// rejecting the user on their next Refresh.
+func (s *Service) UnblockUser(ctx context.Context, userID, actorID int64, notes *string) (*Block, error) {
+	block, err := s.repo.Unblock(ctx, userID, actorID, notes, utils.Now())
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New(errors.ENOTFOUND, "security.block_not_found")
+	}
+
+	s.mu.Lock()
+	delete(s.blocked, userID)
+	s.mu.Unlock()
+
+	s.restoreReputation(ctx, userID)
+
+	s.logger.Info("User unblocked by admin", zap.Int64("userId", userID), zap.Int64("actorId", actorID))
+	return block, nil
+}
@@ -0,0 +1,36 @@
+package security
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ActivityFilter holds the suspicious activity list criteria. Nil/empty
+// fields are not applied.
+type ActivityFilter struct {
+	UserID       *int64
+	ActivityType ActivityType
+	Severity     Severity
+	From         *time.Time
+	To           *time.Time
+}
+
+func applyActivityFilter(query *gorm.DB, filter ActivityFilter) *gorm.DB {
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.ActivityType != "" {
+		query = query.Where("activity_type = ?", filter.ActivityType)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
@@ -0,0 +1,191 @@
+package security
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	// IncrementRecent folds count more occurrences into the latest activity
+	// of the same type and endpoint created since the given time. It returns
+	// nil when there is no such activity.
+	IncrementRecent(ctx context.Context, userID int64, activityType ActivityType, endpoint string, count int, since time.Time) (*SuspiciousActivity, error)
+	CreateActivity(ctx context.Context, activity *SuspiciousActivity) error
+	FindActivities(ctx context.Context, filter ActivityFilter, page, size int) ([]SuspiciousActivity, int64, error)
+	CountActivities(ctx context.Context, filter ActivityFilter) (ActivityCounts, error)
+
+	FindActiveBlock(ctx context.Context, userID int64, now time.Time) (*Block, error)
+	FindActiveBlocks(ctx context.Context, now time.Time) ([]Block, error)
+	FindBlocks(ctx context.Context, userID int64, page, size int) ([]Block, int64, error)
+	// CreateBlock closes the user's expired block, if any, and stores the new
+	// one. It returns false when the user already has an active block.
+	CreateBlock(ctx context.Context, block *Block) (bool, error)
+	// ReleaseExpired closes the temporary blocks that ended before now and
+	// returns the users they belonged to.
+	ReleaseExpired(ctx context.Context, now time.Time) ([]int64, error)
+	// Unblock closes the user's active block and returns it, or nil when the
+	// user is not blocked.
+	Unblock(ctx context.Context, userID, unblockedBy int64, notes *string, now time.Time) (*Block, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) IncrementRecent(ctx context.Context, userID int64, activityType ActivityType, endpoint string, count int, since time.Time) (*SuspiciousActivity, error) {
+	latest := r.db.Model(&SuspiciousActivity{}).
+		Select("id").
+		Where("user_id = ? AND activity_type = ? AND endpoint = ? AND created_at >= ?", userID, activityType, endpoint, since).
+		Order("created_at DESC").
+		Limit(1)
+
+	var activity SuspiciousActivity
+	result := r.db.WithContext(ctx).Model(&activity).
+		Clauses(clause.Returning{}).
+		Where("id = (?)", latest).
+		UpdateColumn("request_count", gorm.Expr("request_count + ?", count))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &activity, nil
+}
+
+func (r *GormRepository) CreateActivity(ctx context.Context, activity *SuspiciousActivity) error {
+	return r.db.WithContext(ctx).Create(activity).Error
+}
+
+func (r *GormRepository) FindActivities(ctx context.Context, filter ActivityFilter, page, size int) ([]SuspiciousActivity, int64, error) {
+	var activities []SuspiciousActivity
+	var total int64
+
+	offset := (page - 1) * size
+	query := applyActivityFilter(r.db.WithContext(ctx).Model(&SuspiciousActivity{}), filter)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(size).Find(&activities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return activities, total, nil
+}
+
+func (r *GormRepository) CountActivities(ctx context.Context, filter ActivityFilter) (ActivityCounts, error) {
+	var counts ActivityCounts
+	err := applyActivityFilter(r.db.WithContext(ctx).Model(&SuspiciousActivity{}), filter).
+		Select(`COUNT(*) FILTER (WHERE severity = ?) AS low,
+			COUNT(*) FILTER (WHERE severity = ?) AS medium,
+			COUNT(*) FILTER (WHERE severity = ?) AS high,
+			COUNT(*) FILTER (WHERE severity = ?) AS critical,
+			COUNT(*) AS total`, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical).
+		Scan(&counts).Error
+	return counts, err
+}
+
+const activeBlock = "unblocked_at IS NULL AND (blocked_until IS NULL OR blocked_until > ?)"
+
+func (r *GormRepository) FindActiveBlock(ctx context.Context, userID int64, now time.Time) (*Block, error) {
+	var block Block
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where(activeBlock, now).
+		First(&block).Error
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (r *GormRepository) FindActiveBlocks(ctx context.Context, now time.Time) ([]Block, error) {
+	var blocks []Block
+	err := r.db.WithContext(ctx).Where(activeBlock, now).Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *GormRepository) FindBlocks(ctx context.Context, userID int64, page, size int) ([]Block, int64, error) {
+	var blocks []Block
+	var total int64
+
+	offset := (page - 1) * size
+	query := r.db.WithContext(ctx).Model(&Block{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("blocked_at DESC, id DESC").Offset(offset).Limit(size).Find(&blocks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return blocks, total, nil
+}
+
+func (r *GormRepository) CreateBlock(ctx context.Context, block *Block) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Block{}).
+			Where("user_id = ? AND unblocked_at IS NULL AND blocked_until <= ?", block.UserID, block.BlockedAt).
+			UpdateColumn("unblocked_at", gorm.Expr("blocked_until")).Error; err != nil {
+			return err
+		}
+
+		// The partial unique index allows a single open block per user
+		result := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "user_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "unblocked_at IS NULL"}}},
+			DoNothing:   true,
+		}).Create(block)
+		created = result.RowsAffected > 0
+		return result.Error
+	})
+	return created, err
+}
+
+func (r *GormRepository) ReleaseExpired(ctx context.Context, now time.Time) ([]int64, error) {
+	var released []Block
+	err := r.db.WithContext(ctx).Model(&released).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "user_id"}}}).
+		Where("unblocked_at IS NULL AND blocked_until <= ?", now).
+		UpdateColumn("unblocked_at", gorm.Expr("blocked_until")).Error
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, len(released))
+	for i, block := range released {
+		userIDs[i] = block.UserID
+	}
+	return userIDs, nil
+}
+
+func (r *GormRepository) Unblock(ctx context.Context, userID, unblockedBy int64, notes *string, now time.Time) (*Block, error) {
+	var block Block
+	result := r.db.WithContext(ctx).Model(&block).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where(activeBlock, now).
+		Updates(map[string]interface{}{
+			"unblocked_at": now,
+			"unblocked_by": unblockedBy,
+			"notes":        notes,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &block, nil
+}
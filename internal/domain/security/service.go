@@ -0,0 +1,471 @@
+package security
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SessionStore is the subset of the auth repository needed to sign a
+// blocked user out.
+type SessionStore interface {
+	RevokeAllUserRefreshTokens(ctx context.Context, userID int64) error
+}
+
+// Rules are the auto-block thresholds. A user is blocked for BlockDuration
+// once any count is reached within Window; zero disables a threshold.
+type Rules struct {
+	CriticalCount int
+	HighCount     int
+	TotalCount    int
+	Window        time.Duration
+	BlockDuration time.Duration
+}
+
+func (r Rules) reason(counts ActivityCounts) string {
+	hours := int(r.Window.Hours())
+	switch {
+	case r.CriticalCount > 0 && counts.Critical >= int64(r.CriticalCount):
+		return fmt.Sprintf("Automatic: %d critical activities in %dh", counts.Critical, hours)
+	case r.HighCount > 0 && counts.High >= int64(r.HighCount):
+		return fmt.Sprintf("Automatic: %d high severity activities in %dh", counts.High, hours)
+	case r.TotalCount > 0 && counts.Total >= int64(r.TotalCount):
+		return fmt.Sprintf("Automatic: %d suspicious activities in %dh", counts.Total, hours)
+	}
+	return ""
+}
+
+// ReportQueue bounds the background recording of reported activities.
+// Workers record them from a queue of Size; reports beyond it are dropped.
+// Within Window only the first report of a user and activity type is
+// queued, the repeats are folded into the next one.
+type ReportQueue struct {
+	Workers int
+	Size    int
+	Window  time.Duration
+}
+
+type reportKey struct {
+	userID       int64
+	activityType ActivityType
+}
+
+// reportState is the last queued report of a user and activity type and
+// the repeats suppressed since.
+type reportState struct {
+	activity SuspiciousActivity
+	at       time.Time
+	repeats  int
+}
+
+type Service struct {
+	repo            Repository
+	userRepo        user.UserService
+	sessions        SessionStore
+	rules           Rules
+	detectionWindow time.Duration
+	escalationCount int
+	checkInterval   time.Duration
+	queue           ReportQueue
+	logger          logger.Logger
+
+	reports  chan SuspiciousActivity
+	stop     chan struct{}
+	workers  sync.WaitGroup
+	dropped  atomic.Int64
+	reportMu sync.Mutex
+	reported map[reportKey]*reportState
+
+	// blocked caches the active blocks (user ID to end, nil when permanent)
+	// so Authenticate does not hit the database on every request. checked
+	// holds when users outside it were last confirmed not blocked.
+	mu      sync.RWMutex
+	blocked map[int64]*time.Time
+	checked map[int64]time.Time
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	sessions SessionStore,
+	rules Rules,
+	detectionWindow time.Duration,
+	escalationCount int,
+	checkInterval time.Duration,
+	queue ReportQueue,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:            repo,
+		userRepo:        userRepo,
+		sessions:        sessions,
+		rules:           rules,
+		detectionWindow: detectionWindow,
+		escalationCount: escalationCount,
+		checkInterval:   checkInterval,
+		queue:           queue,
+		logger:          logger,
+		reports:         make(chan SuspiciousActivity, queue.Size),
+		stop:            make(chan struct{}),
+		reported:        make(map[reportKey]*reportState),
+		blocked:         make(map[int64]*time.Time),
+		checked:         make(map[int64]time.Time),
+	}
+}
+
+// Report queues the activity to be recorded in the background, so the
+// request that triggered it is not slowed down. Repeats within the report
+// window are only counted, and a full queue drops the report.
+func (s *Service) Report(activity SuspiciousActivity) {
+	now := utils.Now()
+	key := reportKey{userID: activity.UserID, activityType: activity.ActivityType}
+
+	s.reportMu.Lock()
+	state, ok := s.reported[key]
+	if ok && now.Sub(state.at) < s.queue.Window {
+		state.repeats++
+		s.reportMu.Unlock()
+		return
+	}
+	activity.RequestCount = 1
+	if ok {
+		activity.RequestCount += state.repeats
+	}
+	s.reported[key] = &reportState{activity: activity, at: now}
+	s.reportMu.Unlock()
+
+	s.enqueue(activity)
+}
+
+func (s *Service) enqueue(activity SuspiciousActivity) {
+	select {
+	case s.reports <- activity:
+	default:
+		dropped := s.dropped.Add(1)
+		s.logger.Warn("Suspicious activity queue full, report dropped",
+			zap.Int64("userId", activity.UserID),
+			zap.String("type", string(activity.ActivityType)),
+			zap.Int64("dropped", dropped),
+		)
+	}
+}
+
+// Dropped returns how many reports were dropped because the queue was full.
+func (s *Service) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// flushReports queues the repeats suppressed in report windows that ended
+// and forgets those windows.
+func (s *Service) flushReports(now time.Time) {
+	var pending []SuspiciousActivity
+
+	s.reportMu.Lock()
+	for key, state := range s.reported {
+		if now.Sub(state.at) < s.queue.Window {
+			continue
+		}
+		if state.repeats > 0 {
+			activity := state.activity
+			activity.RequestCount = state.repeats
+			pending = append(pending, activity)
+		}
+		delete(s.reported, key)
+	}
+	s.reportMu.Unlock()
+
+	for _, activity := range pending {
+		s.enqueue(activity)
+	}
+}
+
+// StartWorkers starts the workers that record the queued reports.
+func (s *Service) StartWorkers() {
+	for range s.queue.Workers {
+		s.workers.Add(1)
+		go s.work()
+	}
+}
+
+// StopWorkers records the reports still queued and stops the workers.
+func (s *Service) StopWorkers(ctx context.Context) error {
+	close(s.stop)
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Service) work() {
+	defer s.workers.Done()
+
+	for {
+		select {
+		case activity := <-s.reports:
+			s.record(activity)
+		case <-s.stop:
+			for {
+				select {
+				case activity := <-s.reports:
+					s.record(activity)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Service) record(activity SuspiciousActivity) {
+	if err := s.Record(context.Background(), &activity); err != nil {
+		s.logger.Error("Failed to record suspicious activity",
+			zap.Int64("userId", activity.UserID),
+			zap.String("type", string(activity.ActivityType)),
+			zap.Error(err),
+		)
+	}
+}
+
+// Record stores the activity, updates the user's reputation and applies the
+// auto-block rules. Repeats of the same type on the same endpoint within the
+// detection window only increase the request count of the first one. The
+// activity's RequestCount, when set, is the number of occurrences it stands
+// for.
+func (s *Service) Record(ctx context.Context, activity *SuspiciousActivity) error {
+	now := utils.Now()
+	count := max(activity.RequestCount, 1)
+
+	recent, err := s.repo.IncrementRecent(ctx, activity.UserID, activity.ActivityType, activity.Endpoint, count, now.Add(-s.detectionWindow))
+	if err != nil {
+		return err
+	}
+	if recent != nil {
+		if s.escalates(recent, count) {
+			return s.escalate(ctx, activity, recent.RequestCount)
+		}
+		return nil
+	}
+
+	if activity.Severity == "" {
+		activity.Severity = DefaultSeverity[activity.ActivityType]
+	}
+	activity.RequestCount = count
+	activity.CreatedAt = now
+	if err := s.repo.CreateActivity(ctx, activity); err != nil {
+		return err
+	}
+
+	u, err := s.userRepo.RecordSuspiciousActivity(ctx, activity.UserID, now)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Warn("Suspicious activity recorded",
+		zap.Int64("userId", u.ID),
+		zap.String("type", string(activity.ActivityType)),
+		zap.String("severity", string(activity.Severity)),
+		zap.String("endpoint", activity.Endpoint),
+	)
+
+	if err := s.evaluate(ctx, u, now); err != nil {
+		return err
+	}
+
+	if s.escalates(activity, count) {
+		return s.escalate(ctx, activity, activity.RequestCount)
+	}
+	return nil
+}
+
+// escalates reports whether adding count occurrences brought a rate limit
+// activity to the escalation count: the user is still hammering the API
+// after being rate limited this many times.
+func (s *Service) escalates(activity *SuspiciousActivity, count int) bool {
+	return activity.ActivityType == ActivityRateLimitExceeded &&
+		s.escalationCount > 0 &&
+		activity.RequestCount >= s.escalationCount &&
+		activity.RequestCount-count < s.escalationCount
+}
+
+func (s *Service) escalate(ctx context.Context, activity *SuspiciousActivity, requests int) error {
+	return s.Record(ctx, &SuspiciousActivity{
+		UserID:       activity.UserID,
+		ActivityType: ActivityPatternAbuse,
+		Endpoint:     activity.Endpoint,
+		IPAddress:    activity.IPAddress,
+		UserAgent:    activity.UserAgent,
+		Details:      Details{"rateLimitedRequests": requests},
+	})
+}
+
+func (s *Service) evaluate(ctx context.Context, u *user.User, now time.Time) error {
+	// Admins are never blocked automatically, so a false positive cannot
+	// lock everyone out.
+	if u.Admin {
+		return nil
+	}
+
+	blocked, err := s.IsBlocked(ctx, u.ID)
+	if err != nil || blocked {
+		return err
+	}
+
+	counts, err := s.recentCounts(ctx, u.ID, now)
+	if err != nil {
+		return err
+	}
+
+	reason := s.rules.reason(counts)
+	if reason == "" {
+		return nil
+	}
+
+	until := now.Add(s.rules.BlockDuration)
+	_, err = s.block(ctx, u, &Block{
+		Reason:                  reason,
+		SuspiciousActivityCount: int(counts.Total),
+		BlockedAt:               now,
+		BlockedUntil:            &until,
+	})
+	return err
+}
+
+// recentCounts counts the user's activities within the auto-block window.
+func (s *Service) recentCounts(ctx context.Context, userID int64, now time.Time) (ActivityCounts, error) {
+	since := now.Add(-s.rules.Window)
+	return s.repo.CountActivities(ctx, ActivityFilter{UserID: &userID, From: &since})
+}
+
+// block stores the block and signs the user out. It returns false when the
+// user already has an active block.
+func (s *Service) block(ctx context.Context, u *user.User, b *Block) (bool, error) {
+	b.UserID = u.ID
+	created, err := s.repo.CreateBlock(ctx, b)
+	if err != nil || !created {
+		return false, err
+	}
+
+	if _, err := s.userRepo.UpdateReputationStatus(ctx, u.ID, user.ReputationStatusBlocked, ""); err != nil {
+		return false, err
+	}
+
+	if err := s.sessions.RevokeAllUserRefreshTokens(ctx, u.ID); err != nil {
+		s.logger.Error("Failed to revoke sessions of blocked user", zap.Int64("userId", u.ID), zap.Error(err))
+	}
+
+	s.mu.Lock()
+	s.blocked[u.ID] = b.BlockedUntil
+	s.mu.Unlock()
+
+	s.logger.Warn("User blocked", zap.Int64("userId", u.ID), zap.String("reason", b.Reason))
+	return true, nil
+}
+
+// IsBlocked reports whether the user has an active block. Blocks known to
+// this instance are answered from the cache; other users are looked up in
+// the database at most once per check interval, so a block created by
+// another instance applies here within that interval.
+func (s *Service) IsBlocked(ctx context.Context, userID int64) (bool, error) {
+	now := utils.Now()
+
+	s.mu.RLock()
+	until, blocked := s.blocked[userID]
+	checkedAt, checked := s.checked[userID]
+	s.mu.RUnlock()
+
+	if blocked && (until == nil || until.After(now)) {
+		return true, nil
+	}
+	if checked && now.Sub(checkedAt) < s.checkInterval {
+		return false, nil
+	}
+
+	block, err := s.repo.FindActiveBlock(ctx, userID, now)
+	if err != nil && !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if block != nil {
+		s.blocked[userID] = block.BlockedUntil
+		return true, nil
+	}
+	delete(s.blocked, userID)
+	s.checked[userID] = now
+	return false, nil
+}
+
+// EnsureNotBlocked checks the database for an active block of the user,
+// for the flows that issue new sessions.
+func (s *Service) EnsureNotBlocked(ctx context.Context, userID int64) error {
+	_, err := s.repo.FindActiveBlock(ctx, userID, utils.Now())
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return errors.New(errors.EFORBIDDEN, "security.account_blocked")
+}
+
+// Refresh lifts the temporary blocks that ended and reloads the active ones,
+// picking up the blocks created by other instances. It also queues the
+// repeats left over from ended report windows.
+func (s *Service) Refresh(ctx context.Context) error {
+	now := utils.Now()
+	s.flushReports(now)
+
+	released, err := s.repo.ReleaseExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, userID := range released {
+		s.restoreReputation(ctx, userID)
+		s.logger.Info("User block expired", zap.Int64("userId", userID))
+	}
+
+	blocks, err := s.repo.FindActiveBlocks(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[int64]*time.Time, len(blocks))
+	for _, b := range blocks {
+		blocked[b.UserID] = b.BlockedUntil
+	}
+
+	s.mu.Lock()
+	s.blocked = blocked
+	s.checked = make(map[int64]time.Time)
+	s.mu.Unlock()
+	return nil
+}
+
+// restoreReputation keeps the user under watch once a block ends or is
+// lifted.
+func (s *Service) restoreReputation(ctx context.Context, userID int64) {
+	_, err := s.userRepo.UpdateReputationStatus(ctx, userID, user.ReputationStatusSuspicious, user.ReputationStatusBlocked)
+	if err != nil {
+		s.logger.Error("Failed to restore user reputation", zap.Int64("userId", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,198 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// blockRepo serves the active blocks stored by any instance and counts the
+// lookups.
+type blockRepo struct {
+	Repository
+	blocks  map[int64]*time.Time
+	lookups int
+}
+
+func (r *blockRepo) FindActiveBlock(_ context.Context, userID int64, now time.Time) (*Block, error) {
+	r.lookups++
+	until, ok := r.blocks[userID]
+	if !ok || (until != nil && !until.After(now)) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &Block{UserID: userID, BlockedUntil: until}, nil
+}
+
+func TestIsBlocked(t *testing.T) {
+	now := utils.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		cached      map[int64]*time.Time
+		checked     map[int64]time.Time
+		stored      map[int64]*time.Time
+		want        bool
+		wantLookups int
+	}{
+		{"cached block", map[int64]*time.Time{1: &future}, nil, nil, true, 0},
+		{"cached permanent block", map[int64]*time.Time{1: nil}, nil, nil, true, 0},
+		{"block from another instance", nil, nil, map[int64]*time.Time{1: nil}, true, 1},
+		{"recently checked", nil, map[int64]time.Time{1: now}, map[int64]*time.Time{1: nil}, false, 0},
+		{"check expired", nil, map[int64]time.Time{1: now.Add(-time.Minute)}, map[int64]*time.Time{1: nil}, true, 1},
+		{"expired cached block", map[int64]*time.Time{1: &past}, nil, nil, false, 1},
+		{"not blocked", nil, nil, nil, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			repo := &blockRepo{blocks: tt.stored}
+			s := NewService(repo, nil, nil, Rules{}, time.Minute, 0, 5*time.Second, ReportQueue{}, log)
+			for id, until := range tt.cached {
+				s.blocked[id] = until
+			}
+			for id, at := range tt.checked {
+				s.checked[id] = at
+			}
+
+			got, err := s.IsBlocked(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("IsBlocked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsBlocked() = %v, want %v", got, tt.want)
+			}
+			if repo.lookups != tt.wantLookups {
+				t.Errorf("lookups = %d, want %d", repo.lookups, tt.wantLookups)
+			}
+
+			// The answer is cached either way
+			if _, err := s.IsBlocked(context.Background(), 1); err != nil {
+				t.Fatalf("IsBlocked() error = %v", err)
+			}
+			if repo.lookups > 1 {
+				t.Errorf("lookups after second call = %d, want at most 1", repo.lookups)
+			}
+		})
+	}
+}
+
+func TestEnsureNotBlocked(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  map[int64]*time.Time
+		wantErr bool
+	}{
+		{"not blocked", nil, false},
+		{"blocked", map[int64]*time.Time{1: nil}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			s := NewService(&blockRepo{blocks: tt.stored}, nil, nil, Rules{}, time.Minute, 0, 5*time.Second, ReportQueue{}, log)
+
+			err := s.EnsureNotBlocked(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EnsureNotBlocked() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// activityRepo stores the activities in memory and counts the writes.
+type activityRepo struct {
+	Repository
+	mu         sync.Mutex
+	activities []*SuspiciousActivity
+	writes     int
+}
+
+func (r *activityRepo) IncrementRecent(_ context.Context, userID int64, activityType ActivityType, endpoint string, count int, _ time.Time) (*SuspiciousActivity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.activities {
+		if a.UserID == userID && a.ActivityType == activityType && a.Endpoint == endpoint {
+			r.writes++
+			a.RequestCount += count
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *activityRepo) CreateActivity(_ context.Context, activity *SuspiciousActivity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writes++
+	r.activities = append(r.activities, activity)
+	return nil
+}
+
+// adminUserRepo returns admins, which are never blocked automatically.
+type adminUserRepo struct {
+	user.UserService
+}
+
+func (adminUserRepo) RecordSuspiciousActivity(_ context.Context, userID int64, _ time.Time) (*user.User, error) {
+	return &user.User{ID: userID, Admin: true}, nil
+}
+
+func TestReportBoundsWritesOfABurst(t *testing.T) {
+	log, _ := logger.NewLogger("test", "none")
+	repo := &activityRepo{}
+	s := NewService(repo, adminUserRepo{}, nil, Rules{}, time.Minute, 500, time.Second, ReportQueue{Workers: 2, Size: 10, Window: time.Minute}, log)
+	s.StartWorkers()
+
+	for range 1000 {
+		s.Report(SuspiciousActivity{UserID: 1, ActivityType: ActivityRateLimitExceeded, Endpoint: "GET /v1/users"})
+	}
+	// The window ends: the suppressed repeats are recorded at once
+	s.flushReports(utils.Now().Add(time.Minute))
+
+	if err := s.StopWorkers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if repo.writes != 3 {
+		t.Errorf("writes = %d, want 3", repo.writes)
+	}
+	if len(repo.activities) != 2 {
+		t.Fatalf("activities = %d, want 2", len(repo.activities))
+	}
+	if got := repo.activities[0]; got.ActivityType != ActivityRateLimitExceeded || got.RequestCount != 1000 {
+		t.Errorf("activity = %s x%d, want %s x1000", got.ActivityType, got.RequestCount, ActivityRateLimitExceeded)
+	}
+	if got := repo.activities[1]; got.ActivityType != ActivityPatternAbuse {
+		t.Errorf("escalation = %s, want %s", got.ActivityType, ActivityPatternAbuse)
+	}
+	if s.Dropped() != 0 {
+		t.Errorf("dropped = %d, want 0", s.Dropped())
+	}
+}
+
+func TestReportDropsWhenQueueIsFull(t *testing.T) {
+	log, _ := logger.NewLogger("test", "none")
+	s := NewService(&activityRepo{}, adminUserRepo{}, nil, Rules{}, time.Minute, 0, time.Second, ReportQueue{Workers: 1, Size: 2, Window: time.Minute}, log)
+
+	// Workers not started: nothing leaves the queue
+	for id := range int64(5) {
+		s.Report(SuspiciousActivity{UserID: id + 1, ActivityType: ActivityUnauthorizedAccess, Endpoint: "GET /v1/admin/users"})
+	}
+
+	if len(s.reports) != 2 {
+		t.Errorf("queued = %d, want 2", len(s.reports))
+	}
+	if s.Dropped() != 3 {
+		t.Errorf("dropped = %d, want 3", s.Dropped())
+	}
+}
@@ -61,6 +61,11 @@ func (l *LocalStorageProvider) Upload(ctx context.Context, key string, reader io
 	return "file://" + filePath, nil
 }
 
+func (l *LocalStorageProvider) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	filePath := filepath.Join(l.basePath, key)
+	return os.Open(filePath)
+}
+
 func (l *LocalStorageProvider) GetPresignedUrl(ctx context.Context, key string, duration time.Duration) (string, error) {
 	filePath := filepath.Join(l.basePath, key)
 	return "file://" + filePath, nil
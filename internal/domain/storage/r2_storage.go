@@ -109,6 +109,20 @@ func (r *R2StorageProvider) GeneratePresignedUploadUrl(ctx context.Context, key
 	return request.URL, nil
 }
 
+func (r *R2StorageProvider) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	r.logger.Debug("Downloading file from R2", zap.String("key", key))
+
+	output, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Body, nil
+}
+
 func (r *R2StorageProvider) Delete(ctx context.Context, key string) error {
 	r.logger.Debug("Deleting file from R2", zap.String("key", key))
 
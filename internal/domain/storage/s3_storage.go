@@ -112,6 +112,20 @@ func (s *S3StorageProvider) GeneratePresignedUploadUrl(ctx context.Context, key
 	return request.URL, nil
 }
 
+func (s *S3StorageProvider) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.logger.Debug("Downloading file from S3", zap.String("key", key))
+
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Body, nil
+}
+
 func (s *S3StorageProvider) Delete(ctx context.Context, key string) error {
 	s.logger.Debug("Deleting file from S3", zap.String("key", key))
 
@@ -100,3 +100,49 @@ func (s *Service) GetPresignedUploadUrl(ctx context.Context, fileName, contentTy
 		FinalUrl:  finalUrl,
 	}, nil
 }
+
+func (s *Service) FindUserFiles(ctx context.Context, userID int64) ([]FileReference, error) {
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+func (s *Service) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.provider.Download(ctx, key)
+}
+
+// Store uploads an object under a fixed key without registering a file
+// reference, for artifacts generated by the application itself.
+func (s *Service) Store(ctx context.Context, key string, reader io.Reader, contentType string, size int64) error {
+	if _, err := s.provider.Upload(ctx, key, reader, contentType, size); err != nil {
+		s.logger.Error("Failed to store object", zap.String("key", key), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Service) GetPresignedDownloadUrl(ctx context.Context, key string, duration time.Duration) (string, error) {
+	return s.provider.GetPresignedUrl(ctx, key, duration)
+}
+
+func (s *Service) DeleteObject(ctx context.Context, key string) error {
+	return s.provider.Delete(ctx, key)
+}
+
+func (s *Service) DeleteUserFiles(ctx context.Context, userID int64) error {
+	files, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := s.provider.Delete(ctx, file.StorageKey); err != nil {
+			s.logger.Error("Failed to delete file from provider", zap.String("key", file.StorageKey), zap.Error(err))
+			return err
+		}
+
+		if err := s.repo.Delete(ctx, file.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -28,6 +28,7 @@ type FileRepository interface {
 
 type StorageProvider interface {
 	Upload(ctx context.Context, key string, reader io.Reader, contentType string, size int64) (string, error)
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
 	GetPresignedUrl(ctx context.Context, key string, duration time.Duration) (string, error)
 	GeneratePresignedUploadUrl(ctx context.Context, key string, contentType string, contentLength int64, duration time.Duration) (string, error)
 	Delete(ctx context.Context, key string) error
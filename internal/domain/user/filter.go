@@ -0,0 +1,177 @@
+package user
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListFilter holds the admin user list criteria. Nil/empty fields are not
+// applied.
+type ListFilter struct {
+	Keyword          string
+	Active           *bool
+	Admin            *bool
+	Source           string
+	PlanType         PlanType
+	AccessMode       AccessMode
+	EmailVerified    *bool
+	ReputationStatus ReputationStatus
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+	LastAccessFrom   *time.Time
+	LastAccessTo     *time.Time
+
+	Sort SortOrder
+}
+
+type SortOrder struct {
+	Field string
+	Desc  bool
+}
+
+var DefaultSortOrder = SortOrder{Field: "id"}
+
+type sortColumn struct {
+	expr  string
+	value func(u *User) string
+}
+
+// Nullable columns are coalesced so keyset comparisons never hit NULL.
+var sortColumns = map[string]sortColumn{
+	"id":        {"id", func(u *User) string { return strconv.FormatInt(u.ID, 10) }},
+	"name":      {"name", func(u *User) string { return u.Name }},
+	"email":     {"email", func(u *User) string { return u.Email }},
+	"active":    {"active", func(u *User) string { return strconv.FormatBool(u.Active) }},
+	"admin":     {"admin", func(u *User) string { return strconv.FormatBool(u.Admin) }},
+	"source":    {"source", func(u *User) string { return u.Source }},
+	"createdAt": {"created_at", func(u *User) string { return formatCursorTime(u.CreatedAt) }},
+	"updatedAt": {"updated_at", func(u *User) string { return formatCursorTime(u.UpdatedAt) }},
+	"lastAccess": {"COALESCE(last_access, '1970-01-01'::timestamp)", func(u *User) string {
+		if u.LastAccess == nil {
+			return formatCursorTime(time.Unix(0, 0))
+		}
+		return formatCursorTime(*u.LastAccess)
+	}},
+	"planType":         {"COALESCE(metadata->>'plan_type', '')", func(u *User) string { return string(u.Metadata.PlanType) }},
+	"accessMode":       {"COALESCE(metadata->>'access_mode', '')", func(u *User) string { return string(u.Metadata.AccessMode) }},
+	"reputationStatus": {"COALESCE(metadata->>'reputation_status', '')", func(u *User) string { return string(u.Metadata.ReputationStatus) }},
+}
+
+func IsValidSortField(field string) bool {
+	_, ok := sortColumns[field]
+	return ok
+}
+
+// Cursor is the keyset position after the last row of a page. It is bound to
+// the sort order it was produced with.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Cursor) Matches(sort SortOrder) bool {
+	return c.Sort == sort.Field && c.Desc == sort.Desc
+}
+
+func cursorFor(u *User, sort SortOrder) *Cursor {
+	return &Cursor{
+		Sort:  sort.Field,
+		Desc:  sort.Desc,
+		Value: sortColumns[sort.Field].value(u),
+		ID:    u.ID,
+	}
+}
+
+func formatCursorTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func applyListFilter(query *gorm.DB, f ListFilter) *gorm.DB {
+	if f.Keyword != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+f.Keyword+"%", "%"+f.Keyword+"%")
+	}
+	if f.Active != nil {
+		query = query.Where("active = ?", *f.Active)
+	}
+	if f.Admin != nil {
+		query = query.Where("admin = ?", *f.Admin)
+	}
+	if f.Source != "" {
+		query = query.Where("source = ?", f.Source)
+	}
+	if f.PlanType != "" {
+		query = query.Where("metadata->>'plan_type' = ?", string(f.PlanType))
+	}
+	if f.AccessMode != "" {
+		query = query.Where("metadata->>'access_mode' = ?", string(f.AccessMode))
+	}
+	if f.EmailVerified != nil {
+		query = query.Where("metadata->>'email_verified' = ?", strconv.FormatBool(*f.EmailVerified))
+	}
+	if f.ReputationStatus != "" {
+		query = query.Where("metadata->>'reputation_status' = ?", string(f.ReputationStatus))
+	}
+	if f.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		query = query.Where("created_at < ?", *f.CreatedTo)
+	}
+	if f.LastAccessFrom != nil {
+		query = query.Where("last_access >= ?", *f.LastAccessFrom)
+	}
+	if f.LastAccessTo != nil {
+		query = query.Where("last_access < ?", *f.LastAccessTo)
+	}
+	return query
+}
+
+func applySortOrder(query *gorm.DB, sort SortOrder) *gorm.DB {
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+
+	column := sortColumns[sort.Field]
+	if sort.Field == "id" {
+		return query.Order("id " + direction)
+	}
+	return query.Order(column.expr + " " + direction).Order("id " + direction)
+}
+
+func applyCursor(query *gorm.DB, sort SortOrder, cursor *Cursor) *gorm.DB {
+	operator := ">"
+	if sort.Desc {
+		operator = "<"
+	}
+
+	if sort.Field == "id" {
+		return query.Where("id "+operator+" ?", cursor.ID)
+	}
+	column := sortColumns[sort.Field]
+	return query.Where("("+column.expr+", id) "+operator+" (?, ?)", cursor.Value, cursor.ID)
+}
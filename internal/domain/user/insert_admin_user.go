@@ -13,13 +13,15 @@ import (
 
 type InsertAdminUser struct {
 	userService UserService
+	catalog     PlanCatalog
 	config      *config.Config
 	logger      logger.Logger
 }
 
-func NewInsertAdminUser(userService UserService, cfg *config.Config, log logger.Logger) *InsertAdminUser {
+func NewInsertAdminUser(userService UserService, catalog PlanCatalog, cfg *config.Config, log logger.Logger) *InsertAdminUser {
 	return &InsertAdminUser{
 		userService: userService,
+		catalog:     catalog,
 		config:      cfg,
 		logger:      log,
 	}
@@ -47,6 +49,12 @@ func (i *InsertAdminUser) Execute(ctx context.Context) error {
 		return err
 	}
 
+	plan, err := i.catalog.Entitlements(PlanTypeFree, nil)
+	if err != nil {
+		i.logger.Error("[InsertAdminUser] Error loading FREE plan entitlements", zap.Error(err))
+		return err
+	}
+
 	now := utils.Now()
 	user := &User{
 		Name:     "Administrator",
@@ -56,7 +64,7 @@ func (i *InsertAdminUser) Execute(ctx context.Context) error {
 		Password: &hashedPassword,
 		Source:   "LOCAL",
 		Metadata: func() UserMetadata {
-			m := NewDefaultMetadata()
+			m := NewDefaultMetadata(plan)
 			m.EmailVerified = true
 			return m
 		}(),
@@ -2,9 +2,14 @@ package user
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/lkgiovani/go-boilerplate/pkg/encrypt"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormRepository struct {
@@ -19,6 +24,30 @@ func (r *GormRepository) Create(ctx context.Context, user *User) error {
 	return r.db.WithContext(ctx).Create(user).Error
 }
 
+func (r *GormRepository) CreateBatch(ctx context.Context, users []*User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(users).Error
+	})
+}
+
+// FindExistingEmails returns which of the given emails already belong to an
+// account, including soft-deleted ones. Emails are returned in lower case.
+func (r *GormRepository) FindExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	lowered := make([]string, len(emails))
+	for i, e := range emails {
+		lowered[i] = strings.ToLower(e)
+	}
+
+	var existing []string
+	if err := r.db.WithContext(ctx).Unscoped().
+		Model(&User{}).
+		Where("LOWER(email) IN ?", lowered).
+		Pluck("LOWER(email)", &existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
 func (r *GormRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
 	var u User
 	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&u).Error; err != nil {
@@ -35,16 +64,174 @@ func (r *GormRepository) GetByID(ctx context.Context, id int64) (*User, error) {
 	return &u, nil
 }
 
+// Update saves the whole user over the version it was read at, so a
+// concurrent change is reported as ErrVersionConflict instead of overwritten.
+// The last access is left out: it is only moved by UpdateLastAccess.
 func (r *GormRepository) Update(ctx context.Context, user *User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	if err := CheckVersion(ctx, user); err != nil {
+		return err
+	}
+
+	version := user.Version
+	user.Version = version + 1
+	result := r.db.WithContext(ctx).Model(user).Where("version = ?", version).Select("*").Omit("last_access").Updates(user)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		user.Version = version
+	}
+	return result.Error
+}
+
+// updateColumns applies values to the user in a single statement, bumping the
+// version, and returns the updated row. With an expected version in ctx the
+// update only applies to that version.
+func (r *GormRepository) updateColumns(ctx context.Context, id int64, values map[string]interface{}) (*User, error) {
+	query := r.db.WithContext(ctx).Clauses(clause.Returning{}).Where("id = ?", id)
+	expected, checked := ExpectedVersionFromContext(ctx, id)
+	if checked {
+		query = query.Where("version = ?", expected)
+	}
+
+	values["version"] = gorm.Expr("version + 1")
+
+	var u User
+	result := query.Model(&u).Updates(values)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if checked {
+			return nil, ErrVersionConflict
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &u, nil
+}
+
+func (r *GormRepository) Delete(ctx context.Context, id, deletedBy int64, purgeAfter time.Time) (bool, error) {
+	affected, err := r.DeleteByIDs(ctx, []int64{id}, deletedBy, purgeAfter)
+	return affected > 0, err
+}
+
+func (r *GormRepository) DeleteByIDs(ctx context.Context, ids []int64, deletedBy int64, purgeAfter time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&User{}).Where("id IN ?", ids)
+
+	var expected int64
+	checked := false
+	if len(ids) == 1 {
+		expected, checked = ExpectedVersionFromContext(ctx, ids[0])
+	}
+	if checked {
+		query = query.Where("version = ?", expected)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"deleted_at":  utils.Now(),
+		"deleted_by":  deletedBy,
+		"purge_after": purgeAfter,
+		"version":     gorm.Expr("version + 1"),
+	})
+	if result.Error == nil && result.RowsAffected == 0 && checked {
+		return 0, ErrVersionConflict
+	}
+	return result.RowsAffected, result.Error
+}
+
+func (r *GormRepository) GetDeletedByID(ctx context.Context, id int64) (*User, error) {
+	var u User
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *GormRepository) GetDeletedByEmail(ctx context.Context, email string) (*User, error) {
+	var u User
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("email = ? AND deleted_at IS NOT NULL", email).
+		First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *GormRepository) Restore(ctx context.Context, id int64) error {
+	query := r.db.WithContext(ctx).Unscoped().
+		Model(&User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id)
+	expected, checked := ExpectedVersionFromContext(ctx, id)
+	if checked {
+		query = query.Where("version = ?", expected)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"deleted_at":            nil,
+		"deleted_by":            nil,
+		"purge_after":           nil,
+		"purge_attempts":        0,
+		"last_purge_attempt_at": nil,
+		"version":               gorm.Expr("version + 1"),
+	})
+	if result.Error == nil && result.RowsAffected == 0 && checked {
+		return ErrVersionConflict
+	}
+	return result.Error
+}
+
+func (r *GormRepository) FindDeleted(ctx context.Context, page, size int) ([]User, int64, error) {
+	var users []User
+	var total int64
+
+	offset := (page - 1) * size
+	query := r.db.WithContext(ctx).Unscoped().Model(&User{}).Where("deleted_at IS NOT NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("deleted_at DESC").Offset(offset).Limit(size).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *GormRepository) FindPurgeable(ctx context.Context, before time.Time, limit int) ([]User, error) {
+	var users []User
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND purge_after <= ?", before).
+		Order("purge_attempts, purge_after").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *GormRepository) Purge(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Delete(&User{}).Error
 }
 
-func (r *GormRepository) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&User{}, id).Error
+func (r *GormRepository) RecordPurgeFailure(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Model(&User{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"purge_attempts":        gorm.Expr("purge_attempts + 1"),
+			"last_purge_attempt_at": utils.Now(),
+		}).Error
 }
 
-func (r *GormRepository) DeleteByIDs(ctx context.Context, ids []int64) error {
-	return r.db.WithContext(ctx).Delete(&User{}, ids).Error
+// Discard removes a user that was just created, when the rest of the signup
+// could not be completed
+func (r *GormRepository) Discard(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&User{}, id).Error
 }
 
 func (r *GormRepository) FindAll(ctx context.Context, page, size int) ([]User, int64, error) {
@@ -64,30 +251,84 @@ func (r *GormRepository) FindAll(ctx context.Context, page, size int) ([]User, i
 	return users, total, nil
 }
 
-func (r *GormRepository) FindAllWithFilter(ctx context.Context, keyword string, page, size int) ([]User, int64, error) {
+func (r *GormRepository) FindAllWithFilter(ctx context.Context, filter ListFilter, page, size int) ([]User, int64, error) {
 	var users []User
 	var total int64
 
 	offset := (page - 1) * size
-	query := r.db.WithContext(ctx).Model(&User{})
-
-	if keyword != "" {
-		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+keyword+"%", "%"+keyword+"%")
-	}
+	query := applyListFilter(r.db.WithContext(ctx).Model(&User{}), filter)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := query.Offset(offset).Limit(size).Find(&users).Error; err != nil {
+	if err := applySortOrder(query, filter.Sort).Offset(offset).Limit(size).Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
 
+// FindWithCursor pages through users with keyset pagination. It returns the
+// cursor of the next page, or nil when there are no more rows.
+func (r *GormRepository) FindWithCursor(ctx context.Context, filter ListFilter, cursor *Cursor, size int) ([]User, *Cursor, error) {
+	var users []User
+
+	query := applyListFilter(r.db.WithContext(ctx).Model(&User{}), filter)
+	if cursor != nil {
+		query = applyCursor(query, filter.Sort, cursor)
+	}
+
+	if err := applySortOrder(query, filter.Sort).Limit(size + 1).Find(&users).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if len(users) <= size {
+		return users, nil, nil
+	}
+
+	users = users[:size]
+	return users, cursorFor(&users[size-1], filter.Sort), nil
+}
+
+func (r *GormRepository) CountWithFilter(ctx context.Context, filter ListFilter) (int64, error) {
+	var total int64
+	err := applyListFilter(r.db.WithContext(ctx).Model(&User{}), filter).Count(&total).Error
+	return total, err
+}
+
+// StreamWithFilter reads the matching users row by row from the database
+// cursor, so large result sets are never fully loaded into memory.
+func (r *GormRepository) StreamWithFilter(ctx context.Context, filter ListFilter, fn func(u *User) error) error {
+	query := applySortOrder(applyListFilter(r.db.WithContext(ctx).Model(&User{}), filter), filter.Sort)
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u User
+		if err := r.db.ScanRows(rows, &u); err != nil {
+			return err
+		}
+		if err := fn(&u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// UpdateLastAccess records a sign-in. It does not change the version, so
+// pending edits of the user stay valid.
+func (r *GormRepository) UpdateLastAccess(ctx context.Context, id int64, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).UpdateColumn("last_access", at).Error
+}
+
 func (r *GormRepository) ToggleStatus(ctx context.Context, id int64, active bool) error {
-	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("active", active).Error
+	_, err := r.updateColumns(ctx, id, map[string]interface{}{"active": active})
+	return err
 }
 
 func (r *GormRepository) RequestPasswordReset(ctx context.Context, email string) error {
@@ -119,6 +360,7 @@ func (r *GormRepository) ChangePassword(ctx context.Context, id int64, currentPa
 	}
 
 	user.Password = &hashedPassword
+	user.Metadata.PasswordChangeRequired = false
 	return r.Update(ctx, user)
 }
 
@@ -127,107 +369,113 @@ func (r *GormRepository) ResetUserPassword(ctx context.Context, id int64, newPas
 	if err != nil {
 		return err
 	}
-	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+	_, err = r.updateColumns(ctx, id, map[string]interface{}{
+		"password": hashedPassword,
+		"metadata": gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{password_change_required}', 'true'::jsonb)"),
+	})
+	return err
 }
 
-func (r *GormRepository) UpdateAccessMode(ctx context.Context, id int64, accessMode string) (*User, error) {
-	user, err := r.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
+func (r *GormRepository) RequirePasswordChange(ctx context.Context, ids []int64, exceptID int64) ([]int64, error) {
+	query := r.db.WithContext(ctx).Model(&User{}).
+		Where("password IS NOT NULL AND id != ?", exceptID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
 	}
 
-	user.Metadata.AccessMode = AccessMode(accessMode)
-	if err := r.Update(ctx, user); err != nil {
+	var affected []int64
+	if err := query.Pluck("id", &affected).Error; err != nil {
 		return nil, err
 	}
-	return user, nil
-}
 
-func (r *GormRepository) UpdateFeatures(ctx context.Context, id int64, canCreateBudgets, canExportData, canUseReports, canUseGoals *bool) (*User, error) {
-	user, err := r.GetByID(ctx, id)
+	if len(affected) == 0 {
+		return affected, nil
+	}
+
+	err := r.db.WithContext(ctx).Model(&User{}).
+		Where("id IN ?", affected).
+		Updates(map[string]interface{}{
+			"metadata": gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{password_change_required}', 'true'::jsonb)"),
+			"version":  gorm.Expr("version + 1"),
+		}).Error
 	if err != nil {
 		return nil, err
 	}
 
-	if canCreateBudgets != nil {
-		user.Metadata.CanCreateBudgets = *canCreateBudgets
-	}
-	if canExportData != nil {
-		user.Metadata.CanExportData = *canExportData
-	}
-	if canUseReports != nil {
-		user.Metadata.CanUseReports = *canUseReports
-	}
-	if canUseGoals != nil {
-		user.Metadata.CanUseGoals = *canUseGoals
-	}
+	return affected, nil
+}
 
-	if err := r.Update(ctx, user); err != nil {
-		return nil, err
-	}
-	return user, nil
+func (r *GormRepository) UpdateAccessMode(ctx context.Context, id int64, accessMode string) (*User, error) {
+	return r.updateColumns(ctx, id, map[string]interface{}{
+		"metadata": gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{access_mode}', to_jsonb(?::text))", accessMode),
+	})
 }
 
+// UpdateLimits records per-user overrides, which take effect right away and
+// are kept when the plan changes. The overrides and the effective values are
+// merged into the metadata in place.
 func (r *GormRepository) UpdateLimits(ctx context.Context, id int64, maxAccounts, maxTransactionsPerMonth, maxCategoriesPerAccount *int) (*User, error) {
-	user, err := r.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-
+	limits := make(map[string]int, 3)
 	if maxAccounts != nil {
-		user.Metadata.MaxAccounts = *maxAccounts
+		limits["max_accounts"] = *maxAccounts
 	}
 	if maxTransactionsPerMonth != nil {
-		user.Metadata.MaxTransactionsPerMonth = *maxTransactionsPerMonth
+		limits["max_transactions_per_month"] = *maxTransactionsPerMonth
 	}
 	if maxCategoriesPerAccount != nil {
-		user.Metadata.MaxCategoriesPerAccount = *maxCategoriesPerAccount
+		limits["max_categories_per_account"] = *maxCategoriesPerAccount
 	}
 
-	if err := r.Update(ctx, user); err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
-func (r *GormRepository) GrantLifetimePro(ctx context.Context, id int64, reason string) (*User, error) {
-	user, err := r.GetByID(ctx, id)
+	patch, err := json.Marshal(limits)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Metadata.PlanType = PlanTypePro
-	user.Metadata.Notes = &reason
-
-	user.Metadata.PlanExpirationDate = nil
+	return r.updateColumns(ctx, id, map[string]interface{}{
+		"metadata": gorm.Expr(`COALESCE(metadata, '{}'::jsonb)
+			|| jsonb_build_object('plan_overrides', COALESCE(NULLIF(metadata->'plan_overrides', 'null'::jsonb), '{}'::jsonb) || ?::jsonb)
+			|| ?::jsonb`, string(patch), string(patch)),
+	})
+}
 
-	if err := r.Update(ctx, user); err != nil {
-		return nil, err
-	}
-	return user, nil
+// RecordSuspiciousActivity counts one more suspicious activity and puts the
+// user under watch, unless already blocked.
+func (r *GormRepository) RecordSuspiciousActivity(ctx context.Context, id int64, at time.Time) (*User, error) {
+	return r.updateColumns(ctx, id, map[string]interface{}{
+		"metadata": gorm.Expr(`COALESCE(metadata, '{}'::jsonb) || jsonb_build_object(
+			'suspicious_activity_count', COALESCE((metadata->>'suspicious_activity_count')::int, 0) + 1,
+			'last_security_check', ?::text,
+			'reputation_status', CASE WHEN metadata->>'reputation_status' = ? THEN ?::text ELSE ?::text END)`,
+			at.Format(time.RFC3339Nano), ReputationStatusBlocked, ReputationStatusBlocked, ReputationStatusSuspicious),
+	})
 }
 
-func (r *GormRepository) RevokeLifetimePro(ctx context.Context, id int64) (*User, error) {
-	user, err := r.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
+// UpdateReputationStatus sets the reputation status of the user. With from
+// set, it only applies to users in that status.
+func (r *GormRepository) UpdateReputationStatus(ctx context.Context, id int64, status, from ReputationStatus) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id)
+	if from != "" {
+		query = query.Where("metadata->>'reputation_status' = ?", from)
 	}
 
-	user.Metadata.PlanType = PlanTypeFree
-	if err := r.Update(ctx, user); err != nil {
-		return nil, err
-	}
-	return user, nil
+	result := query.Updates(map[string]interface{}{
+		"metadata": gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{reputation_status}', to_jsonb(?::text))", status),
+		"version":  gorm.Expr("version + 1"),
+	})
+	return result.RowsAffected > 0, result.Error
 }
 
-func (r *GormRepository) EnsureMetadata(ctx context.Context, id int64) (*User, error) {
+func (r *GormRepository) EnsureMetadata(ctx context.Context, id int64, defaults UserMetadata) (*User, error) {
 	user, err := r.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := CheckVersion(ctx, user); err != nil {
+		return nil, err
+	}
 
 	if user.Metadata.Locale == "" {
-		user.Metadata = NewDefaultMetadata()
+		user.Metadata = defaults
 		if err := r.Update(ctx, user); err != nil {
 			return nil, err
 		}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 )
 
 type Service struct {
@@ -16,26 +17,41 @@ func NewService(repo UserService) *Service {
 
 type UserService interface {
 	Create(ctx context.Context, user *User) error
+	CreateBatch(ctx context.Context, users []*User) error
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	FindExistingEmails(ctx context.Context, emails []string) ([]string, error)
 	GetByID(ctx context.Context, id int64) (*User, error)
 	Update(ctx context.Context, user *User) error
-	Delete(ctx context.Context, id int64) error
-	DeleteByIDs(ctx context.Context, ids []int64) error
+	Delete(ctx context.Context, id, deletedBy int64, purgeAfter time.Time) (bool, error)
+	DeleteByIDs(ctx context.Context, ids []int64, deletedBy int64, purgeAfter time.Time) (int64, error)
+
+	GetDeletedByID(ctx context.Context, id int64) (*User, error)
+	GetDeletedByEmail(ctx context.Context, email string) (*User, error)
+	Restore(ctx context.Context, id int64) error
+	FindDeleted(ctx context.Context, page, size int) ([]User, int64, error)
+	FindPurgeable(ctx context.Context, before time.Time, limit int) ([]User, error)
+	Purge(ctx context.Context, id int64) error
+	RecordPurgeFailure(ctx context.Context, id int64) error
+	Discard(ctx context.Context, id int64) error
 
 	FindAll(ctx context.Context, page, size int) ([]User, int64, error)
-	FindAllWithFilter(ctx context.Context, keyword string, page, size int) ([]User, int64, error)
+	FindAllWithFilter(ctx context.Context, filter ListFilter, page, size int) ([]User, int64, error)
+	FindWithCursor(ctx context.Context, filter ListFilter, cursor *Cursor, size int) ([]User, *Cursor, error)
+	CountWithFilter(ctx context.Context, filter ListFilter) (int64, error)
+	StreamWithFilter(ctx context.Context, filter ListFilter, fn func(u *User) error) error
 
 	ToggleStatus(ctx context.Context, id int64, active bool) error
+	UpdateLastAccess(ctx context.Context, id int64, at time.Time) error
 
 	RequestPasswordReset(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
 	ChangePassword(ctx context.Context, id int64, currentPassword, newPassword string) error
 	ResetUserPassword(ctx context.Context, id int64, newPassword string) error
+	RequirePasswordChange(ctx context.Context, ids []int64, exceptID int64) ([]int64, error)
 
 	UpdateAccessMode(ctx context.Context, id int64, accessMode string) (*User, error)
-	UpdateFeatures(ctx context.Context, id int64, canCreateBudgets, canExportData, canUseReports, canUseGoals *bool) (*User, error)
 	UpdateLimits(ctx context.Context, id int64, maxAccounts, maxTransactionsPerMonth, maxCategoriesPerAccount *int) (*User, error)
-	GrantLifetimePro(ctx context.Context, id int64, reason string) (*User, error)
-	RevokeLifetimePro(ctx context.Context, id int64) (*User, error)
-	EnsureMetadata(ctx context.Context, id int64) (*User, error)
+	RecordSuspiciousActivity(ctx context.Context, id int64, at time.Time) (*User, error)
+	UpdateReputationStatus(ctx context.Context, id int64, status, from ReputationStatus) (bool, error)
+	EnsureMetadata(ctx context.Context, id int64, defaults UserMetadata) (*User, error)
 }
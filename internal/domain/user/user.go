@@ -4,7 +4,10 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"slices"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type AccessMode string
@@ -39,8 +42,7 @@ const (
 	ProSourceAdmin        ProSource = "ADMIN_GRANTED"
 )
 
-type UserMetadata struct {
-	AccessMode          AccessMode `json:"access_mode"`
+type PlanMetadata struct {
 	PlanType            PlanType   `json:"plan_type"`
 	PlanExpirationDate  *time.Time `json:"plan_expiration_date,omitempty"`
 	ProSource           *ProSource `json:"pro_source,omitempty"`
@@ -51,13 +53,42 @@ type UserMetadata struct {
 	MaxCategoriesPerAccount int `json:"max_categories_per_account"`
 	MaxTransactionsPerMonth int `json:"max_transactions_per_month"`
 
-	CanExportData          bool `json:"can_export_data"`
-	CanUseReports          bool `json:"can_use_reports"`
-	CanUseAdvancedFeatures bool `json:"can_use_advanced_features"`
-	CanCreateBudgets       bool `json:"can_create_budgets"`
-	CanUseGoals            bool `json:"can_use_goals"`
+	// Features holds the enabled feature keys, sorted. Keys are registered in
+	// the plan catalog.
+	Features []string `json:"features"`
+}
+
+func (m PlanMetadata) HasFeature(key string) bool {
+	return slices.Contains(m.Features, key)
+}
+
+func (m *PlanMetadata) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, m)
+}
+
+func (m PlanMetadata) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+type UserMetadata struct {
+	AccessMode AccessMode `json:"access_mode"`
+
+	PlanMetadata
+	PlanOverrides *PlanOverrides `json:"plan_overrides,omitempty"`
+
+	// TrialUsedAt marks the one PRO trial of the account as taken.
+	TrialUsedAt *time.Time `json:"trial_used_at,omitempty"`
 
 	EmailVerified           bool             `json:"email_verified"`
+	MustSetPassword         bool             `json:"must_set_password"`
+	PasswordChangeRequired  bool             `json:"password_change_required"`
 	ReputationStatus        ReputationStatus `json:"reputation_status"`
 	SuspiciousActivityCount int              `json:"suspicious_activity_count"`
 	LastSecurityCheck       *time.Time       `json:"last_security_check,omitempty"`
@@ -66,6 +97,17 @@ type UserMetadata struct {
 	Notes    *string `json:"notes,omitempty"`
 	Locale   string  `json:"locale"`
 	Currency string  `json:"currency"`
+	Timezone string  `json:"timezone"`
+
+	Notifications      NotificationSettings `json:"notifications"`
+	MarketingConsent   bool                 `json:"marketing_consent"`
+	MarketingConsentAt *time.Time           `json:"marketing_consent_at,omitempty"`
+}
+
+// NotificationSettings holds the optional emails a user can opt out of.
+// Account and security emails are always sent.
+type NotificationSettings struct {
+	PlanReminders bool `json:"plan_reminders"`
 }
 
 func (m *UserMetadata) Scan(value interface{}) error {
@@ -96,24 +138,87 @@ type User struct {
 	LastAccess *time.Time   `gorm:"column:last_access"`
 	CreatedAt  time.Time    `gorm:"not null"`
 	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	DeletedBy  *int64         `gorm:"column:deleted_by"`
+	PurgeAfter *time.Time     `gorm:"column:purge_after"`
+
+	// Version is incremented on every change and exposed as the ETag.
+	Version int64 `gorm:"not null;default:1"`
+}
+
+// IsSelfDeleted reports whether the account was deleted by its owner, which
+// allows it to be restored by logging in during the grace period.
+func (u *User) IsSelfDeleted() bool {
+	return u.DeletedAt.Valid && u.DeletedBy != nil && *u.DeletedBy == u.ID
+}
+
+// PlanCatalog resolves the limits and features granted by each plan.
+type PlanCatalog interface {
+	Entitlements(planType PlanType, overrides *PlanOverrides) (PlanMetadata, error)
+}
+
+// PlanOverrides are per-user adjustments on top of the plan catalog. They are
+// kept apart from the effective values so they survive plan changes.
+type PlanOverrides struct {
+	MaxAccounts             *int `json:"max_accounts,omitempty"`
+	MaxCategoriesPerAccount *int `json:"max_categories_per_account,omitempty"`
+	MaxTransactionsPerMonth *int `json:"max_transactions_per_month,omitempty"`
+
+	// Features maps feature keys to an explicit grant (true) or denial (false).
+	Features map[string]bool `json:"features,omitempty"`
+}
+
+func (o *PlanOverrides) IsEmpty() bool {
+	return o == nil || (o.MaxAccounts == nil && o.MaxCategoriesPerAccount == nil &&
+		o.MaxTransactionsPerMonth == nil && len(o.Features) == 0)
+}
+
+// Apply writes the overridden values into the plan entitlements.
+func (o *PlanOverrides) Apply(m *PlanMetadata) {
+	if o == nil {
+		return
+	}
+	if o.MaxAccounts != nil {
+		m.MaxAccounts = *o.MaxAccounts
+	}
+	if o.MaxCategoriesPerAccount != nil {
+		m.MaxCategoriesPerAccount = *o.MaxCategoriesPerAccount
+	}
+	if o.MaxTransactionsPerMonth != nil {
+		m.MaxTransactionsPerMonth = *o.MaxTransactionsPerMonth
+	}
+	if len(o.Features) == 0 {
+		return
+	}
+
+	// Builds a new slice: the plan features may be shared with the catalog.
+	features := make([]string, 0, len(m.Features)+len(o.Features))
+	for _, key := range m.Features {
+		if enabled, ok := o.Features[key]; !ok || enabled {
+			features = append(features, key)
+		}
+	}
+	for key, enabled := range o.Features {
+		if enabled && !slices.Contains(m.Features, key) {
+			features = append(features, key)
+		}
+	}
+	slices.Sort(features)
+	m.Features = features
 }
 
-func NewDefaultMetadata() UserMetadata {
+// NewDefaultMetadata builds the metadata of a new account on the given plan
+// entitlements, usually the FREE plan of the catalog.
+func NewDefaultMetadata(plan PlanMetadata) UserMetadata {
 	return UserMetadata{
 		AccessMode:              AccessModeReadWrite,
-		PlanType:                PlanTypeFree,
-		MaxAccounts:             5,
-		MaxCategoriesPerAccount: 20,
-		MaxTransactionsPerMonth: 200,
-		CanExportData:           false,
-		CanUseReports:           false,
-		CanUseAdvancedFeatures:  false,
-		CanCreateBudgets:        true,
-		CanUseGoals:             false,
+		PlanMetadata:            plan,
 		EmailVerified:           false,
 		ReputationStatus:        ReputationStatusGood,
 		SuspiciousActivityCount: 0,
 		Locale:                  "pt-BR",
 		Currency:                "BRL",
+		Timezone:                "America/Sao_Paulo",
+		Notifications:           NotificationSettings{PlanReminders: true},
 	}
 }
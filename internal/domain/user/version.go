@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+	"strings"
+
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+// ErrVersionConflict is returned when the user changed since the version the
+// caller read.
+var ErrVersionConflict = errors.New(errors.EPRECONDITION, "user.version_conflict")
+
+func IsVersionConflict(err error) bool {
+	return stderrors.Is(err, ErrVersionConflict)
+}
+
+type versionContextKey struct{}
+
+type expectedVersion struct {
+	userID  int64
+	version int64
+}
+
+// WithExpectedVersion stores the version of the user the client based its
+// change on (If-Match). Repository writes to that user fail with
+// ErrVersionConflict when it no longer matches.
+func WithExpectedVersion(ctx context.Context, userID, version int64) context.Context {
+	return context.WithValue(ctx, versionContextKey{}, expectedVersion{userID: userID, version: version})
+}
+
+func ExpectedVersionFromContext(ctx context.Context, userID int64) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	expected, ok := ctx.Value(versionContextKey{}).(expectedVersion)
+	return expected.version, ok && expected.userID == userID
+}
+
+// CheckVersion fails when the loaded user does not match the version in ctx,
+// for flows that write through bulk updates.
+func CheckVersion(ctx context.Context, u *User) error {
+	if expected, ok := ExpectedVersionFromContext(ctx, u.ID); ok && expected != u.Version {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func ETag(u *User) string {
+	return `"` + strconv.FormatInt(u.Version, 10) + `"`
+}
+
+// ParseETag reads the version from an ETag built by ETag. Weak tags are
+// accepted since the version covers the whole resource.
+func ParseETag(value string) (int64, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	return version, err == nil && version > 0
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantVersion int64
+		wantOK      bool
+	}{
+		{"strong", `"3"`, 3, true},
+		{"weak", `W/"3"`, 3, true},
+		{"surrounding spaces", ` "12" `, 12, true},
+		{"unquoted", `3`, 0, false},
+		{"missing closing quote", `"3`, 0, false},
+		{"empty quotes", `""`, 0, false},
+		{"not a number", `"abc"`, 0, false},
+		{"zero", `"0"`, 0, false},
+		{"negative", `"-1"`, 0, false},
+		{"list", `"1", "2"`, 0, false},
+		{"empty", ``, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok := ParseETag(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseETag(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if ok && version != tt.wantVersion {
+				t.Errorf("ParseETag(%q) = %d, want %d", tt.value, version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestETagRoundTrip(t *testing.T) {
+	u := &User{Version: 42}
+	version, ok := ParseETag(ETag(u))
+	if !ok || version != u.Version {
+		t.Fatalf("ParseETag(ETag()) = %d, %v, want %d, true", version, ok, u.Version)
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		user    *User
+		wantErr bool
+	}{
+		{"no expected version", context.Background(), &User{ID: 1, Version: 2}, false},
+		{"matching version", WithExpectedVersion(context.Background(), 1, 2), &User{ID: 1, Version: 2}, false},
+		{"stale version", WithExpectedVersion(context.Background(), 1, 1), &User{ID: 1, Version: 2}, true},
+		{"other user", WithExpectedVersion(context.Background(), 9, 1), &User{ID: 1, Version: 2}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckVersion(tt.ctx, tt.user)
+			if tt.wantErr != IsVersionConflict(err) {
+				t.Fatalf("CheckVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("CheckVersion() error = %v", err)
+			}
+		})
+	}
+}
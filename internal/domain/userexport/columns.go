@@ -0,0 +1,109 @@
+package userexport
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type Column struct {
+	Key     string
+	numeric bool
+	value   func(u *user.User) string
+}
+
+// Exportable columns, in the order they appear when selected. Metadata
+// fields are flattened into their own columns.
+var columns = []Column{
+	{"id", true, func(u *user.User) string { return strconv.FormatInt(u.ID, 10) }},
+	{"name", false, func(u *user.User) string { return u.Name }},
+	{"email", false, func(u *user.User) string { return u.Email }},
+	{"active", false, func(u *user.User) string { return strconv.FormatBool(u.Active) }},
+	{"admin", false, func(u *user.User) string { return strconv.FormatBool(u.Admin) }},
+	{"source", false, func(u *user.User) string { return u.Source }},
+	{"createdAt", false, func(u *user.User) string { return formatTime(&u.CreatedAt) }},
+	{"updatedAt", false, func(u *user.User) string { return formatTime(&u.UpdatedAt) }},
+	{"lastAccess", false, func(u *user.User) string { return formatTime(u.LastAccess) }},
+	{"accessMode", false, func(u *user.User) string { return string(u.Metadata.AccessMode) }},
+	{"planType", false, func(u *user.User) string { return string(u.Metadata.PlanType) }},
+	{"planExpirationDate", false, func(u *user.User) string { return formatTime(u.Metadata.PlanExpirationDate) }},
+	{"proSource", false, func(u *user.User) string {
+		if u.Metadata.ProSource == nil {
+			return ""
+		}
+		return string(*u.Metadata.ProSource)
+	}},
+	{"maxAccounts", true, func(u *user.User) string { return strconv.Itoa(u.Metadata.MaxAccounts) }},
+	{"maxCategoriesPerAccount", true, func(u *user.User) string { return strconv.Itoa(u.Metadata.MaxCategoriesPerAccount) }},
+	{"maxTransactionsPerMonth", true, func(u *user.User) string { return strconv.Itoa(u.Metadata.MaxTransactionsPerMonth) }},
+	{"features", false, func(u *user.User) string { return strings.Join(u.Metadata.Features, ",") }},
+	{"emailVerified", false, func(u *user.User) string { return strconv.FormatBool(u.Metadata.EmailVerified) }},
+	{"mustSetPassword", false, func(u *user.User) string { return strconv.FormatBool(u.Metadata.MustSetPassword) }},
+	{"passwordChangeRequired", false, func(u *user.User) string { return strconv.FormatBool(u.Metadata.PasswordChangeRequired) }},
+	{"reputationStatus", false, func(u *user.User) string { return string(u.Metadata.ReputationStatus) }},
+	{"suspiciousActivityCount", true, func(u *user.User) string { return strconv.Itoa(u.Metadata.SuspiciousActivityCount) }},
+	{"locale", false, func(u *user.User) string { return u.Metadata.Locale }},
+	{"currency", false, func(u *user.User) string { return u.Metadata.Currency }},
+	{"timezone", false, func(u *user.User) string { return u.Metadata.Timezone }},
+}
+
+var DefaultColumns = []string{
+	"id", "name", "email", "active", "admin", "source",
+	"planType", "planExpirationDate", "emailVerified", "createdAt", "lastAccess",
+}
+
+// ResolveColumns validates the requested keys, keeping the order they were
+// given in. An empty selection returns DefaultColumns.
+func ResolveColumns(keys []string) ([]Column, error) {
+	if len(keys) == 0 {
+		keys = DefaultColumns
+	}
+
+	resolved := make([]Column, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+
+		column, ok := findColumn(key)
+		if !ok {
+			return nil, errors.New(errors.EINVALID, "export.invalid_column", key)
+		}
+		seen[key] = true
+		resolved = append(resolved, column)
+	}
+
+	if len(resolved) == 0 {
+		return nil, errors.New(errors.EINVALID, "export.columns_required")
+	}
+	return resolved, nil
+}
+
+func findColumn(key string) (Column, bool) {
+	for _, c := range columns {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func columnKeys(cols []Column) []string {
+	keys := make([]string, len(cols))
+	for i, c := range cols {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
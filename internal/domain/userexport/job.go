@@ -0,0 +1,35 @@
+package userexport
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending    Status = "PENDING"
+	StatusProcessing Status = "PROCESSING"
+	StatusCompleted  Status = "COMPLETED"
+	StatusFailed     Status = "FAILED"
+	StatusExpired    Status = "EXPIRED"
+)
+
+type ExportJob struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement"`
+	CreatedBy   int64      `gorm:"not null;index"`
+	Format      Format     `gorm:"size:10;not null"`
+	Status      Status     `gorm:"size:20;not null;default:PENDING"`
+	Columns     string     `gorm:"not null"`
+	Query       string     `gorm:"not null;default:''"`
+	RowCount    int64      `gorm:"not null;default:0"`
+	StorageKey  *string    `gorm:"column:storage_key;size:500"`
+	FileSize    int64      `gorm:"not null;default:0"`
+	Error       *string    `gorm:"column:error"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at"`
+	CreatedAt   time.Time  `gorm:"not null"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+}
+
+func (ExportJob) TableName() string {
+	return "user_export_jobs"
+}
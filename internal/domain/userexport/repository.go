@@ -0,0 +1,65 @@
+package userexport
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, job *ExportJob) error
+	Save(ctx context.Context, job *ExportJob) error
+	FindByID(ctx context.Context, id int64) (*ExportJob, error)
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]ExportJob, error)
+	FailStale(ctx context.Context, before time.Time, message string) (int64, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, job *ExportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *GormRepository) Save(ctx context.Context, job *ExportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *GormRepository) FindByID(ctx context.Context, id int64) (*ExportJob, error) {
+	var job ExportJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *GormRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]ExportJob, error) {
+	var jobs []ExportJob
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", StatusCompleted, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *GormRepository) FailStale(ctx context.Context, before time.Time, message string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&ExportJob{}).
+		Where("status IN ? AND created_at < ?", []Status{StatusPending, StatusProcessing}, before).
+		Updates(map[string]interface{}{
+			"status":       StatusFailed,
+			"error":        message,
+			"completed_at": utils.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,301 @@
+package userexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/email"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/storage"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const cleanupBatchSize = 100
+
+// A job still pending or processing after this long was interrupted (e.g.
+// the instance restarted) and is marked as failed.
+const staleJobAfter = time.Hour
+
+type Request struct {
+	Filter  user.ListFilter
+	Columns []string
+	Format  Format
+
+	// Query is the raw query string, kept on asynchronous jobs for auditing.
+	Query string
+}
+
+type Service struct {
+	repo           Repository
+	userRepo       user.UserService
+	storageService *storage.Service
+	emailSender    email.EmailSender
+	asyncThreshold int64
+	linkExpiration time.Duration
+	logger         logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	storageService *storage.Service,
+	emailSender email.EmailSender,
+	asyncThreshold int,
+	linkExpirationHours int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:           repo,
+		userRepo:       userRepo,
+		storageService: storageService,
+		emailSender:    emailSender,
+		asyncThreshold: int64(asyncThreshold),
+		linkExpiration: time.Duration(linkExpirationHours) * time.Hour,
+		logger:         logger,
+	}
+}
+
+// Prepare validates the request and tells whether it is small enough to be
+// streamed in the response; larger exports must go through StartAsync.
+func (s *Service) Prepare(ctx context.Context, req Request) ([]Column, bool, error) {
+	cols, err := ResolveColumns(req.Columns)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if req.Format != FormatCSV && req.Format != FormatXLSX {
+		return nil, false, errors.New(errors.EINVALID, "export.invalid_format")
+	}
+
+	total, err := s.userRepo.CountWithFilter(ctx, req.Filter)
+	if err != nil {
+		return nil, false, errors.New(errors.EINTERNAL, "export.count_failed")
+	}
+
+	return cols, total <= s.asyncThreshold, nil
+}
+
+// Write streams the matching users into w, reading them from the database
+// cursor one row at a time.
+func (s *Service) Write(ctx context.Context, w io.Writer, format Format, cols []Column, filter user.ListFilter) (int64, error) {
+	writer := newRowWriter(format, w, cols)
+	if err := writer.Write(columnKeys(cols)); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err := s.userRepo.StreamWithFilter(ctx, filter, func(u *user.User) error {
+		record := make([]string, len(cols))
+		for i, c := range cols {
+			record[i] = c.value(u)
+		}
+		count++
+		return writer.Write(record)
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, writer.Close()
+}
+
+// StartAsync registers an export job and produces the file in the
+// background. Once stored, the presigned link is sent to the admin by email
+// and is also available from GetJob.
+func (s *Service) StartAsync(ctx context.Context, adminID int64, cols []Column, req Request) (*ExportJob, error) {
+	job := &ExportJob{
+		CreatedBy: adminID,
+		Format:    req.Format,
+		Status:    StatusPending,
+		Columns:   strings.Join(columnKeys(cols), ","),
+		Query:     req.Query,
+		CreatedAt: utils.Now(),
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		s.logger.Error("Failed to create user export job", zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "export.start_failed")
+	}
+
+	go s.process(context.Background(), *job, cols, req.Filter)
+
+	s.logger.Info("User export started", zap.Int64("jobId", job.ID), zap.Int64("adminId", adminID), zap.String("format", string(job.Format)))
+	return job, nil
+}
+
+func (s *Service) GetJob(ctx context.Context, id int64) (*ExportJob, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "export.not_found")
+	}
+	return job, nil
+}
+
+// DownloadURL returns a fresh presigned link for a completed job, valid until
+// the job expires.
+func (s *Service) DownloadURL(ctx context.Context, job *ExportJob) (string, error) {
+	if job.Status != StatusCompleted || job.StorageKey == nil || job.ExpiresAt == nil {
+		return "", nil
+	}
+
+	remaining := time.Until(*job.ExpiresAt)
+	if remaining <= 0 {
+		return "", nil
+	}
+	return s.storageService.GetPresignedDownloadUrl(ctx, *job.StorageKey, remaining)
+}
+
+func (s *Service) process(ctx context.Context, job ExportJob, cols []Column, filter user.ListFilter) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.fail(ctx, &job, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	job.Status = StatusProcessing
+	s.save(ctx, &job)
+
+	downloadURL, err := s.build(ctx, &job, cols, filter)
+	if err != nil {
+		s.fail(ctx, &job, err)
+		return
+	}
+
+	if admin, err := s.userRepo.GetByID(ctx, job.CreatedBy); err == nil {
+		if err := s.sendExportEmail(ctx, admin, downloadURL, job.RowCount); err != nil {
+			s.logger.Error("Failed to send user export email", zap.Int64("jobId", job.ID), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("User export completed", zap.Int64("jobId", job.ID), zap.Int64("rows", job.RowCount), zap.Int64("size", job.FileSize))
+}
+
+func (s *Service) build(ctx context.Context, job *ExportJob, cols []Column, filter user.ListFilter) (string, error) {
+	tmp, err := os.CreateTemp("", "user-export-*."+string(job.Format))
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	count, err := s.Write(ctx, tmp, job.Format, cols, filter)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("exports/users/%s.%s", uuid.New().String(), job.Format)
+	if err := s.storageService.Store(ctx, key, tmp, job.Format.ContentType(), info.Size()); err != nil {
+		return "", err
+	}
+
+	downloadURL, err := s.storageService.GetPresignedDownloadUrl(ctx, key, s.linkExpiration)
+	if err != nil {
+		return "", err
+	}
+
+	now := utils.Now()
+	expiresAt := now.Add(s.linkExpiration)
+	job.Status = StatusCompleted
+	job.RowCount = count
+	job.StorageKey = &key
+	job.FileSize = info.Size()
+	job.CompletedAt = &now
+	job.ExpiresAt = &expiresAt
+	if err := s.repo.Save(ctx, job); err != nil {
+		return "", err
+	}
+
+	return downloadURL, nil
+}
+
+// CleanupExpired removes the files whose download link has expired.
+func (s *Service) CleanupExpired(ctx context.Context) (int, error) {
+	jobs, err := s.repo.FindExpired(ctx, utils.Now(), cleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i := range jobs {
+		job := &jobs[i]
+		if job.StorageKey != nil {
+			if err := s.storageService.DeleteObject(ctx, *job.StorageKey); err != nil {
+				s.logger.Error("Failed to remove expired user export", zap.Int64("jobId", job.ID), zap.Error(err))
+				continue
+			}
+		}
+
+		job.Status = StatusExpired
+		job.StorageKey = nil
+		if err := s.repo.Save(ctx, job); err != nil {
+			s.logger.Error("Failed to update expired user export", zap.Int64("jobId", job.ID), zap.Error(err))
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.logger.Info("Expired user exports removed", zap.Int("count", removed))
+	}
+	return removed, nil
+}
+
+// FailInterrupted fails the jobs left unfinished, such as those running when
+// an instance restarted.
+func (s *Service) FailInterrupted(ctx context.Context) (int64, error) {
+	failed, err := s.repo.FailStale(ctx, utils.Now().Add(-staleJobAfter), "interrupted before finishing")
+	if err != nil {
+		return 0, err
+	}
+
+	if failed > 0 {
+		s.logger.Warn("Interrupted user exports marked as failed", zap.Int64("count", failed))
+	}
+	return failed, nil
+}
+
+func (s *Service) fail(ctx context.Context, job *ExportJob, err error) {
+	s.logger.Error("Failed to build user export", zap.Int64("jobId", job.ID), zap.Error(err))
+
+	message := err.Error()
+	completedAt := utils.Now()
+	job.Status = StatusFailed
+	job.Error = &message
+	job.CompletedAt = &completedAt
+	s.save(ctx, job)
+}
+
+func (s *Service) save(ctx context.Context, job *ExportJob) {
+	if err := s.repo.Save(ctx, job); err != nil {
+		s.logger.Error("Failed to update user export job", zap.Int64("jobId", job.ID), zap.Error(err))
+	}
+}
+
+func (s *Service) sendExportEmail(ctx context.Context, admin *user.User, downloadURL string, rows int64) error {
+	subject, body, err := email.Render(admin.Metadata.Locale, admin.Metadata.Timezone, "user_export", map[string]any{
+		"Rows":            rows,
+		"URL":             downloadURL,
+		"ExpirationHours": int(s.linkExpiration.Hours()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, admin.Email, subject, body)
+}
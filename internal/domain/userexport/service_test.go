@@ -0,0 +1,51 @@
+package userexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+)
+
+type jobRepo struct {
+	Repository
+	saved []ExportJob
+}
+
+func (r *jobRepo) Save(_ context.Context, job *ExportJob) error {
+	r.saved = append(r.saved, *job)
+	return nil
+}
+
+// streamUserRepo streams a single user.
+type streamUserRepo struct {
+	user.UserService
+}
+
+func (streamUserRepo) StreamWithFilter(_ context.Context, _ user.ListFilter, fn func(u *user.User) error) error {
+	return fn(&user.User{ID: 1, Email: "user@example.com"})
+}
+
+func TestProcessFailsJobOnPanic(t *testing.T) {
+	log, _ := logger.NewLogger("test", "none")
+	repo := &jobRepo{}
+	s := NewService(repo, streamUserRepo{}, nil, nil, 0, 24, log)
+
+	cols := []Column{{Key: "boom", value: func(*user.User) string { panic("boom") }}}
+	s.process(context.Background(), ExportJob{ID: 1, Format: FormatCSV, Status: StatusPending}, cols, user.ListFilter{})
+
+	if len(repo.saved) == 0 {
+		t.Fatal("job was never saved")
+	}
+	job := repo.saved[len(repo.saved)-1]
+	if job.Status != StatusFailed {
+		t.Errorf("status = %s, want %s", job.Status, StatusFailed)
+	}
+	if job.CompletedAt == nil {
+		t.Error("CompletedAt not set")
+	}
+	if job.Error == nil || *job.Error != "panic: boom" {
+		t.Errorf("error = %v, want panic: boom", job.Error)
+	}
+}
@@ -0,0 +1,70 @@
+package userexport
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// rowWriter receives the header first and then one record per user, with
+// values in column order.
+type rowWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+func newRowWriter(format Format, w io.Writer, cols []Column) rowWriter {
+	if format == FormatXLSX {
+		return newXLSXWriter(w, cols)
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+func (w *csvWriter) Write(record []string) error {
+	for i, value := range record {
+		record[i] = escapeFormula(value)
+	}
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+
+	// Flush periodically so the response starts streaming right away.
+	w.rows++
+	if w.rows%100 == 0 {
+		w.writer.Flush()
+	}
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// escapeFormula prevents spreadsheet applications from evaluating
+// user-provided values such as names as formulas.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,118 @@
+package userexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// xlsxWriter streams a single-sheet workbook. The static parts are written
+// upfront and the rows go straight into the sheet entry of the ZIP, so the
+// file is never held in memory. Strings are written inline, which avoids
+// having to build a shared strings table.
+type xlsxWriter struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	cols   []Column
+	row    int
+	err    error
+	header bool
+}
+
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Users" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+func newXLSXWriter(w io.Writer, cols []Column) *xlsxWriter {
+	xw := &xlsxWriter{zip: zip.NewWriter(w), cols: cols, header: true}
+
+	for _, part := range xlsxStaticParts {
+		entry, err := xw.zip.Create(part.name)
+		if err != nil {
+			xw.err = err
+			return xw
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			xw.err = err
+			return xw
+		}
+	}
+
+	entry, err := xw.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		xw.err = err
+		return xw
+	}
+	xw.sheet = bufio.NewWriter(entry)
+	xw.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return xw
+}
+
+func (w *xlsxWriter) Write(record []string) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.row++
+	row := strconv.Itoa(w.row)
+	w.sheet.WriteString(`<row r="` + row + `">`)
+
+	for i, value := range record {
+		ref := columnName(i) + row
+		if value == "" {
+			continue
+		}
+
+		if !w.header && i < len(w.cols) && w.cols[i].numeric {
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + value + `</v></c>`)
+			continue
+		}
+
+		w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(w.sheet, []byte(value)); err != nil {
+			w.err = err
+			return err
+		}
+		w.sheet.WriteString(`</t></is></c>`)
+	}
+
+	w.header = false
+	_, w.err = w.sheet.WriteString(`</row>`)
+	return w.err
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+// columnName converts a zero-based index into a spreadsheet column name
+// (0 -> A, 25 -> Z, 26 -> AA).
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
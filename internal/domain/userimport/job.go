@@ -0,0 +1,93 @@
+package userimport
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusRunning   Status = "RUNNING"
+	StatusCompleted Status = "COMPLETED"
+	StatusFailed    Status = "FAILED"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// RowError keeps the catalog key of the message and its params, so it is
+// translated when the job is read. Jobs stored before that hold the text.
+type RowError struct {
+	Row     int      `json:"row"`
+	Email   string   `json:"email,omitempty"`
+	Message string   `json:"message"`
+	Params  []string `json:"params,omitempty"`
+}
+
+type RowErrors []RowError
+
+func (e *RowErrors) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return stderrors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+func (e RowErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e)
+}
+
+type ImportJob struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement"`
+	CreatedBy       int64      `gorm:"not null;index"`
+	Format          Format     `gorm:"size:10;not null"`
+	Status          Status     `gorm:"size:20;not null;default:PENDING"`
+	DryRun          bool       `gorm:"not null;default:false"`
+	SendInvitations bool       `gorm:"not null;default:false"`
+	TotalRows       int        `gorm:"not null;default:0"`
+	ValidRows       int        `gorm:"not null;default:0"`
+	ProcessedRows   int        `gorm:"not null;default:0"`
+	CreatedRows     int        `gorm:"not null;default:0"`
+	FailedRows      int        `gorm:"not null;default:0"`
+	Errors          RowErrors  `gorm:"type:jsonb"`
+	Message         *string    `gorm:"column:message"`
+	CreatedAt       time.Time  `gorm:"not null"`
+	UpdatedAt       time.Time  `gorm:"not null;autoUpdateTime"`
+	StartedAt       *time.Time `gorm:"column:started_at"`
+	CompletedAt     *time.Time `gorm:"column:completed_at"`
+}
+
+func (ImportJob) TableName() string {
+	return "user_import_jobs"
+}
+
+func (j *ImportJob) addError(row int, email string, err error) {
+	rowError := RowError{Row: row, Email: email, Message: errors.ErrorKey(err)}
+
+	var e *errors.Error
+	if stderrors.As(err, &e) {
+		for _, param := range e.Params {
+			rowError.Params = append(rowError.Params, fmt.Sprint(param))
+		}
+	}
+
+	j.Errors = append(j.Errors, rowError)
+}
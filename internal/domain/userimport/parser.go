@@ -0,0 +1,131 @@
+package userimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported columns (CSV header) and keys (JSON lines). Only name and email
+// are required; the remaining ones override the defaults of the new account.
+// Admin rights and roles are granted through the role endpoints only.
+var columns = map[string]bool{
+	"name":                       true,
+	"email":                      true,
+	"active":                     true,
+	"source":                     true,
+	"plan_type":                  true,
+	"plan_expiration_date":       true,
+	"max_accounts":               true,
+	"max_categories_per_account": true,
+	"max_transactions_per_month": true,
+	"locale":                     true,
+	"currency":                   true,
+	"timezone":                   true,
+}
+
+// Row is a raw input record; values are validated later so every problem can
+// be reported against its row number.
+type Row struct {
+	Number int
+	Values map[string]string
+}
+
+func (r Row) get(column string) string {
+	return strings.TrimSpace(r.Values[column])
+}
+
+func parse(format Format, data []byte) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(bytes.NewReader(data))
+	case FormatJSONL:
+		return parseJSONL(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !columns[name] {
+			return nil, fmt.Errorf("unknown column: %s", name)
+		}
+		header[i] = name
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		number, _ := reader.FieldPos(0)
+
+		values := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				values[header[i]] = value
+			}
+		}
+		rows = append(rows, Row{Number: number, Values: values})
+	}
+
+	return rows, nil
+}
+
+func parseJSONL(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []Row
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var object map[string]any
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return nil, fmt.Errorf("invalid JSON at line %d: %w", number, err)
+		}
+
+		values := make(map[string]string, len(object))
+		for key, value := range object {
+			if !columns[key] {
+				return nil, fmt.Errorf("unknown key at line %d: %s", number, key)
+			}
+			if value != nil {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+		rows = append(rows, Row{Number: number, Values: values})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
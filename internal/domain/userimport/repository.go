@@ -0,0 +1,55 @@
+package userimport
+
+import (
+	"context"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, job *ImportJob) error
+	Save(ctx context.Context, job *ImportJob) error
+	FindByID(ctx context.Context, id int64) (*ImportJob, error)
+	FailStale(ctx context.Context, before time.Time, message string) (int64, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, job *ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *GormRepository) Save(ctx context.Context, job *ImportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *GormRepository) FindByID(ctx context.Context, id int64) (*ImportJob, error) {
+	var job ImportJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *GormRepository) FailStale(ctx context.Context, before time.Time, message string) (int64, error) {
+	now := utils.Now()
+	result := r.db.WithContext(ctx).
+		Model(&ImportJob{}).
+		Where("status IN ? AND updated_at < ?", []Status{StatusPending, StatusRunning}, before).
+		Updates(map[string]interface{}{
+			"status":       StatusFailed,
+			"message":      message,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	return result.RowsAffected, result.Error
+}
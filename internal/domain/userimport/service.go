@@ -0,0 +1,400 @@
+package userimport
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/internal/domain/invitation"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/preferences"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const staleJobAfter = 15 * time.Minute
+
+type Options struct {
+	Format          Format
+	DryRun          bool
+	SendInvitations bool
+}
+
+type candidate struct {
+	row  int
+	user *user.User
+}
+
+type Service struct {
+	repo              Repository
+	userRepo          user.UserService
+	catalog           *plancatalog.Service
+	invitationService *invitation.Service
+	maxRows           int
+	batchSize         int
+	logger            logger.Logger
+}
+
+func NewService(
+	repo Repository,
+	userRepo user.UserService,
+	catalog *plancatalog.Service,
+	invitationService *invitation.Service,
+	maxRows int,
+	batchSize int,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:              repo,
+		userRepo:          userRepo,
+		catalog:           catalog,
+		invitationService: invitationService,
+		maxRows:           maxRows,
+		batchSize:         batchSize,
+		logger:            logger,
+	}
+}
+
+// Start parses the file and runs the import in the background. The returned
+// job can be polled for progress and per-row errors.
+func (s *Service) Start(ctx context.Context, adminID int64, data []byte, opts Options) (*ImportJob, error) {
+	rows, err := parse(opts.Format, data)
+	if err != nil {
+		return nil, errors.New(errors.EINVALID, "import.invalid_file_detail", err.Error())
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New(errors.EINVALID, "import.empty_file")
+	}
+
+	if len(rows) > s.maxRows {
+		return nil, errors.New(errors.EINVALID, "import.too_many_rows", s.maxRows)
+	}
+
+	job := &ImportJob{
+		CreatedBy:       adminID,
+		Format:          opts.Format,
+		Status:          StatusPending,
+		DryRun:          opts.DryRun,
+		SendInvitations: opts.SendInvitations,
+		TotalRows:       len(rows),
+		Errors:          RowErrors{},
+		CreatedAt:       utils.Now(),
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		s.logger.Error("Failed to create import job", zap.Error(err))
+		return nil, errors.New(errors.EINTERNAL, "import.start_failed")
+	}
+
+	go s.run(context.Background(), *job, rows)
+
+	s.logger.Info("User import started",
+		zap.Int64("jobId", job.ID),
+		zap.Int64("adminId", adminID),
+		zap.Int("rows", len(rows)),
+		zap.Bool("dryRun", opts.DryRun),
+	)
+	return job, nil
+}
+
+func (s *Service) GetJob(ctx context.Context, id int64) (*ImportJob, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New(errors.ENOTFOUND, "import.not_found")
+	}
+	return job, nil
+}
+
+func (s *Service) run(ctx context.Context, job ImportJob, rows []Row) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.fail(ctx, &job, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	now := utils.Now()
+	job.Status = StatusRunning
+	job.StartedAt = &now
+	s.save(ctx, &job)
+
+	candidates, err := s.validate(ctx, &job, rows)
+	if err != nil {
+		s.fail(ctx, &job, err)
+		return
+	}
+
+	job.ValidRows = len(candidates)
+	job.FailedRows = len(job.Errors)
+
+	if !job.DryRun {
+		s.save(ctx, &job)
+
+		for start := 0; start < len(candidates); start += s.batchSize {
+			end := min(start+s.batchSize, len(candidates))
+			s.createBatch(ctx, &job, candidates[start:end])
+
+			job.ProcessedRows = job.FailedRows + job.CreatedRows
+			s.save(ctx, &job)
+		}
+	}
+
+	completedAt := utils.Now()
+	job.ProcessedRows = job.TotalRows
+	job.Status = StatusCompleted
+	job.CompletedAt = &completedAt
+	s.save(ctx, &job)
+
+	s.logger.Info("User import finished",
+		zap.Int64("jobId", job.ID),
+		zap.Int("created", job.CreatedRows),
+		zap.Int("failed", job.FailedRows),
+		zap.Bool("dryRun", job.DryRun),
+	)
+}
+
+// validate checks every row before anything is created: required fields,
+// email format, duplicates within the file and accounts that already exist.
+func (s *Service) validate(ctx context.Context, job *ImportJob, rows []Row) ([]candidate, error) {
+	seen := make(map[string]int, len(rows))
+	candidates := make([]candidate, 0, len(rows))
+
+	for _, row := range rows {
+		u, err := s.buildUser(row)
+		if err != nil {
+			job.addError(row.Number, row.get("email"), err)
+			continue
+		}
+
+		key := strings.ToLower(u.Email)
+		if first, ok := seen[key]; ok {
+			job.addError(row.Number, u.Email, errors.New(errors.EINVALID, "import.duplicate_email", strconv.Itoa(first)))
+			continue
+		}
+		seen[key] = row.Number
+
+		candidates = append(candidates, candidate{row: row.Number, user: u})
+	}
+
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	emails := make([]string, len(candidates))
+	for i, c := range candidates {
+		emails[i] = c.user.Email
+	}
+
+	existing := make(map[string]bool)
+	for start := 0; start < len(emails); start += s.batchSize {
+		end := min(start+s.batchSize, len(emails))
+		found, err := s.userRepo.FindExistingEmails(ctx, emails[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range found {
+			existing[e] = true
+		}
+	}
+
+	valid := candidates[:0]
+	for _, c := range candidates {
+		if existing[strings.ToLower(c.user.Email)] {
+			job.addError(c.row, c.user.Email, errors.New(errors.ECONFLICT, "import.email_in_use"))
+			continue
+		}
+		valid = append(valid, c)
+	}
+
+	return valid, nil
+}
+
+func (s *Service) createBatch(ctx context.Context, job *ImportJob, batch []candidate) {
+	users := make([]*user.User, len(batch))
+	for i, c := range batch {
+		users[i] = c.user
+	}
+
+	if err := s.userRepo.CreateBatch(ctx, users); err != nil {
+		// Fall back to one insert per row so a conflicting row (e.g. an
+		// account created meanwhile) does not fail the whole batch.
+		s.logger.Warn("Import batch failed, retrying row by row", zap.Int64("jobId", job.ID), zap.Error(err))
+
+		for _, c := range batch {
+			c.user.ID = 0
+			if err := s.userRepo.Create(ctx, c.user); err != nil {
+				job.addError(c.row, c.user.Email, errors.New(errors.EINTERNAL, "import.create_failed"))
+				job.FailedRows++
+				continue
+			}
+			s.created(ctx, job, c.user)
+		}
+		return
+	}
+
+	for _, u := range users {
+		s.created(ctx, job, u)
+	}
+}
+
+func (s *Service) created(ctx context.Context, job *ImportJob, u *user.User) {
+	job.CreatedRows++
+
+	if !job.SendInvitations {
+		return
+	}
+
+	if _, err := s.invitationService.CreateAndSendInvitation(ctx, u, &job.CreatedBy); err != nil {
+		s.logger.Error("Failed to send invitation for imported user", zap.Int64("userId", u.ID), zap.Error(err))
+	}
+}
+
+// FailInterrupted fails the jobs that stopped reporting progress, such as
+// those running when an instance restarted. Running jobs save their progress
+// after every batch, well within staleJobAfter.
+func (s *Service) FailInterrupted(ctx context.Context) (int64, error) {
+	failed, err := s.repo.FailStale(ctx, utils.Now().Add(-staleJobAfter), "import.interrupted")
+	if err != nil {
+		return 0, err
+	}
+
+	if failed > 0 {
+		s.logger.Warn("Interrupted user imports marked as failed", zap.Int64("count", failed))
+	}
+	return failed, nil
+}
+
+func (s *Service) fail(ctx context.Context, job *ImportJob, err error) {
+	s.logger.Error("User import failed", zap.Int64("jobId", job.ID), zap.Error(err))
+
+	message := "import.failed"
+	completedAt := utils.Now()
+	job.Status = StatusFailed
+	job.Message = &message
+	job.CompletedAt = &completedAt
+	s.save(ctx, job)
+}
+
+func (s *Service) save(ctx context.Context, job *ImportJob) {
+	if err := s.repo.Save(ctx, job); err != nil {
+		s.logger.Error("Failed to update import job", zap.Int64("jobId", job.ID), zap.Error(err))
+	}
+}
+
+func (s *Service) buildUser(row Row) (*user.User, error) {
+	name := row.get("name")
+	if name == "" {
+		return nil, errors.New(errors.EINVALID, "validation.name_required")
+	}
+
+	email := row.get("email")
+	if email == "" {
+		return nil, errors.New(errors.EINVALID, "validation.email_required")
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, errors.New(errors.EINVALID, "validation.invalid_email")
+	}
+
+	u := &user.User{
+		Name:     name,
+		Email:    email,
+		Active:   true,
+		Source:   "IMPORT",
+		Metadata: s.catalog.DefaultMetadata(),
+	}
+	u.Metadata.MustSetPassword = true
+
+	var err error
+	if v := row.get("active"); v != "" {
+		if u.Active, err = strconv.ParseBool(v); err != nil {
+			return nil, errors.New(errors.EINVALID, "import.invalid_active", v)
+		}
+	}
+	if v := row.get("source"); v != "" {
+		u.Source = strings.ToUpper(v)
+	}
+
+	planType := user.PlanTypeFree
+	if v := row.get("plan_type"); v != "" {
+		if planType, err = s.catalog.ParsePlanType(v); err != nil {
+			return nil, err
+		}
+	}
+
+	// Limits given in the file are kept as overrides of the plan catalog.
+	overrides := &user.PlanOverrides{}
+	if err := parseLimit(row, "max_accounts", &overrides.MaxAccounts); err != nil {
+		return nil, err
+	}
+	if err := parseLimit(row, "max_categories_per_account", &overrides.MaxCategoriesPerAccount); err != nil {
+		return nil, err
+	}
+	if err := parseLimit(row, "max_transactions_per_month", &overrides.MaxTransactionsPerMonth); err != nil {
+		return nil, err
+	}
+	if !overrides.IsEmpty() {
+		u.Metadata.PlanOverrides = overrides
+	}
+	if u.Metadata.PlanMetadata, err = s.catalog.Entitlements(planType, u.Metadata.PlanOverrides); err != nil {
+		return nil, err
+	}
+
+	if v := row.get("plan_expiration_date"); v != "" {
+		expiration, err := parseDate(v)
+		if err != nil {
+			return nil, errors.New(errors.EINVALID, "import.invalid_expiration", v)
+		}
+		u.Metadata.PlanExpirationDate = &expiration
+	}
+
+	if v := row.get("locale"); v != "" {
+		locale, err := preferences.NormalizeLocale(v)
+		if err != nil {
+			return nil, err
+		}
+		u.Metadata.Locale = locale
+	}
+	if v := row.get("currency"); v != "" {
+		currency, err := preferences.NormalizeCurrency(v)
+		if err != nil {
+			return nil, err
+		}
+		u.Metadata.Currency = currency
+	}
+	if v := row.get("timezone"); v != "" {
+		timezone, err := preferences.NormalizeTimezone(v)
+		if err != nil {
+			return nil, err
+		}
+		u.Metadata.Timezone = timezone
+	}
+
+	return u, nil
+}
+
+func parseLimit(row Row, column string, target **int) error {
+	v := row.get(column)
+	if v == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return errors.New(errors.EINVALID, "import.invalid_value", column, v)
+	}
+	*target = &n
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
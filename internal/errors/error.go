@@ -3,28 +3,35 @@ package errors
 import (
 	"errors"
 	"fmt"
+
+	"github.com/lkgiovani/go-boilerplate/internal/i18n"
 )
 
 const (
-	EBADREQUEST     = "bad_request"
-	ECONFLICT       = "conflict"
-	EDUPLICATION    = "duplication"
-	EEXPIRED        = "expired"
-	EFORBIDDEN      = "forbidden"
-	EINTERNAL       = "internal"
-	EINVALID        = "invalid"
-	ENOTFOUND       = "not_found"
-	ENOTIMPLEMENTED = "not_implemented"
-	EPRECONDITION   = "precondition_failed"
-	ERATELIMIT      = "rate_limit"
-	ETIMEOUT        = "timeout"
-	EUNAUTHORIZED   = "unauthorized"
-	EUNAVAILABLE    = "service_unavailable"
+	EBADREQUEST      = "bad_request"
+	ECONFLICT        = "conflict"
+	EDUPLICATION     = "duplication"
+	EEXPIRED         = "expired"
+	EFORBIDDEN       = "forbidden"
+	EINTERNAL        = "internal"
+	EINVALID         = "invalid"
+	ENOTFOUND        = "not_found"
+	ENOTIMPLEMENTED  = "not_implemented"
+	EPRECONDITION    = "precondition_failed"
+	EPRECONDREQUIRED = "precondition_required"
+	ERATELIMIT       = "rate_limit"
+	ETIMEOUT         = "timeout"
+	EUNAUTHORIZED    = "unauthorized"
+	EUNAVAILABLE     = "service_unavailable"
 )
 
 type Error struct {
 	Code string
 
+	// Key identifies the message in the i18n catalogs and Params fill its
+	// placeholders. Message holds it rendered in the default locale.
+	Key     string
+	Params  []any
 	Message string
 }
 
@@ -42,14 +49,45 @@ func ErrorCode(err error) string {
 	return EINTERNAL
 }
 
+// ErrorKey returns the catalog key of err. Errors without one are reported
+// as internal errors.
+func ErrorKey(err error) string {
+	var e *Error
+	if err == nil {
+		return ""
+	} else if errors.As(err, &e) && e.Key != "" {
+		return e.Key
+	}
+	return "common.internal_error"
+}
+
 func ErrorMessage(err error) string {
+	return LocalizedMessage(err, i18n.DefaultLocale)
+}
+
+// LocalizedMessage returns the message of err in locale. Errors created
+// without a message key keep their original message.
+func LocalizedMessage(err error, locale string) string {
 	var e *Error
 	if err == nil {
 		return ""
-	} else if errors.As(err, &e) {
-		return e.Message
+	} else if !errors.As(err, &e) {
+		return i18n.T(locale, "common.internal_error")
+	} else if e.Key != "" {
+		return i18n.T(locale, e.Key, e.Params...)
+	}
+	return e.Message
+}
+
+// New returns an error whose message is the catalog entry key, formatted
+// with params.
+func New(code, key string, params ...any) *Error {
+	return &Error{
+		Code:    code,
+		Key:     key,
+		Params:  params,
+		Message: i18n.T(i18n.DefaultLocale, key, params...),
 	}
-	return "Internal error."
 }
 
 func Errorf(code string, format string, args ...interface{}) *Error {
@@ -0,0 +1,137 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when neither the user nor the request asks for a
+// supported locale, and for messages missing from another catalog.
+const DefaultLocale = "pt-BR"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = mustLoadCatalogs()
+
+// mustLoadCatalogs reads one catalog per locale, named after the locale
+// (pt-BR.json). The files are embedded, so a broken one is a build defect.
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return loaded
+}
+
+// Supported returns the locales with a catalog, sorted.
+func Supported() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// T returns the message of key in locale, formatted with params. Missing
+// messages fall back to the default locale and then to the key itself.
+func T(locale, key string, params ...any) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(params) > 0 {
+		return fmt.Sprintf(message, params...)
+	}
+	return message
+}
+
+// Resolve returns the first candidate that matches a supported locale, or
+// the default locale. Candidates may be locale tags ("en-US", "pt_BR") or
+// Accept-Language headers.
+func Resolve(candidates ...string) string {
+	for _, candidate := range candidates {
+		if locale := Match(candidate); locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// Match returns the supported locale that best fits value, or "" when none
+// does. Tags are tried by decreasing quality, first as-is and then by their
+// language alone ("es-AR" matches "es", "pt" matches "pt-BR").
+func Match(value string) string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(value, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, weighted{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	for _, t := range tags {
+		if locale := matchTag(t.tag); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+func matchTag(tag string) string {
+	for locale := range catalogs {
+		if strings.EqualFold(locale, tag) {
+			return locale
+		}
+	}
+
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range Supported() {
+		primary, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(primary, language) {
+			return locale
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[vTtbcdoOqxXUeEfFgGsp%]`)
+
+func TestCatalogParity(t *testing.T) {
+	reference := catalogs[DefaultLocale]
+	if len(reference) == 0 {
+		t.Fatalf("catalog %s is empty", DefaultLocale)
+	}
+
+	for _, locale := range Supported() {
+		t.Run(locale, func(t *testing.T) {
+			messages := catalogs[locale]
+			for key, want := range reference {
+				got, ok := messages[key]
+				if !ok {
+					t.Errorf("missing key %s", key)
+					continue
+				}
+				if strings.TrimSpace(got) == "" {
+					t.Errorf("empty message for %s", key)
+				}
+				if gotVerbs, wantVerbs := verbPattern.FindAllString(got, -1), verbPattern.FindAllString(want, -1); !slices.Equal(gotVerbs, wantVerbs) {
+					t.Errorf("%s has verbs %v, want %v as in %s", key, gotVerbs, wantVerbs, DefaultLocale)
+				}
+			}
+			for key := range messages {
+				if _, ok := reference[key]; !ok {
+					t.Errorf("key %s is not in %s", key, DefaultLocale)
+				}
+			}
+		})
+	}
+}
+
+// keyPattern finds message keys passed to errors.New, translate and T in the
+// Go sources.
+var keyPattern = regexp.MustCompile(`(?:errors\.New\(errors\.E[A-Z]+|translate\(c|i18n\.T\([^,()]+), "([a-z0-9_]+(?:\.[a-z0-9_]+)+)"`)
+
+func TestSourceKeysExist(t *testing.T) {
+	root := filepath.Join("..", "..")
+	reference := catalogs[DefaultLocale]
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); (name != ".." && strings.HasPrefix(name, ".")) || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range keyPattern.FindAllStringSubmatch(string(source), -1) {
+			if _, ok := reference[match[1]]; !ok {
+				t.Errorf("%s: key %s is not in the catalog", path, match[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		params []any
+		want   string
+	}{
+		{"locale message", "en", "auth.required", nil, catalogs["en"]["auth.required"]},
+		{"unsupported locale falls back", "fr", "auth.required", nil, catalogs[DefaultLocale]["auth.required"]},
+		{"unknown key returns the key", "en", "does.not_exist", nil, "does.not_exist"},
+		{"params are formatted", "en", "export.invalid_column", []any{"foo"}, strings.Replace(catalogs["en"]["export.invalid_column"], "%s", "foo", 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.locale, tt.key, tt.params...); got != tt.want {
+				t.Errorf("T(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{"exact tag", []string{"en"}, "en"},
+		{"region falls back to language", []string{"es-AR"}, "es"},
+		{"language matches a regional catalog", []string{"pt"}, "pt-BR"},
+		{"underscore separator", []string{"pt_BR"}, "pt-BR"},
+		{"accept-language by quality", []string{"fr;q=0.9, en;q=0.8, es;q=0.95"}, "es"},
+		{"zero quality is ignored", []string{"en;q=0, es"}, "es"},
+		{"first matching candidate wins", []string{"", "fr", "en-GB", "es"}, "en"},
+		{"nothing supported", []string{"fr", "*"}, DefaultLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resolve(tt.candidates...); got != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.candidates, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log"
@@ -19,30 +20,68 @@ import (
 const (
 	AccessTokenCookieName  = "access_token"
 	RefreshTokenCookieName = "refresh_token"
+
+	ScopePasswordChange = "password_change"
+
+	ProfileWeb         = "web"
+	ProfileWebRemember = "web-remember"
+	ProfileMobile      = "mobile"
+	ProfileCLI         = "cli"
 )
 
 type CustomClaims struct {
-	ID         string   `json:"id"`
-	Name       string   `json:"name"`
-	Email      string   `json:"email"`
-	Roles      []string `json:"roles,omitempty"`
-	Plan       string   `json:"plan,omitempty"`
-	AccessMode string   `json:"access_mode,omitempty"`
-	Jti        string   `json:"jti,omitempty"`
-	Type       string   `json:"type,omitempty"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Email       string   `json:"email"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Plan        string   `json:"plan,omitempty"`
+	Features    []string `json:"features,omitempty"`
+	AccessMode  string   `json:"access_mode,omitempty"`
+	Locale      string   `json:"locale,omitempty"`
+	Jti         string   `json:"jti,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
+	Profile     string   `json:"profile,omitempty"`
+	Sid         string   `json:"sid,omitempty"`
+	OrgID       string   `json:"org_id,omitempty"`
+	OrgRole     string   `json:"org_role,omitempty"`
 	jwt.StandardClaims
 }
 
+type TokenProfile struct {
+	Name                     string
+	ClientType               string
+	Audience                 string
+	AccessTokenTTL           int64
+	RefreshTokenTTL          int64
+	AccessTokenCookieMaxAge  int
+	RefreshTokenCookieMaxAge int
+}
+
+type SessionOptions struct {
+	Profile          string
+	SessionID        string
+	Roles            []string
+	Permissions      []string
+	OrganizationID   int64
+	OrganizationRole string
+	Plan             *user.PlanMetadata
+	// RefreshTokenTTL, in seconds, shortens the refresh token of the profile
+	// when set.
+	RefreshTokenTTL int64
+}
+
 type JwtService struct {
-	secretKey                string
-	issuer                   string
-	audience                 string
-	cookieDomain             string
-	tokenTTL                 int64
-	accessTokenCookieMaxAge  int
-	refreshTokenCookieMaxAge int
-	parser                   *jwt.Parser
-	userService              *user.Service
+	secretKey    string
+	issuer       string
+	audience     string
+	cookieDomain string
+	tokenTTL     int64
+	profiles     map[string]TokenProfile
+	cliAPIKeys   []string
+	parser       *jwt.Parser
+	userService  *user.Service
 }
 
 func NewJwtService(settings config.JWTConfig, userService *user.Service) (*JwtService, error) {
@@ -50,25 +89,93 @@ func NewJwtService(settings config.JWTConfig, userService *user.Service) (*JwtSe
 		return nil, errors.New("JWT_EXPIRATION_MS inválido")
 	}
 
+	refreshTokenDays := settings.RefreshTokenExpiration
+	if refreshTokenDays <= 0 {
+		refreshTokenDays = 7
+	}
+
 	audience := settings.Audience
 	if audience == "" {
 		audience = "boilerplate-api"
 	}
 
+	profiles := make(map[string]TokenProfile, len(settings.Profiles)+1)
+	for name, p := range settings.Profiles {
+		if p.AccessTokenTTLSeconds <= 0 || p.RefreshTokenTTLSeconds <= 0 {
+			return nil, fmt.Errorf("perfil de token %q inválido", name)
+		}
+
+		profileAudience := p.Audience
+		if profileAudience == "" {
+			profileAudience = audience
+		}
+
+		profiles[name] = TokenProfile{
+			Name:                     name,
+			ClientType:               p.ClientType,
+			Audience:                 profileAudience,
+			AccessTokenTTL:           int64(p.AccessTokenTTLSeconds),
+			RefreshTokenTTL:          int64(p.RefreshTokenTTLSeconds),
+			AccessTokenCookieMaxAge:  p.AccessTokenCookieMaxAge,
+			RefreshTokenCookieMaxAge: p.RefreshTokenCookieMaxAge,
+		}
+	}
+
+	if _, ok := profiles[ProfileWeb]; !ok {
+		profiles[ProfileWeb] = TokenProfile{
+			Name:                     ProfileWeb,
+			ClientType:               "web",
+			Audience:                 audience,
+			AccessTokenTTL:           int64(settings.ExpirationMs / 1000),
+			RefreshTokenTTL:          int64(refreshTokenDays) * 24 * 60 * 60,
+			AccessTokenCookieMaxAge:  settings.AccessTokenCookieMaxAge,
+			RefreshTokenCookieMaxAge: settings.RefreshTokenCookieMaxAge,
+		}
+	}
+
 	parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Name}}
 	return &JwtService{
-		secretKey:                settings.SecretKey,
-		issuer:                   settings.Issuer,
-		audience:                 audience,
-		cookieDomain:             settings.CookieDomain,
-		tokenTTL:                 int64(settings.ExpirationMs / 1000),
-		accessTokenCookieMaxAge:  settings.AccessTokenCookieMaxAge,
-		refreshTokenCookieMaxAge: settings.RefreshTokenCookieMaxAge,
-		parser:                   parser,
-		userService:              userService,
+		secretKey:    settings.SecretKey,
+		issuer:       settings.Issuer,
+		audience:     audience,
+		cookieDomain: settings.CookieDomain,
+		tokenTTL:     int64(settings.ExpirationMs / 1000),
+		profiles:     profiles,
+		cliAPIKeys:   settings.CLIAPIKeys,
+		parser:       parser,
+		userService:  userService,
 	}, nil
 }
 
+func (s *JwtService) Profile(name string) TokenProfile {
+	if p, ok := s.profiles[name]; ok {
+		return p
+	}
+	return s.profiles[ProfileWeb]
+}
+
+// WithRefreshTTL caps the refresh token lifetime, and its cookie, at ttl
+// seconds. A ttl of zero leaves the profile unchanged.
+func (p TokenProfile) WithRefreshTTL(ttl int64) TokenProfile {
+	if ttl <= 0 || ttl >= p.RefreshTokenTTL {
+		return p
+	}
+	p.RefreshTokenTTL = ttl
+	if p.RefreshTokenCookieMaxAge > int(ttl) {
+		p.RefreshTokenCookieMaxAge = int(ttl)
+	}
+	return p
+}
+
+func (s *JwtService) IsCLIAPIKey(key string) bool {
+	for _, k := range s.cliAPIKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *JwtService) GetTokenFromCookie(r *http.Request) (string, error) {
 	cookie, err := r.Cookie(AccessTokenCookieName)
 	if err != nil {
@@ -91,34 +198,80 @@ func (s *JwtService) GenerateTokenFromUser(ctx context.Context, u *user.User) (s
 }
 
 func (s *JwtService) GenerateAccessToken(u *user.User) (string, *CustomClaims, error) {
-	return s.generateToken(u, s.tokenTTL, "access")
+	profile := s.Profile(ProfileWeb)
+	return s.generateToken(u, profile, profile.AccessTokenTTL, "access", "", SessionOptions{})
 }
 
 func (s *JwtService) GenerateRefreshToken(u *user.User) (string, *CustomClaims, error) {
-	return s.generateToken(u, s.tokenTTL*7, "refresh")
+	profile := s.Profile(ProfileWeb)
+	return s.generateToken(u, profile, profile.RefreshTokenTTL, "refresh", "", SessionOptions{})
 }
 
-func (s *JwtService) generateToken(u *user.User, ttl int64, tokenType string) (string, *CustomClaims, error) {
+func (s *JwtService) GeneratePasswordChangeToken(u *user.User, profileName string) (string, *http.Cookie, error) {
+	profile := s.Profile(profileName)
+	token, _, err := s.generateToken(u, profile, profile.AccessTokenTTL, "access", ScopePasswordChange, SessionOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	cookie := &http.Cookie{
+		Name:     AccessTokenCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   profile.AccessTokenCookieMaxAge,
+		Secure:   s.cookieDomain != "",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if s.cookieDomain != "" {
+		cookie.Domain = s.cookieDomain
+	}
+
+	return token, cookie, nil
+}
+
+func (s *JwtService) generateToken(u *user.User, profile TokenProfile, ttl int64, tokenType, scope string, opts SessionOptions) (string, *CustomClaims, error) {
 	now := utils.Now().Unix()
 
-	roles := []string{"USER"}
-	if u.Admin {
-		roles = append(roles, "ADMIN")
+	roles := opts.Roles
+	if roles == nil {
+		roles = []string{"USER"}
+		if u.Admin {
+			roles = append(roles, "ADMIN")
+		}
+	}
+
+	plan, features := u.Metadata.PlanType, u.Metadata.Features
+	if opts.Plan != nil {
+		plan, features = opts.Plan.PlanType, opts.Plan.Features
+	}
+
+	var orgID string
+	if opts.OrganizationID > 0 {
+		orgID = strconv.FormatInt(opts.OrganizationID, 10)
 	}
 
 	claims := CustomClaims{
-		ID:         strconv.FormatInt(u.ID, 10),
-		Name:       u.Name,
-		Email:      u.Email,
-		Roles:      roles,
-		Plan:       string(u.Metadata.PlanType),
-		AccessMode: string(u.Metadata.AccessMode),
-		Jti:        uuid.New().String(),
-		Type:       tokenType,
+		ID:          strconv.FormatInt(u.ID, 10),
+		Name:        u.Name,
+		Email:       u.Email,
+		Roles:       roles,
+		Permissions: opts.Permissions,
+		Plan:        string(plan),
+		Features:    features,
+		AccessMode:  string(u.Metadata.AccessMode),
+		Locale:      u.Metadata.Locale,
+		Jti:         uuid.New().String(),
+		Type:        tokenType,
+		Scope:       scope,
+		Profile:     profile.Name,
+		Sid:         opts.SessionID,
+		OrgID:       orgID,
+		OrgRole:     opts.OrganizationRole,
 		StandardClaims: jwt.StandardClaims{
 			Subject:   u.Email,
 			Issuer:    s.issuer,
-			Audience:  s.audience,
+			Audience:  profile.Audience,
 			IssuedAt:  now,
 			ExpiresAt: now + ttl,
 		},
@@ -170,13 +323,15 @@ func (s *JwtService) GenerateToken(ctx context.Context, id string) (string, erro
 	return token.SignedString([]byte(s.secretKey))
 }
 
-func (s *JwtService) GenerateCookies(u *user.User) (string, string, *CustomClaims, []*http.Cookie, error) {
-	accessToken, _, err := s.GenerateAccessToken(u)
+func (s *JwtService) GenerateCookies(u *user.User, opts SessionOptions) (string, string, *CustomClaims, []*http.Cookie, error) {
+	profile := s.Profile(opts.Profile).WithRefreshTTL(opts.RefreshTokenTTL)
+
+	accessToken, _, err := s.generateToken(u, profile, profile.AccessTokenTTL, "access", "", opts)
 	if err != nil {
 		return "", "", nil, nil, err
 	}
 
-	refreshToken, refreshClaims, err := s.GenerateRefreshToken(u)
+	refreshToken, refreshClaims, err := s.generateToken(u, profile, profile.RefreshTokenTTL, "refresh", "", opts)
 	if err != nil {
 		return "", "", nil, nil, err
 	}
@@ -188,7 +343,7 @@ func (s *JwtService) GenerateCookies(u *user.User) (string, string, *CustomClaim
 			Name:     AccessTokenCookieName,
 			Value:    accessToken,
 			Path:     "/",
-			MaxAge:   s.accessTokenCookieMaxAge,
+			MaxAge:   profile.AccessTokenCookieMaxAge,
 			Secure:   isSecure,
 			HttpOnly: true,
 			SameSite: http.SameSiteLaxMode,
@@ -197,7 +352,7 @@ func (s *JwtService) GenerateCookies(u *user.User) (string, string, *CustomClaim
 			Name:     RefreshTokenCookieName,
 			Value:    refreshToken,
 			Path:     "/v1/auth/refresh",
-			MaxAge:   s.refreshTokenCookieMaxAge,
+			MaxAge:   profile.RefreshTokenCookieMaxAge,
 			Secure:   isSecure,
 			HttpOnly: true,
 			SameSite: http.SameSiteLaxMode,
@@ -214,7 +369,7 @@ func (s *JwtService) GenerateCookies(u *user.User) (string, string, *CustomClaim
 }
 
 func (s *JwtService) GenerateCookie(u *user.User, r *http.Request) (*http.Cookie, error) {
-	_, _, _, cookies, err := s.GenerateCookies(u)
+	_, _, _, cookies, err := s.GenerateCookies(u, SessionOptions{Profile: ProfileWeb})
 	if err != nil {
 		return nil, err
 	}
@@ -290,8 +445,8 @@ func (s *JwtService) ValidateToken(tokenString string) bool {
 		return false
 	}
 
-	if !claims.VerifyAudience(s.audience, true) {
-		log.Printf("Invalid JWT audience: expected %s", s.audience)
+	if audience := s.Profile(claims.Profile).Audience; !claims.VerifyAudience(audience, true) {
+		log.Printf("Invalid JWT audience: expected %s", audience)
 		return false
 	}
 
@@ -316,7 +471,7 @@ func (s *JwtService) ParseToken(tokenString string) (*CustomClaims, error) {
 		return nil, fmt.Errorf("invalid issuer")
 	}
 
-	if !claims.VerifyAudience(s.audience, true) {
+	if !claims.VerifyAudience(s.Profile(claims.Profile).Audience, true) {
 		return nil, fmt.Errorf("invalid audience")
 	}
 
@@ -327,12 +482,12 @@ func (s *JwtService) ParseToken(tokenString string) (*CustomClaims, error) {
 	return claims, nil
 }
 
-func (s *JwtService) GetAccessTokenExpirationSeconds() int64 {
-	return s.tokenTTL
+func (s *JwtService) GetAccessTokenExpirationSeconds(profileName string) int64 {
+	return s.Profile(profileName).AccessTokenTTL
 }
 
-func (s *JwtService) GetRefreshTokenExpirationSeconds() int64 {
-	return s.tokenTTL * 7
+func (s *JwtService) GetRefreshTokenExpirationSeconds(profileName string) int64 {
+	return s.Profile(profileName).RefreshTokenTTL
 }
 
 func (s *JwtService) parseCustomClaims(tokenString string) (*CustomClaims, error) {
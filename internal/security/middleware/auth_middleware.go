@@ -1,25 +1,90 @@
 package middleware
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/organization"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/plancatalog"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
 	"github.com/lkgiovani/go-boilerplate/internal/errors"
 	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
 )
 
 type AuthMiddleware struct {
-	jwtService *jwt.JwtService
+	jwtService  *jwt.JwtService
+	planCatalog *plancatalog.Service
+	security    *security.Service
 }
 
-func NewAuthMiddleware(jwtService *jwt.JwtService) *AuthMiddleware {
+func NewAuthMiddleware(jwtService *jwt.JwtService, planCatalog *plancatalog.Service, securityService *security.Service) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:  jwtService,
+		planCatalog: planCatalog,
+		security:    securityService,
 	}
 }
 
 func (m *AuthMiddleware) Authenticate(c *fiber.Ctx) error {
+	claims, err := m.parseRequestToken(c)
+	if err != nil {
+		return err
+	}
+
+	if claims.Type != "access" {
+		return errors.New(errors.EUNAUTHORIZED, "auth.invalid_token_type")
+	}
+
+	if claims.Scope == jwt.ScopePasswordChange {
+		return errors.New(errors.EFORBIDDEN, "auth.password_change_pending")
+	}
+
+	if err := m.ensureNotBlocked(c, claims); err != nil {
+		return err
+	}
+
+	setClaimsLocals(c, claims)
+
+	return c.Next()
+}
+
+func (m *AuthMiddleware) AuthenticatePasswordChange(c *fiber.Ctx) error {
+	claims, err := m.parseRequestToken(c)
+	if err != nil {
+		return err
+	}
+
+	if claims.Type != "access" || claims.Scope != jwt.ScopePasswordChange {
+		return errors.New(errors.EFORBIDDEN, "auth.access_denied")
+	}
+
+	if err := m.ensureNotBlocked(c, claims); err != nil {
+		return err
+	}
+
+	setClaimsLocals(c, claims)
+
+	return c.Next()
+}
+
+// ensureNotBlocked rejects tokens of blocked users. Blocks also revoke the
+// refresh tokens, so this only matters until the access token expires.
+func (m *AuthMiddleware) ensureNotBlocked(c *fiber.Ctx, claims *jwt.CustomClaims) error {
+	uid, _ := strconv.ParseInt(claims.ID, 10, 64)
+	blocked, err := m.security.IsBlocked(c.UserContext(), uid)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.New(errors.EFORBIDDEN, "security.account_blocked")
+	}
+	return nil
+}
+
+func (m *AuthMiddleware) parseRequestToken(c *fiber.Ctx) (*jwt.CustomClaims, error) {
 	var token string
 
 	authHeader := c.Get("Authorization")
@@ -36,42 +101,56 @@ func (m *AuthMiddleware) Authenticate(c *fiber.Ctx) error {
 	}
 
 	if token == "" {
-		return errors.Errorf(errors.EUNAUTHORIZED, "Authentication required")
+		return nil, errors.New(errors.EUNAUTHORIZED, "auth.required")
 	}
 
 	claims, err := m.jwtService.ParseToken(token)
 	if err != nil {
-		return errors.Errorf(errors.EUNAUTHORIZED, "Invalid or expired token")
+		return nil, errors.New(errors.EUNAUTHORIZED, "auth.invalid_token")
 	}
 
+	return claims, nil
+}
+
+func setClaimsLocals(c *fiber.Ctx, claims *jwt.CustomClaims) {
 	uid, _ := strconv.ParseInt(claims.ID, 10, 64)
 	c.Locals("userID", uid)
 	c.Locals("userEmail", claims.Email)
 	c.Locals("userName", claims.Name)
 	c.Locals("userRoles", claims.Roles)
+	c.Locals("userPermissions", claims.Permissions)
 	c.Locals("userPlan", claims.Plan)
+	c.Locals("userFeatures", claims.Features)
 	c.Locals("userAccessMode", claims.AccessMode)
+	c.Locals("userLocale", claims.Locale)
+	c.Locals("tokenProfile", claims.Profile)
 
-	return c.Next()
+	orgID, _ := strconv.ParseInt(claims.OrgID, 10, 64)
+	c.Locals("organizationID", orgID)
+	c.Locals("organizationRole", claims.OrgRole)
+	if orgID > 0 {
+		c.SetUserContext(organization.WithOrganizationID(c.UserContext(), orgID))
+	}
 }
 
 func (m *AuthMiddleware) RequireRole(role string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		roles, ok := c.Locals("userRoles").([]string)
 		if !ok {
-			return errors.Errorf(errors.EFORBIDDEN, "Access denied")
+			return errors.New(errors.EFORBIDDEN, "auth.access_denied")
 		}
 
 		hasRole := false
 		for _, r := range roles {
-			if r == role {
+			if strings.EqualFold(r, role) {
 				hasRole = true
 				break
 			}
 		}
 
 		if !hasRole {
-			return errors.Errorf(errors.EFORBIDDEN, "Insufficient permissions")
+			m.reportDenied(c, security.Details{"role": role})
+			return errors.New(errors.EFORBIDDEN, "auth.insufficient_permissions")
 		}
 
 		return c.Next()
@@ -82,11 +161,41 @@ func (m *AuthMiddleware) RequireAdmin(c *fiber.Ctx) error {
 	return m.RequireRole("ADMIN")(c)
 }
 
+func (m *AuthMiddleware) RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := c.Locals("userPermissions").([]string); !ok {
+			return errors.New(errors.EFORBIDDEN, "auth.access_denied")
+		}
+
+		if HasPermission(c, permission) {
+			return c.Next()
+		}
+
+		m.reportDenied(c, security.Details{"permission": permission})
+		return errors.New(errors.EFORBIDDEN, "auth.insufficient_permissions")
+	}
+}
+
+// HasPermission reports whether the authenticated user holds permission, for
+// handlers whose options need more than the permission of the route.
+func HasPermission(c *fiber.Ctx, permission string) bool {
+	permissions, _ := c.Locals("userPermissions").([]string)
+	return slices.Contains(permissions, permission)
+}
+
+// reportDenied records an authenticated request to a route the user has no
+// access to.
+func (m *AuthMiddleware) reportDenied(c *fiber.Ctx, details security.Details) {
+	if userID, ok := c.Locals("userID").(int64); ok && userID != 0 {
+		m.security.Report(newActivity(c, userID, security.ActivityUnauthorizedAccess, details))
+	}
+}
+
 func (m *AuthMiddleware) RequirePlan(plans ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userPlan, ok := c.Locals("userPlan").(string)
 		if !ok {
-			return errors.Errorf(errors.EFORBIDDEN, "Access denied")
+			return errors.New(errors.EFORBIDDEN, "auth.access_denied")
 		}
 
 		hasPlan := false
@@ -98,7 +207,7 @@ func (m *AuthMiddleware) RequirePlan(plans ...string) fiber.Handler {
 		}
 
 		if !hasPlan {
-			return errors.Errorf(errors.EFORBIDDEN, "Seu plano atual não permite acesso a este recurso")
+			return errors.New(errors.EFORBIDDEN, "plan.access_denied")
 		}
 
 		return c.Next()
@@ -109,20 +218,28 @@ func (m *AuthMiddleware) RequireMinPlan(minPlan string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userPlan, ok := c.Locals("userPlan").(string)
 		if !ok {
-			return errors.Errorf(errors.EFORBIDDEN, "Access denied")
+			return errors.New(errors.EFORBIDDEN, "auth.access_denied")
 		}
 
-		planLevels := map[string]int{
-			"FREE":       1,
-			"PRO":        2,
-			"ENTERPRISE": 3,
+		// Ranks come from the plan catalog; unknown plans never pass.
+		userRank, userOK := m.planCatalog.Rank(user.PlanType(strings.ToUpper(userPlan)))
+		minRank, minOK := m.planCatalog.Rank(user.PlanType(strings.ToUpper(minPlan)))
+
+		if !userOK || !minOK || userRank < minRank {
+			return errors.New(errors.EFORBIDDEN, "plan.min_required", minPlan)
 		}
 
-		userLevel := planLevels[strings.ToUpper(userPlan)]
-		minLevel := planLevels[strings.ToUpper(minPlan)]
+		return c.Next()
+	}
+}
 
-		if userLevel < minLevel {
-			return errors.Errorf(errors.EFORBIDDEN, "Este recurso requer um plano %s ou superior", minPlan)
+// RequireFeature allows the request when the feature is enabled for the plan
+// carried by the access token, including the user's own overrides.
+func (m *AuthMiddleware) RequireFeature(feature string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		features, ok := c.Locals("userFeatures").([]string)
+		if !ok || !slices.Contains(features, feature) {
+			return errors.New(errors.EFORBIDDEN, "feature.not_included")
 		}
 
 		return c.Next()
@@ -132,12 +249,29 @@ func (m *AuthMiddleware) RequireMinPlan(minPlan string) fiber.Handler {
 func (m *AuthMiddleware) RequireWriteAccess(c *fiber.Ctx) error {
 	accessMode, ok := c.Locals("userAccessMode").(string)
 	if !ok {
-		return errors.Errorf(errors.EFORBIDDEN, "Access denied")
+		return errors.New(errors.EFORBIDDEN, "auth.access_denied")
 	}
 
 	if !strings.EqualFold(accessMode, "READ_WRITE") {
-		return errors.Errorf(errors.EFORBIDDEN, "Sua conta está em modo de apenas leitura ou desativada")
+		return errors.New(errors.EFORBIDDEN, "auth.read_only")
 	}
 
 	return c.Next()
 }
+
+func (m *AuthMiddleware) RequireOrganizationRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		orgRole, ok := c.Locals("organizationRole").(string)
+		if !ok || orgRole == "" {
+			return errors.New(errors.EFORBIDDEN, "auth.access_denied")
+		}
+
+		for _, r := range roles {
+			if strings.EqualFold(orgRole, r) {
+				return c.Next()
+			}
+		}
+
+		return errors.New(errors.EFORBIDDEN, "auth.insufficient_permissions")
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/jwt"
+)
+
+func TestAuthenticateRejectsNonAccessTokens(t *testing.T) {
+	jwtService, err := jwt.NewJwtService(config.JWTConfig{SecretKey: "test-secret", Issuer: "test", ExpirationMs: 60000}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &user.User{ID: 7, Email: "user@example.com"}
+
+	refreshToken, _, err := jwtService.GenerateRefreshToken(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passwordChangeToken, _, err := jwtService.GeneratePasswordChangeToken(u, jwt.ProfileWeb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		handler func(m *AuthMiddleware) fiber.Handler
+		token   string
+		wantKey string
+	}{
+		{"refresh token as bearer", func(m *AuthMiddleware) fiber.Handler { return m.Authenticate }, refreshToken, "auth.invalid_token_type"},
+		{"password change token", func(m *AuthMiddleware) fiber.Handler { return m.Authenticate }, passwordChangeToken, "auth.password_change_pending"},
+		{"refresh token on password change", func(m *AuthMiddleware) fiber.Handler { return m.AuthenticatePasswordChange }, refreshToken, "auth.access_denied"},
+		{"invalid token", func(m *AuthMiddleware) fiber.Handler { return m.Authenticate }, "not-a-token", "auth.invalid_token"},
+		{"missing token", func(m *AuthMiddleware) fiber.Handler { return m.Authenticate }, "", "auth.required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got error
+			app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+				got = err
+				return c.SendStatus(fiber.StatusUnauthorized)
+			}})
+			app.Get("/", tt.handler(NewAuthMiddleware(jwtService, nil, nil)), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.token != "" {
+				req.Header.Set(fiber.HeaderAuthorization, "Bearer "+tt.token)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+
+			if got == nil || errors.ErrorKey(got) != tt.wantKey {
+				t.Fatalf("error = %v, want %s", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestRequireFeature(t *testing.T) {
+	tests := []struct {
+		name     string
+		features any
+		wantKey  string
+	}{
+		{"feature enabled", []string{"reports", "export"}, ""},
+		{"feature missing", []string{"reports"}, "feature.not_included"},
+		{"no features", []string{}, "feature.not_included"},
+		{"unauthenticated", nil, "feature.not_included"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got error
+			app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+				got = err
+				return c.SendStatus(fiber.StatusForbidden)
+			}})
+			m := NewAuthMiddleware(nil, nil, nil)
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.features != nil {
+					c.Locals("userFeatures", tt.features)
+				}
+				return c.Next()
+			}, m.RequireFeature("export"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantKey == "" {
+				if resp.StatusCode != fiber.StatusOK {
+					t.Fatalf("status = %d, error = %v, want 200", resp.StatusCode, got)
+				}
+				return
+			}
+			if got == nil || errors.ErrorKey(got) != tt.wantKey {
+				t.Fatalf("error = %v, want %s", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions any
+		want        bool
+	}{
+		{"granted", []string{"users:create", "roles:manage"}, true},
+		{"missing", []string{"users:create"}, false},
+		{"unauthenticated", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.permissions != nil {
+					c.Locals("userPermissions", tt.permissions)
+				}
+				got = HasPermission(c, "roles:manage")
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil)); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("HasPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+type ClientAuthMiddleware struct {
+	apiKeys []string
+	clients map[string]string
+}
+
+func NewClientAuthMiddleware(cfg *config.Config) *ClientAuthMiddleware {
+	return &ClientAuthMiddleware{
+		apiKeys: cfg.Introspection.APIKeys,
+		clients: cfg.Introspection.Clients,
+	}
+}
+
+func (m *ClientAuthMiddleware) Authenticate(c *fiber.Ctx) error {
+	if apiKey := c.Get("X-API-Key"); apiKey != "" {
+		for _, key := range m.apiKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+				c.Locals("clientID", "api-key")
+				return c.Next()
+			}
+		}
+		return errors.New(errors.EUNAUTHORIZED, "auth.invalid_client_credentials")
+	}
+
+	clientID, clientSecret, ok := basicAuth(c)
+	if !ok {
+		clientID = c.FormValue("client_id")
+		clientSecret = c.FormValue("client_secret")
+	}
+
+	if clientID == "" || clientSecret == "" {
+		return errors.New(errors.EUNAUTHORIZED, "auth.client_auth_required")
+	}
+
+	expected, exists := m.clients[clientID]
+	if !exists || subtle.ConstantTimeCompare([]byte(expected), []byte(clientSecret)) != 1 {
+		return errors.New(errors.EUNAUTHORIZED, "auth.invalid_client_credentials")
+	}
+
+	c.Locals("clientID", clientID)
+	return c.Next()
+}
+
+func basicAuth(c *fiber.Ctx) (string, string, bool) {
+	authHeader := c.Get("Authorization")
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "basic") {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(decoded), ":")
+}
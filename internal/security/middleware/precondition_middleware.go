@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+// RequireIfMatch rejects changes to the user in the param route parameter
+// that do not send the ETag they were based on. The version is checked when
+// the change is written, so handlers must use c.UserContext().
+func RequireIfMatch(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := strconv.ParseInt(c.Params(param), 10, 64)
+		if err != nil {
+			return errors.New(errors.EBADREQUEST, "user.invalid_id")
+		}
+
+		return requireIfMatch(c, userID)
+	}
+}
+
+// RequireOwnIfMatch is RequireIfMatch for the routes where users change
+// their own profile. Must run after Authenticate.
+func RequireOwnIfMatch(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok || userID == 0 {
+		return errors.New(errors.EUNAUTHORIZED, "auth.not_authenticated")
+	}
+	return requireIfMatch(c, userID)
+}
+
+func requireIfMatch(c *fiber.Ctx, userID int64) error {
+	value := c.Get(fiber.HeaderIfMatch)
+	if value == "" {
+		return errors.New(errors.EPRECONDREQUIRED, "common.if_match_required")
+	}
+	if value == "*" {
+		return c.Next()
+	}
+
+	version, ok := user.ParseETag(value)
+	if !ok {
+		return user.ErrVersionConflict
+	}
+
+	c.SetUserContext(user.WithExpectedVersion(c.UserContext(), userID, version))
+	return c.Next()
+}
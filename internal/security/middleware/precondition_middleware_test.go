@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+)
+
+func TestRequireIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		ifMatch     string
+		wantKey     string
+		wantVersion int64
+	}{
+		{"strong tag", "/7", `"3"`, "", 3},
+		{"weak tag", "/7", `W/"3"`, "", 3},
+		{"any version", "/7", "*", "", 0},
+		{"missing header", "/7", "", "common.if_match_required", 0},
+		{"malformed tag", "/7", "3", "user.version_conflict", 0},
+		{"invalid id", "/abc", `"3"`, "user.invalid_id", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got error
+			var version int64
+			app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+				got = err
+				return c.SendStatus(fiber.StatusPreconditionFailed)
+			}})
+			app.Put("/:id", RequireIfMatch("id"), func(c *fiber.Ctx) error {
+				version, _ = user.ExpectedVersionFromContext(c.UserContext(), 7)
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodPut, tt.path, nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.ifMatch)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantKey != "" {
+				if got == nil || errors.ErrorKey(got) != tt.wantKey {
+					t.Fatalf("error = %v, want %s", got, tt.wantKey)
+				}
+				return
+			}
+			if got != nil {
+				t.Fatalf("error = %v", got)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("expected version = %d, want %d", version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestRequireOwnIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  any
+		ifMatch string
+		wantKey string
+	}{
+		{"authenticated", int64(7), `"3"`, ""},
+		{"missing header", int64(7), "", "common.if_match_required"},
+		{"unauthenticated", nil, `"3"`, "auth.not_authenticated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got error
+			var version int64
+			app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+				got = err
+				return c.SendStatus(fiber.StatusPreconditionFailed)
+			}})
+			app.Put("/", func(c *fiber.Ctx) error {
+				if tt.userID != nil {
+					c.Locals("userID", tt.userID)
+				}
+				return c.Next()
+			}, RequireOwnIfMatch, func(c *fiber.Ctx) error {
+				version, _ = user.ExpectedVersionFromContext(c.UserContext(), 7)
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.ifMatch)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantKey != "" {
+				if got == nil || errors.ErrorKey(got) != tt.wantKey {
+					t.Fatalf("error = %v, want %s", got, tt.wantKey)
+				}
+				return
+			}
+			if got != nil {
+				t.Fatalf("error = %v", got)
+			}
+			if version != 3 {
+				t.Errorf("expected version = %d, want 3", version)
+			}
+		})
+	}
+}
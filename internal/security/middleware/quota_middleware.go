@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/metering"
+)
+
+type QuotaMiddleware struct {
+	metering *metering.Service
+}
+
+func NewQuotaMiddleware(meteringService *metering.Service) *QuotaMiddleware {
+	return &QuotaMiddleware{
+		metering: meteringService,
+	}
+}
+
+// MeterRequests counts the request against the monthly request quota of the
+// authenticated user and rejects it once the quota is exhausted. Must run
+// after Authenticate.
+func (m *QuotaMiddleware) MeterRequests(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok || userID == 0 {
+		return c.Next()
+	}
+
+	quota, err := m.metering.Consume(c.UserContext(), userID, metering.MetricRequests)
+	if quota.Limit != nil {
+		c.Set("X-Quota-Limit", strconv.Itoa(*quota.Limit))
+		c.Set("X-Quota-Remaining", strconv.FormatInt(quota.Remaining(), 10))
+		c.Set("X-Quota-Reset", strconv.FormatInt(quota.ResetAt.Unix(), 10))
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Next()
+}
+
+// MeterResources enforces the monthly resource quota on routes that create
+// resources. Only successful responses are counted.
+func (m *QuotaMiddleware) MeterResources(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok || userID == 0 {
+		return c.Next()
+	}
+
+	if err := m.metering.Allow(c.UserContext(), userID, metering.MetricResources); err != nil {
+		return err
+	}
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+
+	if c.Response().StatusCode() < fiber.StatusMultipleChoices {
+		m.metering.Record(userID, metering.MetricResources, 1)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/metering"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/user"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+// usageRepo starts every counter at zero.
+type usageRepo struct {
+	metering.Repository
+}
+
+func (usageRepo) Count(context.Context, int64, time.Time, metering.Metric) (int64, error) {
+	return 0, nil
+}
+
+type quotaUserRepo struct {
+	user.UserService
+	limit *int
+}
+
+func (r quotaUserRepo) GetByID(_ context.Context, id int64) (*user.User, error) {
+	u := &user.User{ID: id}
+	u.Metadata.MaxRequestsPerMonth = r.limit
+	return u, nil
+}
+
+func TestMeterRequests(t *testing.T) {
+	limit := 2
+	reset := strconv.FormatInt(metering.PeriodEnd(metering.PeriodStart(utils.Now())).Unix(), 10)
+
+	tests := []struct {
+		name          string
+		limit         *int
+		requests      int
+		wantStatus    int
+		wantRemaining string
+	}{
+		{"within quota", &limit, 1, fiber.StatusOK, "1"},
+		{"last request", &limit, 2, fiber.StatusOK, "0"},
+		{"quota exhausted", &limit, 3, fiber.StatusTooManyRequests, "0"},
+		{"unlimited", nil, 3, fiber.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := logger.NewLogger("test", "none")
+			m := NewQuotaMiddleware(metering.NewService(usageRepo{}, quotaUserRepo{limit: tt.limit}, time.Minute, log))
+
+			var rendered error
+			app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+				rendered = err
+				if errors.ErrorCode(err) == errors.ERATELIMIT {
+					return c.SendStatus(fiber.StatusTooManyRequests)
+				}
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}})
+			app.Get("/", func(c *fiber.Ctx) error {
+				c.Locals("userID", int64(1))
+				return c.Next()
+			}, m.MeterRequests, func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			var status int
+			var header func(string) string
+			for i := 0; i < tt.requests; i++ {
+				r, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+				if err != nil {
+					t.Fatal(err)
+				}
+				status, header = r.StatusCode, r.Header.Get
+			}
+
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, error = %v, want %d", status, rendered, tt.wantStatus)
+			}
+			if tt.wantStatus == fiber.StatusTooManyRequests && errors.ErrorKey(rendered) != "usage.requests_exceeded" {
+				t.Errorf("error = %v, want usage.requests_exceeded", rendered)
+			}
+
+			if tt.limit == nil {
+				if got := header("X-Quota-Limit"); got != "" {
+					t.Errorf("X-Quota-Limit = %q, want no quota headers", got)
+				}
+				return
+			}
+			if got := header("X-Quota-Limit"); got != "2" {
+				t.Errorf("X-Quota-Limit = %q, want 2", got)
+			}
+			if got := header("X-Quota-Remaining"); got != tt.wantRemaining {
+				t.Errorf("X-Quota-Remaining = %q, want %s", got, tt.wantRemaining)
+			}
+			if got := header("X-Quota-Reset"); got != reset {
+				t.Errorf("X-Quota-Reset = %q, want %s", got, reset)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/ratelimit"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type RateLimitMiddleware struct {
+	security     *security.Service
+	errorHandler func(c *fiber.Ctx, err error) error
+	enabled      bool
+	store        ratelimit.Store
+	window       time.Duration
+	globalLimit  int
+	authLimit    int
+	planLimits   map[string]int
+	groupLimits  map[string]int
+	whitelist    []string
+	logger       logger.Logger
+}
+
+func NewRateLimitMiddleware(
+	cfg *config.Config,
+	store ratelimit.Store,
+	securityService *security.Service,
+	errorHandler func(c *fiber.Ctx, err error) error,
+	log logger.Logger,
+) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		security:     securityService,
+		errorHandler: errorHandler,
+		enabled:      cfg.RateLimit.Enabled,
+		store:        store,
+		window:       time.Duration(cfg.RateLimit.WindowSeconds) * time.Second,
+		globalLimit:  cfg.RateLimit.GlobalLimit,
+		authLimit:    cfg.RateLimit.AuthLimit,
+		planLimits:   cfg.RateLimit.PlanLimits,
+		groupLimits:  cfg.RateLimit.GroupLimits,
+		whitelist:    cfg.RateLimit.WhitelistIPs,
+		logger:       log,
+	}
+}
+
+// Global limits every request per client IP.
+func (m *RateLimitMiddleware) Global(c *fiber.Ctx) error {
+	if m.skip(c) {
+		return c.Next()
+	}
+	if !m.take(c, "rl:global:ip:"+c.IP(), m.globalLimit) {
+		return m.reject(c)
+	}
+	return c.Next()
+}
+
+// ByIP limits the public routes of a group per client IP, using the group
+// limit or the auth limit.
+func (m *RateLimitMiddleware) ByIP(group string) fiber.Handler {
+	limit := m.authLimit
+	if groupLimit, ok := m.groupLimits[group]; ok {
+		limit = groupLimit
+	}
+
+	return func(c *fiber.Ctx) error {
+		if m.skip(c) {
+			return c.Next()
+		}
+		if !m.take(c, "rl:"+group+":ip:"+c.IP(), limit) {
+			return m.reject(c)
+		}
+		return c.Next()
+	}
+}
+
+// ByUser limits the routes of a group per authenticated user with the limit
+// of their plan, capped by the group limit. The plan is part of the key, so
+// an upgrade starts with a full bucket. Rejected requests are reported as
+// suspicious activity. Must run after Authenticate.
+func (m *RateLimitMiddleware) ByUser(group string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("userID").(int64)
+		if !ok || userID == 0 || m.skip(c) {
+			return c.Next()
+		}
+
+		plan, _ := c.Locals("userPlan").(string)
+		limit, ok := m.planLimits[strings.ToUpper(plan)]
+		if !ok {
+			limit = m.planLimits["FREE"]
+		}
+		if groupLimit, ok := m.groupLimits[group]; ok && groupLimit < limit {
+			limit = groupLimit
+		}
+
+		key := "rl:" + group + ":user:" + strconv.FormatInt(userID, 10) + ":" + strings.ToUpper(plan)
+		if !m.take(c, key, limit) {
+			m.security.Report(newActivity(c, userID, security.ActivityRateLimitExceeded, security.Details{
+				"group": group,
+				"limit": limit,
+			}))
+			return m.reject(c)
+		}
+		return c.Next()
+	}
+}
+
+func (m *RateLimitMiddleware) skip(c *fiber.Ctx) bool {
+	return !m.enabled || slices.Contains(m.whitelist, c.IP())
+}
+
+// take consumes one request from the bucket and reports whether the request
+// may go on.
+func (m *RateLimitMiddleware) take(c *fiber.Ctx, key string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	result, err := m.store.Take(c.UserContext(), key, limit, m.window)
+	if err != nil {
+		// Fail open: an unavailable store must not take the API down.
+		m.logger.Warn("Rate limit store unavailable", zap.String("key", key), zap.Error(err))
+		return true
+	}
+
+	c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Set("RateLimit-Reset", strconv.Itoa(seconds(result.ResetAfter)))
+
+	if !result.Allowed {
+		c.Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+		return false
+	}
+
+	return true
+}
+
+// reject writes the 429 response itself, so a rejection never reaches the
+// handlers and does not depend on how the app renders returned errors.
+func (m *RateLimitMiddleware) reject(c *fiber.Ctx) error {
+	return m.errorHandler(c, errors.New(errors.ERATELIMIT, "rate_limit.exceeded"))
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/ratelimit"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+)
+
+func TestRateLimitByIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.RateLimitConfig
+		requests   int
+		wantStatus int
+	}{
+		{"within limit", config.RateLimitConfig{Enabled: true, WindowSeconds: 60, AuthLimit: 2}, 2, fiber.StatusOK},
+		{"over limit", config.RateLimitConfig{Enabled: true, WindowSeconds: 60, AuthLimit: 2}, 3, fiber.StatusTooManyRequests},
+		{"group limit replaces auth limit", config.RateLimitConfig{Enabled: true, WindowSeconds: 60, AuthLimit: 2, GroupLimits: map[string]int{"login": 1}}, 2, fiber.StatusTooManyRequests},
+		{"disabled", config.RateLimitConfig{WindowSeconds: 60, AuthLimit: 1}, 3, fiber.StatusOK},
+		{"whitelisted ip", config.RateLimitConfig{Enabled: true, WindowSeconds: 60, AuthLimit: 1, WhitelistIPs: []string{"0.0.0.0"}}, 3, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rendered error
+			errorHandler := func(c *fiber.Ctx, err error) error {
+				rendered = err
+				return c.SendStatus(fiber.StatusTooManyRequests)
+			}
+			log, _ := logger.NewLogger("test", "none")
+			m := NewRateLimitMiddleware(&config.Config{RateLimit: tt.cfg}, ratelimit.NewMemoryStore(), nil, errorHandler, log)
+
+			// The app has no error handler: a rejection must be rendered by
+			// the middleware itself.
+			app := fiber.New()
+			app.Post("/login", m.ByIP("login"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			var status int
+			var retryAfter string
+			for i := 0; i < tt.requests; i++ {
+				resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/login", nil))
+				if err != nil {
+					t.Fatal(err)
+				}
+				status, retryAfter = resp.StatusCode, resp.Header.Get("Retry-After")
+			}
+
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus == fiber.StatusTooManyRequests {
+				if errors.ErrorKey(rendered) != "rate_limit.exceeded" {
+					t.Errorf("error = %v, want rate_limit.exceeded", rendered)
+				}
+				if retryAfter == "" {
+					t.Error("Retry-After header is missing")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lkgiovani/go-boilerplate/infra/config"
+	"github.com/lkgiovani/go-boilerplate/internal/domain/security"
+	"github.com/lkgiovani/go-boilerplate/internal/errors"
+	"github.com/lkgiovani/go-boilerplate/internal/security/ratelimit"
+	"github.com/lkgiovani/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SecurityMiddleware feeds the suspicious-activity detection. It never
+// rejects requests itself: blocking is decided by the security service.
+type SecurityMiddleware struct {
+	security     *security.Service
+	store        ratelimit.Store
+	window       time.Duration
+	maxRequests  int
+	massCreation int
+	invalidData  int
+	logger       logger.Logger
+}
+
+func NewSecurityMiddleware(cfg *config.Config, securityService *security.Service, store ratelimit.Store, log logger.Logger) *SecurityMiddleware {
+	return &SecurityMiddleware{
+		security:     securityService,
+		store:        store,
+		window:       time.Duration(cfg.Security.Suspicious.WindowMinutes) * time.Minute,
+		maxRequests:  cfg.Security.Suspicious.MaxRequestsPerWindow,
+		massCreation: cfg.Security.Suspicious.MassCreationThreshold,
+		invalidData:  cfg.Security.Suspicious.InvalidDataThreshold,
+		logger:       log,
+	}
+}
+
+// Monitor reports users who send more requests than a person would in the
+// detection window, or keep sending invalid data. Must run after
+// Authenticate.
+func (m *SecurityMiddleware) Monitor(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok || userID == 0 {
+		return c.Next()
+	}
+
+	m.track(c, userID, "requests", m.maxRequests, security.ActivityAutomatedBehavior)
+
+	err := c.Next()
+
+	// Handlers either return the error or write it through the error handler
+	code := errors.ErrorCode(err)
+	if code == errors.EINVALID || code == errors.EBADREQUEST || c.Response().StatusCode() == fiber.StatusBadRequest {
+		m.track(c, userID, "invalid", m.invalidData, security.ActivityInvalidDataAttempts)
+	}
+	return err
+}
+
+// TrackCreations reports users who create resources faster than the mass
+// creation threshold. Only successful responses are counted.
+func (m *SecurityMiddleware) TrackCreations(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		return err
+	}
+
+	userID, ok := c.Locals("userID").(int64)
+	if ok && userID != 0 && c.Response().StatusCode() < fiber.StatusMultipleChoices {
+		m.track(c, userID, "creations", m.massCreation, security.ActivityMassCreation)
+	}
+	return nil
+}
+
+func (m *SecurityMiddleware) track(c *fiber.Ctx, userID int64, counter string, limit int, activityType security.ActivityType) {
+	if limit <= 0 {
+		return
+	}
+
+	key := "sec:" + counter + ":user:" + strconv.FormatInt(userID, 10)
+	result, err := m.store.Take(c.UserContext(), key, limit, m.window)
+	if err != nil {
+		m.logger.Warn("Security counter store unavailable", zap.String("key", key), zap.Error(err))
+		return
+	}
+
+	if !result.Allowed {
+		m.security.Report(newActivity(c, userID, activityType, security.Details{
+			"limit":         limit,
+			"windowMinutes": int(m.window.Minutes()),
+		}))
+	}
+}
+
+// newActivity describes the current request. Values are copied since the
+// activity is recorded after the request context is recycled.
+func newActivity(c *fiber.Ctx, userID int64, activityType security.ActivityType, details security.Details) security.SuspiciousActivity {
+	endpoint := c.Method() + " " + c.Path()
+	if len(endpoint) > 500 {
+		endpoint = endpoint[:500]
+	}
+	ip := strings.Clone(c.IP())
+	userAgent := strings.Clone(c.Get(fiber.HeaderUserAgent))
+
+	return security.SuspiciousActivity{
+		UserID:       userID,
+		ActivityType: activityType,
+		Endpoint:     strings.Clone(endpoint),
+		IPAddress:    &ip,
+		UserAgent:    &userAgent,
+		Details:      details,
+	}
+}
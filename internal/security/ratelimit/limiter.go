@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result describes the state of a key after a request was counted.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Store counts requests per key with the GCRA token bucket: a key may send
+// up to limit requests at once, and the bucket refills one request every
+// window/limit.
+type Store interface {
+	Take(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// gcra applies one request to the theoretical arrival time tat and returns
+// the new tat to store (zero when the request is rejected).
+func gcra(now, tat time.Time, limit int, window time.Duration) (Result, time.Time) {
+	interval := window / time.Duration(limit)
+	if tat.Before(now) {
+		tat = now
+	}
+
+	next := tat.Add(interval)
+	allowAt := next.Add(-window)
+	if now.Before(allowAt) {
+		return Result{
+			Limit:      limit,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}, time.Time{}
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      limit,
+		Remaining:  int((window - next.Sub(now)) / interval),
+		ResetAfter: next.Sub(now),
+	}, next
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGCRA(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	tests := []struct {
+		name          string
+		tat           time.Time
+		limit         int
+		wantAllowed   bool
+		wantRemaining int
+		wantReset     time.Duration
+		wantRetry     time.Duration
+		wantTat       time.Time
+	}{
+		{"empty bucket", time.Time{}, 10, true, 9, 6 * time.Second, 0, now.Add(6 * time.Second)},
+		{"tat in the past counts as full", now.Add(-time.Hour), 10, true, 9, 6 * time.Second, 0, now.Add(6 * time.Second)},
+		{"partly used", now.Add(30 * time.Second), 10, true, 4, 36 * time.Second, 0, now.Add(36 * time.Second)},
+		{"last request of the burst", now.Add(54 * time.Second), 10, true, 0, time.Minute, 0, now.Add(time.Minute)},
+		{"exhausted", now.Add(time.Minute), 10, false, 0, time.Minute, 6 * time.Second, time.Time{}},
+		{"exhausted with partial refill", now.Add(57 * time.Second), 10, false, 0, 57 * time.Second, 3 * time.Second, time.Time{}},
+		{"limit of one", time.Time{}, 1, true, 0, time.Minute, 0, now.Add(time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, tat := gcra(now, tt.tat, tt.limit, window)
+
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v", result.Allowed, tt.wantAllowed)
+			}
+			if result.Limit != tt.limit {
+				t.Errorf("Limit = %d, want %d", result.Limit, tt.limit)
+			}
+			if result.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", result.Remaining, tt.wantRemaining)
+			}
+			if result.ResetAfter != tt.wantReset {
+				t.Errorf("ResetAfter = %v, want %v", result.ResetAfter, tt.wantReset)
+			}
+			if result.RetryAfter != tt.wantRetry {
+				t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, tt.wantRetry)
+			}
+			if !tat.Equal(tt.wantTat) {
+				t.Errorf("tat = %v, want %v", tat, tt.wantTat)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreBurstAndRefill(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	steps := []struct {
+		name        string
+		advance     time.Duration
+		key         string
+		wantAllowed bool
+	}{
+		{"first", 0, "a", true},
+		{"second", 0, "a", true},
+		{"third", 0, "a", true},
+		{"burst exhausted", 0, "a", false},
+		{"other key has its own bucket", 0, "b", true},
+		{"not refilled yet", 19 * time.Second, "a", false},
+		{"one request refilled", time.Second, "a", true},
+		{"refill used", 0, "a", false},
+		{"full after the window", 2 * time.Minute, "a", true},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		result, err := store.Take(ctx, step.key, 3, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: Take() error = %v", step.name, err)
+		}
+		if result.Allowed != step.wantAllowed {
+			t.Fatalf("%s: Allowed = %v, want %v", step.name, result.Allowed, step.wantAllowed)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	if _, err := store.Take(context.Background(), "a", 10, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * sweepInterval)
+	if _, err := store.Take(context.Background(), "b", 10, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := store.tats["a"]; ok {
+		t.Error("full bucket of key a was not swept")
+	}
+	if _, ok := store.tats["b"]; !ok {
+		t.Error("bucket of key b is missing")
+	}
+}
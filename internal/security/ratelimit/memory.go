@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lkgiovani/go-boilerplate/pkg/utils"
+)
+
+const sweepInterval = time.Minute
+
+// MemoryStore keeps the buckets in the process. Limits are per instance, so
+// it only fits single-instance deployments.
+type MemoryStore struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tats: make(map[string]time.Time),
+		now:  utils.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	result, tat := gcra(now, s.tats[key], limit, window)
+	if result.Allowed {
+		s.tats[key] = tat
+	}
+	return result, nil
+}
+
+// sweep drops the buckets that are full again, which behave the same as
+// missing ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, key)
+		}
+	}
+}
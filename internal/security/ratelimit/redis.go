@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript runs the GCRA step atomically on the Redis clock so that every
+// instance shares the same buckets. Times are in microseconds.
+var takeScript = redis.NewScript(`
+local now = redis.call("TIME")
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+local window = tonumber(ARGV[2])
+local interval = math.floor(window / tonumber(ARGV[1]))
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local nextTat = tat + interval
+local allowAt = nextTat - window
+if now < allowAt then
+	return {0, tat - now, allowAt - now}
+end
+
+redis.call("SET", KEYS[1], nextTat, "PX", math.ceil((nextTat - now) / 1000))
+return {1, nextTat - now, 0}
+`)
+
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	values, err := takeScript.Run(ctx, s.client, []string{key}, limit, window.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		ResetAfter: time.Duration(values[1]) * time.Microsecond,
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}
+	if result.Allowed {
+		interval := window / time.Duration(limit)
+		result.Remaining = int((window - result.ResetAfter) / interval)
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+-- Roles and Permissions
+-- V10: Role-based access control replacing the users.admin flag
+
+CREATE TABLE IF NOT EXISTS roles (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL,
+    permission_id BIGINT NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id BIGINT NOT NULL,
+    role_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role_id ON user_roles(role_id);
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_id ON role_permissions(permission_id);
+
+INSERT INTO roles (name, description) VALUES
+    ('user', 'Standard user'),
+    ('support', 'Support team: reads users and changes their status'),
+    ('billing', 'Billing team: reads users and manages plans'),
+    ('admin', 'Administrator with full access')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'List and read users'),
+    ('users:create', 'Create users and manage invitations'),
+    ('users:update', 'Update user data'),
+    ('users:delete', 'Delete users'),
+    ('users:status', 'Activate, deactivate and change the access mode'),
+    ('users:credentials', 'Reset passwords and force password changes'),
+    ('users:plan', 'Manage plan, features and limits'),
+    ('roles:manage', 'Read and assign roles')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON
+    (r.name = 'admin')
+    OR (r.name = 'support' AND p.name IN ('users:read', 'users:status'))
+    OR (r.name = 'billing' AND p.name IN ('users:read', 'users:plan'))
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id
+FROM users u
+JOIN roles r ON r.name = 'user'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id
+FROM users u
+JOIN roles r ON r.name = 'admin'
+WHERE u.admin = TRUE
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE roles IS 'Roles that can be assigned to users';
+COMMENT ON TABLE permissions IS 'Fine-grained permissions in the resource:action format';
+COMMENT ON TABLE role_permissions IS 'Permissions granted to each role';
+COMMENT ON TABLE user_roles IS 'Roles assigned to each user; users.admin is kept in sync with the admin role';
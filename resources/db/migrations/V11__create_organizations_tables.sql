@@ -0,0 +1,94 @@
+-- Organizations
+-- V11: Multi-tenant workspaces with members, invitations and organization-level plans
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    personal BOOLEAN NOT NULL DEFAULT FALSE,
+    owner_id BIGINT NOT NULL,
+    plan JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_organizations_owner
+        FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT fk_organization_members_organization
+        FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_organization_members_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_organization_members_role
+        CHECK (role IN ('owner', 'admin', 'member'))
+);
+
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member',
+    token VARCHAR(255) NOT NULL UNIQUE,
+    invited_by BIGINT,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_organization_invitations_organization
+        FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_organization_invitations_invited_by
+        FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Only one personal organization per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_personal_owner ON organizations(owner_id) WHERE personal = TRUE;
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_organization_id ON organization_invitations(organization_id);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_email ON organization_invitations(LOWER(email));
+
+-- Active organization of each session
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS organization_id BIGINT;
+
+-- Backfill a personal organization for every existing user, copying the plan keys from users.metadata
+INSERT INTO organizations (name, slug, personal, owner_id, plan, created_at, updated_at)
+SELECT
+    u.name,
+    'personal-' || u.id,
+    TRUE,
+    u.id,
+    COALESCE((
+        SELECT jsonb_object_agg(m.key, m.value)
+        FROM jsonb_each(u.metadata) m
+        WHERE m.key IN (
+            'plan_type', 'plan_expiration_date', 'pro_source', 'max_resources', 'max_requests_per_month',
+            'max_accounts', 'max_categories_per_account', 'max_transactions_per_month',
+            'can_export_data', 'can_use_reports', 'can_use_advanced_features', 'can_create_budgets', 'can_use_goals'
+        )
+    ), '{}'::jsonb),
+    CURRENT_TIMESTAMP,
+    CURRENT_TIMESTAMP
+FROM users u
+WHERE NOT EXISTS (
+    SELECT 1 FROM organizations o WHERE o.owner_id = u.id AND o.personal = TRUE
+);
+
+INSERT INTO organization_members (organization_id, user_id, role, created_at)
+SELECT o.id, o.owner_id, 'owner', CURRENT_TIMESTAMP
+FROM organizations o
+WHERE o.personal = TRUE
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE organizations IS 'Workspaces (tenants); every user owns a personal organization';
+COMMENT ON COLUMN organizations.personal IS 'Personal organizations follow the owner plan and do not accept new members';
+COMMENT ON COLUMN organizations.plan IS 'Organization-level plan metadata (plan type, limits and features)';
+COMMENT ON TABLE organization_members IS 'Organization membership with owner/admin/member role';
+COMMENT ON TABLE organization_invitations IS 'Email invitations to join an organization';
+COMMENT ON COLUMN refresh_tokens.organization_id IS 'Active organization bound to the session';
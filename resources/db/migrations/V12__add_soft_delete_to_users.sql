@@ -0,0 +1,13 @@
+-- Users Soft Delete
+-- V12: Soft deletion with a restoration grace period before the account is purged
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS purge_after TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_users_purge_after ON users(purge_after) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN users.deleted_at IS 'Soft deletion timestamp; deleted users are excluded from every query';
+COMMENT ON COLUMN users.deleted_by IS 'User who deleted the account (equal to id for self-service deletions, which can be restored by logging in)';
+COMMENT ON COLUMN users.purge_after IS 'When the background purge permanently removes the account, its files and sessions';
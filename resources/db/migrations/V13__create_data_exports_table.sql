@@ -0,0 +1,26 @@
+-- Data Exports
+-- V13: Self-service personal data exports (LGPD/GDPR)
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    storage_key VARCHAR(500),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+
+    CONSTRAINT fk_data_exports_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_data_exports_status
+        CHECK (status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'FAILED', 'EXPIRED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id_created_at ON data_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports(expires_at) WHERE status = 'COMPLETED';
+
+COMMENT ON TABLE data_exports IS 'Personal data export requests; the ZIP archive is kept in storage until the download link expires';
+COMMENT ON COLUMN data_exports.storage_key IS 'Storage key of the ZIP archive (cleared once the export expires)';
+COMMENT ON COLUMN data_exports.expires_at IS 'When the presigned download link expires and the archive is removed';
@@ -0,0 +1,24 @@
+-- Email Change Requests
+-- V14: Pending email changes confirmed through the new address and cancellable from the old one
+
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    old_email VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    cancel_token VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_email_change_requests_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_change_requests_user_id ON email_change_requests(user_id);
+
+COMMENT ON TABLE email_change_requests IS 'Pending email changes; the new address is applied only after confirmation';
+COMMENT ON COLUMN email_change_requests.token IS 'Confirmation token sent to the new address';
+COMMENT ON COLUMN email_change_requests.cancel_token IS 'Cancellation token sent to the previous address';
@@ -0,0 +1,15 @@
+-- User List Filter Indexes
+-- V15: Indexes backing the admin user list filters, sort fields and keyset pagination
+
+-- JSONB metadata fields (expressions must match the ones used by the repository)
+CREATE INDEX IF NOT EXISTS idx_users_metadata_plan_type ON users ((metadata->>'plan_type'));
+CREATE INDEX IF NOT EXISTS idx_users_metadata_access_mode ON users ((metadata->>'access_mode'));
+CREATE INDEX IF NOT EXISTS idx_users_metadata_reputation_status ON users ((metadata->>'reputation_status'));
+CREATE INDEX IF NOT EXISTS idx_users_metadata_email_verified ON users ((metadata->>'email_verified'));
+
+-- Range filters and keyset pagination (sort column + id tie-breaker)
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_last_access_id ON users((COALESCE(last_access, '1970-01-01'::timestamp)), id);
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users(name, id);
+CREATE INDEX IF NOT EXISTS idx_users_source ON users(source);
+CREATE INDEX IF NOT EXISTS idx_users_admin ON users(admin);
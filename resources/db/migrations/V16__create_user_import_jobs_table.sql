@@ -0,0 +1,45 @@
+-- User Import Jobs
+-- V16: Asynchronous bulk user import (CSV / JSON lines)
+
+CREATE TABLE IF NOT EXISTS user_import_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    created_by BIGINT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    send_invitations BOOLEAN NOT NULL DEFAULT FALSE,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    valid_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+
+    CONSTRAINT fk_user_import_jobs_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_import_jobs_format
+        CHECK (format IN ('csv', 'jsonl')),
+    CONSTRAINT chk_user_import_jobs_status
+        CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_import_jobs_created_by ON user_import_jobs(created_by);
+
+COMMENT ON TABLE user_import_jobs IS 'Bulk user imports started by administrators, with progress and per-row errors';
+COMMENT ON COLUMN user_import_jobs.dry_run IS 'When true the file is only validated and no user is created';
+COMMENT ON COLUMN user_import_jobs.errors IS 'Per-row validation and creation errors: [{row, email, message}]';
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:import', 'Bulk import users')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'users:import'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
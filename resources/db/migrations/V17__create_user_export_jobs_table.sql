@@ -0,0 +1,43 @@
+-- User Export Jobs
+-- V17: Asynchronous admin user exports (CSV / XLSX)
+
+CREATE TABLE IF NOT EXISTS user_export_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    created_by BIGINT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    columns TEXT NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    row_count BIGINT NOT NULL DEFAULT 0,
+    storage_key VARCHAR(500),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+
+    CONSTRAINT fk_user_export_jobs_created_by
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_export_jobs_format
+        CHECK (format IN ('csv', 'xlsx')),
+    CONSTRAINT chk_user_export_jobs_status
+        CHECK (status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'FAILED', 'EXPIRED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_export_jobs_created_by ON user_export_jobs(created_by);
+CREATE INDEX IF NOT EXISTS idx_user_export_jobs_expires_at ON user_export_jobs(expires_at) WHERE status = 'COMPLETED';
+
+COMMENT ON TABLE user_export_jobs IS 'Admin user exports produced in background and delivered as a presigned link';
+COMMENT ON COLUMN user_export_jobs.columns IS 'Comma-separated list of exported columns';
+COMMENT ON COLUMN user_export_jobs.query IS 'Query string (filters) the export was requested with';
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:export', 'Export user lists')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'users:export'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
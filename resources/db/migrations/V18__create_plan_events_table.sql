@@ -0,0 +1,33 @@
+-- Plan Events
+-- V18: Plan transition history (trials, expirations, admin grants) and expiration reminders
+
+CREATE TABLE IF NOT EXISTS plan_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    from_plan VARCHAR(20),
+    to_plan VARCHAR(20),
+    pro_source VARCHAR(20),
+    expires_at TIMESTAMP,
+    actor_id BIGINT,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_plan_events_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_plan_events_actor
+        FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT chk_plan_events_type
+        CHECK (type IN ('TRIAL_STARTED', 'EXPIRED', 'REMINDER_SENT', 'GRANTED', 'REVOKED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_plan_events_user_id_created_at ON plan_events(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_plan_events_user_id_type ON plan_events(user_id, type);
+
+-- Supports the lifecycle job, which only looks at plans with an expiration date
+CREATE INDEX IF NOT EXISTS idx_users_plan_expiration_date ON users ((metadata->>'plan_expiration_date'))
+    WHERE metadata->>'plan_expiration_date' IS NOT NULL;
+
+COMMENT ON TABLE plan_events IS 'Plan transitions of each user and the expiration reminders sent';
+COMMENT ON COLUMN plan_events.expires_at IS 'Plan expiration date the event refers to (trial end, expired date or reminded date)';
+COMMENT ON COLUMN plan_events.actor_id IS 'User who triggered the transition; NULL for the scheduled lifecycle job';
@@ -0,0 +1,55 @@
+-- Billing
+-- V19: Subscriptions synced from payment provider webhooks and the idempotent event log
+
+CREATE TABLE IF NOT EXISTS billing_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    provider VARCHAR(30) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL DEFAULT '',
+    subscription_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    plan_type VARCHAR(20) NOT NULL,
+    current_period_end TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+
+    CONSTRAINT fk_billing_subscriptions_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_billing_subscriptions_status
+        CHECK (status IN ('ACTIVE', 'PAST_DUE', 'CANCELLED'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_billing_subscriptions_provider_subscription ON billing_subscriptions(provider, subscription_id);
+CREATE INDEX IF NOT EXISTS idx_billing_subscriptions_user_id ON billing_subscriptions(user_id);
+
+CREATE TABLE IF NOT EXISTS billing_events (
+    id BIGSERIAL PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    user_id BIGINT,
+    error TEXT,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP,
+
+    CONSTRAINT chk_billing_events_status
+        CHECK (status IN ('PROCESSED', 'IGNORED', 'FAILED'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_billing_events_provider_event ON billing_events(provider, event_id);
+CREATE INDEX IF NOT EXISTS idx_billing_events_user_id ON billing_events(user_id);
+
+-- Subscription transitions are recorded in the plan history
+ALTER TABLE plan_events DROP CONSTRAINT IF EXISTS chk_plan_events_type;
+ALTER TABLE plan_events ADD CONSTRAINT chk_plan_events_type
+    CHECK (type IN (
+        'TRIAL_STARTED', 'EXPIRED', 'REMINDER_SENT', 'GRANTED', 'REVOKED',
+        'SUBSCRIPTION_STARTED', 'SUBSCRIPTION_RENEWED', 'SUBSCRIPTION_PAST_DUE', 'SUBSCRIPTION_CANCELLED'
+    ));
+
+COMMENT ON TABLE billing_subscriptions IS 'Subscriptions at payment providers, kept in sync by webhooks';
+COMMENT ON TABLE billing_events IS 'Every verified provider webhook; (provider, event_id) makes redeliveries idempotent';
+COMMENT ON COLUMN billing_events.user_id IS 'User resolved for the event; not a foreign key so the log keeps events of unknown or deleted users';
+COMMENT ON COLUMN billing_events.status IS 'PROCESSED, IGNORED (not related to a known subscription or plan) or FAILED (retried on redelivery)';
@@ -0,0 +1,49 @@
+-- Plans
+-- V20: Plan catalog (rank, limits and features of each plan)
+
+CREATE TABLE IF NOT EXISTS plans (
+    code VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rank INT NOT NULL,
+    max_accounts INT NOT NULL,
+    max_categories_per_account INT NOT NULL,
+    max_transactions_per_month INT NOT NULL,
+    max_resources INT,
+    max_requests_per_month INT,
+    can_export_data BOOLEAN NOT NULL DEFAULT FALSE,
+    can_use_reports BOOLEAN NOT NULL DEFAULT FALSE,
+    can_use_advanced_features BOOLEAN NOT NULL DEFAULT FALSE,
+    can_create_budgets BOOLEAN NOT NULL DEFAULT FALSE,
+    can_use_goals BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_plans_limits
+        CHECK (max_accounts >= 0 AND max_categories_per_account >= 0 AND max_transactions_per_month >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plans_rank ON plans(rank);
+
+COMMENT ON TABLE plans IS 'Plan catalog; users get the limits and features of their plan plus their own overrides';
+COMMENT ON COLUMN plans.rank IS 'Plan order used by minimum plan checks (higher includes lower)';
+COMMENT ON COLUMN plans.max_resources IS 'NULL means unlimited';
+COMMENT ON COLUMN plans.max_requests_per_month IS 'NULL means unlimited';
+
+INSERT INTO plans (code, name, rank, max_accounts, max_categories_per_account, max_transactions_per_month,
+                   can_export_data, can_use_reports, can_use_advanced_features, can_create_budgets, can_use_goals)
+VALUES
+    ('FREE', 'Free', 1, 5, 20, 200, FALSE, FALSE, FALSE, TRUE, FALSE),
+    ('PRO', 'Pro', 2, 20, 100, 5000, TRUE, TRUE, FALSE, TRUE, TRUE),
+    ('ENTERPRISE', 'Enterprise', 3, 100, 500, 50000, TRUE, TRUE, TRUE, TRUE, TRUE)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('plans:manage', 'Manage the plan catalog')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'plans:manage'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
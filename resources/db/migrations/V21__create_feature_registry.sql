@@ -0,0 +1,94 @@
+-- Feature Registry
+-- V21: Feature keys enabled per plan, replacing the fixed can_* flags
+
+CREATE TABLE IF NOT EXISTS features (
+    key VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS plan_features (
+    plan_code VARCHAR(20) NOT NULL,
+    feature_key VARCHAR(50) NOT NULL,
+
+    PRIMARY KEY (plan_code, feature_key),
+    CONSTRAINT fk_plan_features_plan
+        FOREIGN KEY (plan_code) REFERENCES plans(code) ON DELETE CASCADE,
+    CONSTRAINT fk_plan_features_feature
+        FOREIGN KEY (feature_key) REFERENCES features(key) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_plan_features_feature_key ON plan_features(feature_key);
+
+COMMENT ON TABLE features IS 'Feature registry; keys are checked by RequireFeature and may be overridden per user';
+COMMENT ON TABLE plan_features IS 'Features enabled by default for each plan';
+
+INSERT INTO features (key, description) VALUES
+    ('export', 'Data export'),
+    ('reports', 'Reports'),
+    ('advanced_features', 'Advanced features'),
+    ('budgets', 'Budgets'),
+    ('goals', 'Goals')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO plan_features (plan_code, feature_key)
+SELECT p.code, f.key
+FROM plans p
+JOIN (VALUES
+    ('export', 'can_export_data'),
+    ('reports', 'can_use_reports'),
+    ('advanced_features', 'can_use_advanced_features'),
+    ('budgets', 'can_create_budgets'),
+    ('goals', 'can_use_goals')
+) AS f(key, flag) ON (to_jsonb(p)->>f.flag)::boolean
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE plans
+    DROP COLUMN IF EXISTS can_export_data,
+    DROP COLUMN IF EXISTS can_use_reports,
+    DROP COLUMN IF EXISTS can_use_advanced_features,
+    DROP COLUMN IF EXISTS can_create_budgets,
+    DROP COLUMN IF EXISTS can_use_goals;
+
+-- Converts the can_* flags of a plan JSON into the list of enabled feature keys
+CREATE FUNCTION pg_temp.feature_keys(flags JSONB) RETURNS JSONB AS $$
+    SELECT COALESCE(jsonb_agg(f.key ORDER BY f.key), '[]'::jsonb)
+    FROM (VALUES
+        ('export', 'can_export_data'),
+        ('reports', 'can_use_reports'),
+        ('advanced_features', 'can_use_advanced_features'),
+        ('budgets', 'can_create_budgets'),
+        ('goals', 'can_use_goals')
+    ) AS f(key, flag)
+    WHERE (flags->>f.flag)::boolean
+$$ LANGUAGE SQL;
+
+-- Converts the can_* overrides into a feature key map (true grants, false denies)
+CREATE FUNCTION pg_temp.feature_overrides(flags JSONB) RETURNS JSONB AS $$
+    SELECT jsonb_object_agg(f.key, flags->f.flag)
+    FROM (VALUES
+        ('export', 'can_export_data'),
+        ('reports', 'can_use_reports'),
+        ('advanced_features', 'can_use_advanced_features'),
+        ('budgets', 'can_create_budgets'),
+        ('goals', 'can_use_goals')
+    ) AS f(key, flag)
+    WHERE flags ? f.flag
+$$ LANGUAGE SQL;
+
+UPDATE users
+SET metadata = (metadata - 'can_export_data' - 'can_use_reports' - 'can_use_advanced_features' - 'can_create_budgets' - 'can_use_goals')
+    || jsonb_build_object('features', pg_temp.feature_keys(metadata))
+WHERE NOT metadata ? 'features';
+
+UPDATE users
+SET metadata = jsonb_set(metadata, '{plan_overrides}',
+    (metadata->'plan_overrides' - 'can_export_data' - 'can_use_reports' - 'can_use_advanced_features' - 'can_create_budgets' - 'can_use_goals')
+    || jsonb_build_object('features', pg_temp.feature_overrides(metadata->'plan_overrides')))
+WHERE metadata->'plan_overrides' ?| ARRAY['can_export_data', 'can_use_reports', 'can_use_advanced_features', 'can_create_budgets', 'can_use_goals'];
+
+UPDATE organizations
+SET plan = (plan - 'can_export_data' - 'can_use_reports' - 'can_use_advanced_features' - 'can_create_budgets' - 'can_use_goals')
+    || jsonb_build_object('features', pg_temp.feature_keys(plan))
+WHERE NOT plan ? 'features';
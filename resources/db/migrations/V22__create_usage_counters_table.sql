@@ -0,0 +1,21 @@
+-- Usage Counters
+-- V22: Monthly usage metering (API requests and resource creations per user)
+
+CREATE TABLE IF NOT EXISTS usage_counters (
+    user_id BIGINT NOT NULL,
+    period_start DATE NOT NULL,
+    metric VARCHAR(20) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, period_start, metric),
+    CONSTRAINT chk_usage_counters_metric
+        CHECK (metric IN ('REQUESTS', 'RESOURCES'))
+);
+
+-- Supports the admin usage report, which lists a whole period
+CREATE INDEX IF NOT EXISTS idx_usage_counters_period_start ON usage_counters(period_start);
+
+COMMENT ON TABLE usage_counters IS 'Usage per user, billing period (calendar month, UTC) and metric; flushed from in-memory buffers';
+COMMENT ON COLUMN usage_counters.user_id IS 'No foreign key: buffered counters may be flushed after the user is purged';
+COMMENT ON COLUMN usage_counters.period_start IS 'First day of the month the usage belongs to';
@@ -0,0 +1,11 @@
+-- User Preferences
+-- V23: Timezone, notification settings and marketing consent in users.metadata
+
+-- Existing users get the same defaults as new ones; keys already present are kept
+UPDATE users
+SET metadata = jsonb_build_object(
+        'timezone', 'America/Sao_Paulo',
+        'notifications', jsonb_build_object('plan_reminders', true),
+        'marketing_consent', false
+    ) || metadata
+WHERE metadata IS NOT NULL AND NOT metadata ? 'timezone';
@@ -0,0 +1,20 @@
+-- Security Console
+-- V24: Records who created manual blocks and adds the security permissions
+
+ALTER TABLE user_security_blocks
+    ADD COLUMN blocked_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN user_security_blocks.blocked_by IS 'Admin who created the block; NULL for automatic blocks';
+
+INSERT INTO permissions (name, description) VALUES
+    ('security:read', 'Read suspicious activities and blocks'),
+    ('security:manage', 'Block and unblock users')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name IN ('security:read', 'security:manage')
+WHERE r.name = 'admin'
+   OR (r.name = 'support' AND p.name = 'security:read')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+-- User Versioning
+-- V25: Optimistic concurrency control on users (ETag / If-Match)
+
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN users.version IS 'Incremented on every change; exposed as ETag and checked through If-Match';
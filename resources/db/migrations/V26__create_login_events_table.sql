@@ -0,0 +1,27 @@
+-- Login Events
+-- V26: Sign-in history of each user (password, Google and token refresh attempts)
+
+CREATE TABLE IF NOT EXISTS login_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(100),
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    device_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_login_events_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_login_events_method
+        CHECK (method IN ('PASSWORD', 'GOOGLE', 'REFRESH'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_events_user_id_created_at ON login_events(user_id, created_at DESC);
+
+-- Supports the retention job
+CREATE INDEX IF NOT EXISTS idx_login_events_created_at ON login_events(created_at);
+
+COMMENT ON TABLE login_events IS 'Sign-in attempts of each user; removed after the retention period';
+COMMENT ON COLUMN login_events.failure_reason IS 'Message key of the error returned to the client; NULL for successful attempts';
@@ -0,0 +1,11 @@
+-- Users Purge Attempts
+-- V27: Failed purges move to the end of the queue instead of blocking every batch
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS purge_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_purge_attempt_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_users_purge_after;
+CREATE INDEX IF NOT EXISTS idx_users_purge_queue ON users(purge_attempts, purge_after) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN users.purge_attempts IS 'Failed purge attempts; accounts are purged in order of attempts, then purge_after';
+COMMENT ON COLUMN users.last_purge_attempt_at IS 'When the last failed purge attempt happened';
@@ -0,0 +1,9 @@
+-- Email Change Token Hashing
+-- V28: Confirmation and cancellation tokens are stored as SHA-256 hashes, like refresh tokens
+
+UPDATE email_change_requests
+SET token = encode(sha256(convert_to(token, 'UTF8')), 'hex'),
+    cancel_token = encode(sha256(convert_to(cancel_token, 'UTF8')), 'hex');
+
+COMMENT ON COLUMN email_change_requests.token IS 'SHA-256 hash of the confirmation token sent to the new address';
+COMMENT ON COLUMN email_change_requests.cancel_token IS 'SHA-256 hash of the cancellation token sent to the previous address';
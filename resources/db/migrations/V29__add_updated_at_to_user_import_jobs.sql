@@ -0,0 +1,9 @@
+-- User Import Job Progress
+-- V29: Last progress update, used to fail imports interrupted by a restart
+
+ALTER TABLE user_import_jobs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_user_import_jobs_unfinished ON user_import_jobs(updated_at) WHERE status IN ('PENDING', 'RUNNING');
+
+COMMENT ON COLUMN user_import_jobs.updated_at IS 'Last progress update; pending or running jobs without progress for 15 minutes are marked as failed';
+COMMENT ON COLUMN user_import_jobs.message IS 'Catalog key of the failure message';
@@ -0,0 +1,14 @@
+-- Trial Used Marker
+-- V30: The one PRO trial of each account is marked in the user metadata, in the same update that grants it
+
+UPDATE users u
+SET metadata = jsonb_set(COALESCE(u.metadata, '{}'::jsonb), '{trial_used_at}', to_jsonb(to_char(t.started_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'))),
+    version = u.version + 1
+FROM (
+    SELECT user_id, MIN(created_at) AS started_at
+    FROM plan_events
+    WHERE type = 'TRIAL_STARTED'
+    GROUP BY user_id
+) t
+WHERE u.id = t.user_id
+  AND u.metadata->>'trial_used_at' IS NULL;
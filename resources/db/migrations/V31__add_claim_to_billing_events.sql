@@ -0,0 +1,11 @@
+-- Billing Event Claim
+-- V31: Webhook events are claimed on (provider, event_id) before they are applied, so concurrent deliveries apply an event once
+
+ALTER TABLE billing_events ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+
+ALTER TABLE billing_events DROP CONSTRAINT IF EXISTS chk_billing_events_status;
+ALTER TABLE billing_events ADD CONSTRAINT chk_billing_events_status
+    CHECK (status IN ('PROCESSING', 'PROCESSED', 'IGNORED', 'FAILED'));
+
+COMMENT ON COLUMN billing_events.status IS 'PROCESSING (claimed by a delivery), PROCESSED, IGNORED (not related to a known subscription or plan) or FAILED (retried on redelivery)';
+COMMENT ON COLUMN billing_events.claimed_at IS 'When a delivery claimed the event; a PROCESSING claim older than a few minutes is taken over by the next delivery';
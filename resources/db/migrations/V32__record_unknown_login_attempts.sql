@@ -0,0 +1,8 @@
+-- Login Events Attempts
+-- V32: Failed attempts with an unknown email are recorded without a user, along with the submitted email
+
+ALTER TABLE login_events ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE login_events ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+
+COMMENT ON COLUMN login_events.user_id IS 'NULL when the submitted email does not belong to any user';
+COMMENT ON COLUMN login_events.email IS 'Email submitted with the attempt, or of the token owner on refresh';
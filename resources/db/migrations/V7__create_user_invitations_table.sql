@@ -0,0 +1,32 @@
+-- User Invitations Table
+-- V7: Create user_invitations table for admin-created accounts (set-password flow)
+
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    invited_by BIGINT,
+    expires_at TIMESTAMP NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_user_invitations_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_invitations_invited_by
+        FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Indexes for performance
+CREATE INDEX IF NOT EXISTS idx_user_invitations_token ON user_invitations(token);
+CREATE INDEX IF NOT EXISTS idx_user_invitations_user_id ON user_invitations(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_invitations_expires_at ON user_invitations(expires_at);
+
+-- Comments for documentation
+COMMENT ON TABLE user_invitations IS 'Single-use invitations sent to admin-created users so they can set their own password';
+COMMENT ON COLUMN user_invitations.token IS 'Unique secure token sent to the invited user email';
+COMMENT ON COLUMN user_invitations.invited_by IS 'Admin who created or resent the invitation';
+COMMENT ON COLUMN user_invitations.expires_at IS 'Invitation expiration timestamp (default 72 hours)';
+COMMENT ON COLUMN user_invitations.revoked_at IS 'Timestamp when the invitation was revoked or superseded by a resend';
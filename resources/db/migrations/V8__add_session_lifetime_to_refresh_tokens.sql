@@ -0,0 +1,20 @@
+-- Refresh Tokens Session Lifetime
+-- V8: Track client type and family start to enforce idle timeout and absolute session lifetime
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS client_type VARCHAR(20) NOT NULL DEFAULT 'web';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_created_at TIMESTAMP;
+
+UPDATE refresh_tokens rt
+SET family_created_at = f.first_created_at
+FROM (
+    SELECT family_id, MIN(created_at) AS first_created_at
+    FROM refresh_tokens
+    GROUP BY family_id
+) f
+WHERE rt.family_id = f.family_id
+  AND rt.family_created_at IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN family_created_at SET NOT NULL;
+
+COMMENT ON COLUMN refresh_tokens.client_type IS 'Client type that started the session (web, mobile)';
+COMMENT ON COLUMN refresh_tokens.family_created_at IS 'When the token family (session) was first created, used for the absolute lifetime limit';
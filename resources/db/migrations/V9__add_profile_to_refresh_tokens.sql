@@ -0,0 +1,8 @@
+-- Refresh Tokens Profile
+-- V9: Store the client token profile (web, web-remember, mobile, cli) used to issue each session
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS profile VARCHAR(30) NOT NULL DEFAULT 'web';
+
+UPDATE refresh_tokens SET profile = 'mobile' WHERE client_type = 'mobile';
+
+COMMENT ON COLUMN refresh_tokens.profile IS 'Token profile that defines access/refresh TTLs, cookie max-age and audience';